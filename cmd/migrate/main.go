@@ -3,9 +3,12 @@ package main
 import (
 	"log"
 
-	"github.com/LiteMove/light-stack/internal/config"
-	"github.com/LiteMove/light-stack/internal/model"
-	"github.com/LiteMove/light-stack/internal/utils"
+	fileModel "github.com/LiteMove/light-stack/internal/modules/files/model"
+	generatorModel "github.com/LiteMove/light-stack/internal/modules/generator/model"
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/internal/shared/config"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/internal/shared/utils"
 	"github.com/LiteMove/light-stack/pkg/database"
 	"github.com/LiteMove/light-stack/pkg/logger"
 )
@@ -26,6 +29,9 @@ func main() {
 
 	db := database.GetDB()
 
+	// 数据权限字段是否已存在，不存在时迁移后为内置角色设置默认数据权限
+	hasRoleDataScope := db.Migrator().HasColumn(&model.Role{}, "data_scope")
+
 	// 自动迁移数据库表
 	logger.Info("Starting database migration...")
 
@@ -34,11 +40,22 @@ func main() {
 		&model.Role{},
 		&model.Menu{},
 		&model.UserRole{},
+		&model.PasswordHistory{},
 		&model.RoleMenus{},
-		&model.File{},
-		&model.GenTableConfig{},
-		&model.GenTableColumn{},
-		&model.GenHistory{},
+		&model.Tenant{},
+		&model.TenantPackage{},
+		&model.TenantPackageMenu{},
+		&model.Dept{},
+		&model.Post{},
+		&model.UserPost{},
+		&fileModel.File{},
+		&fileModel.UploadSession{},
+		&fileModel.UploadSessionPart{},
+		&generatorModel.GenTableConfig{},
+		&generatorModel.GenTableColumn{},
+		&generatorModel.GenHistory{},
+		&sharedModel.OperationLog{},
+		&sharedModel.LoginLog{},
 	)
 
 	if err != nil {
@@ -47,12 +64,23 @@ func main() {
 
 	logger.Info("Database migration completed successfully!")
 
+	// 角色租户隔离迁移
+	migrateTenantRoles()
+
+	// 内置角色数据权限迁移
+	if !hasRoleDataScope {
+		migrateRoleDataScopes()
+	}
+
 	// 创建基础数据
 	createBasicData()
 
 	// 创建文件管理菜单
 	createFileManagementMenus()
 
+	// 创建管理接口权限码
+	createPermissionMenus()
+
 	logger.Info("Migration process finished!")
 }
 
@@ -80,6 +108,7 @@ func createBasicRoles() {
 			Status:      1,
 			IsSystem:    true,
 			SortOrder:   1,
+			DataScope:   sharedModel.DataScopeAll,
 		},
 		{
 			Name:        "租户管理员",
@@ -88,6 +117,7 @@ func createBasicRoles() {
 			Status:      1,
 			IsSystem:    true,
 			SortOrder:   2,
+			DataScope:   sharedModel.DataScopeTenant,
 		},
 		{
 			Name:        "普通用户",
@@ -96,12 +126,13 @@ func createBasicRoles() {
 			Status:      1,
 			IsSystem:    true,
 			SortOrder:   3,
+			DataScope:   sharedModel.DataScopeSelf,
 		},
 	}
 
 	for _, role := range roles {
 		var existingRole model.Role
-		result := db.Where("code = ?", role.Code).First(&existingRole)
+		result := db.Where("tenant_id = ? AND code = ?", model.SystemRoleTenantID, role.Code).First(&existingRole)
 		if result.Error != nil {
 			// 角色不存在，创建新角色
 			if err := db.Create(&role).Error; err != nil {
@@ -240,7 +271,7 @@ func assignFileMenuToRoles(menuID uint64) {
 
 	for _, roleCode := range roleCodes {
 		var role model.Role
-		if err := db.Where("code = ?", roleCode).First(&role).Error; err != nil {
+		if err := db.Where("tenant_id = ? AND code = ?", model.SystemRoleTenantID, roleCode).First(&role).Error; err != nil {
 			logger.Error("Failed to find role:", roleCode, err)
 			continue
 		}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/pkg/database"
+	"github.com/LiteMove/light-stack/pkg/logger"
+)
+
+// permissionGroup 挂在同一菜单下的一组权限码
+type permissionGroup struct {
+	parent      model.Menu // 所属菜单，按Code查找，不存在时创建
+	permissions []permissionSeed
+}
+
+// permissionSeed 权限码种子数据
+type permissionSeed struct {
+	code  string
+	name  string
+	roles []string // 新建时授予的角色（super_admin默认授予）
+}
+
+// legacyPermissionCodes 旧版权限码到新版权限码的映射
+var legacyPermissionCodes = map[string]string{
+	"role:list":     "system:role:list",
+	"role:create":   "system:role:create",
+	"role:update":   "system:role:update",
+	"role:delete":   "system:role:delete",
+	"menu:list":     "system:menu:list",
+	"menu:create":   "system:menu:create",
+	"menu:update":   "system:menu:update",
+	"menu:delete":   "system:menu:delete",
+	"tenant:list":   "system:tenant:list",
+	"tenant:create": "system:tenant:create",
+	"tenant:update": "system:tenant:update",
+	"tenant:delete": "system:tenant:delete",
+}
+
+// permissionGroups 管理接口使用的权限码，与路由中声明的权限码保持一致
+var permissionGroups = []permissionGroup{
+	{
+		parent: model.Menu{Name: "用户管理", Code: "system:user:menu", Type: "menu", Path: "/system/users", Component: "system/users/index", Icon: "user"},
+		permissions: []permissionSeed{
+			{code: "system:user:list", name: "用户管理-列表", roles: []string{"tenant_admin"}},
+			{code: "system:user:detail", name: "用户管理-详情", roles: []string{"tenant_admin"}},
+			{code: "system:user:create", name: "用户管理-创建", roles: []string{"tenant_admin"}},
+			{code: "system:user:update", name: "用户管理-更新", roles: []string{"tenant_admin"}},
+			{code: "system:user:delete", name: "用户管理-删除", roles: []string{"tenant_admin"}},
+			{code: "system:user:reset", name: "用户管理-重置密码", roles: []string{"tenant_admin"}},
+			{code: "system:user:role:assign", name: "用户角色-分配", roles: []string{"tenant_admin"}},
+			{code: "system:user:post:assign", name: "用户岗位-分配", roles: []string{"tenant_admin"}},
+		},
+	},
+	{
+		parent: model.Menu{Name: "角色管理", Code: "system:role:menu", Type: "menu", Path: "/system/roles", Component: "system/roles/index", Icon: "role"},
+		permissions: []permissionSeed{
+			{code: "system:role:list", name: "角色管理-查看", roles: []string{"tenant_admin"}},
+			{code: "system:role:create", name: "角色管理-创建", roles: []string{"tenant_admin"}},
+			{code: "system:role:update", name: "角色管理-更新", roles: []string{"tenant_admin"}},
+			{code: "system:role:delete", name: "角色管理-删除", roles: []string{"tenant_admin"}},
+			{code: "system:role:menu:assign", name: "角色菜单-分配", roles: []string{"tenant_admin"}},
+		},
+	},
+	{
+		parent: model.Menu{Name: "部门管理", Code: "system:dept:menu", Type: "menu", Path: "/system/depts", Component: "system/depts/index", Icon: "OfficeBuilding"},
+		permissions: []permissionSeed{
+			{code: "system:dept:list", name: "部门管理-查看", roles: []string{"tenant_admin"}},
+			{code: "system:dept:create", name: "部门管理-创建", roles: []string{"tenant_admin"}},
+			{code: "system:dept:update", name: "部门管理-更新", roles: []string{"tenant_admin"}},
+			{code: "system:dept:delete", name: "部门管理-删除", roles: []string{"tenant_admin"}},
+		},
+	},
+	{
+		parent: model.Menu{Name: "岗位管理", Code: "system:post:menu", Type: "menu", Path: "/system/posts", Component: "system/posts/index", Icon: "Postcard"},
+		permissions: []permissionSeed{
+			{code: "system:post:list", name: "岗位管理-查看", roles: []string{"tenant_admin"}},
+			{code: "system:post:create", name: "岗位管理-创建", roles: []string{"tenant_admin"}},
+			{code: "system:post:update", name: "岗位管理-更新", roles: []string{"tenant_admin"}},
+			{code: "system:post:delete", name: "岗位管理-删除", roles: []string{"tenant_admin"}},
+		},
+	},
+	{
+		parent: model.Menu{Name: "菜单权限", Code: "system:menu:menu", Type: "menu", Path: "/system/menus", Component: "system/menus/index", Icon: "menu"},
+		permissions: []permissionSeed{
+			{code: "system:menu:list", name: "菜单权限-查看"},
+			{code: "system:menu:create", name: "菜单权限-创建"},
+			{code: "system:menu:update", name: "菜单权限-更新"},
+			{code: "system:menu:delete", name: "菜单权限-删除"},
+		},
+	},
+	{
+		parent: model.Menu{Name: "字典管理", Code: "system:dict:menu", Type: "menu", Path: "/system/dicts", Component: "system/dicts/index", Icon: "Collection"},
+		permissions: []permissionSeed{
+			{code: "system:dict:list", name: "字典管理-查看", roles: []string{"tenant_admin"}},
+			// 字典为全局数据，所有租户共用，仅超级管理员可维护
+			{code: "system:dict:create", name: "字典管理-创建"},
+			{code: "system:dict:update", name: "字典管理-更新"},
+			{code: "system:dict:delete", name: "字典管理-删除"},
+		},
+	},
+	{
+		parent: model.Menu{Name: "操作日志", Code: "system:log:menu", Type: "menu", Path: "/system/logs", Component: "system/logs/index", Icon: "log"},
+		permissions: []permissionSeed{
+			{code: "system:operlog:list", name: "操作日志-查看", roles: []string{"tenant_admin"}},
+			{code: "system:operlog:export", name: "操作日志-导出", roles: []string{"tenant_admin"}},
+			{code: "system:loginlog:list", name: "登录日志-查看", roles: []string{"tenant_admin"}},
+		},
+	},
+	{
+		parent: model.Menu{Name: "文件管理", Code: "file_management", Type: "menu", Path: "/files", Component: "system/files", Icon: "FolderOpened", SortOrder: 500},
+		permissions: []permissionSeed{
+			{code: "system:file:list", name: "文件管理-列表", roles: []string{"tenant_admin"}},
+			{code: "system:file:view", name: "文件管理-查看", roles: []string{"tenant_admin", "user"}},
+			{code: "system:file:upload", name: "文件管理-上传", roles: []string{"tenant_admin", "user"}},
+			{code: "system:file:delete", name: "文件管理-删除", roles: []string{"tenant_admin"}},
+		},
+	},
+	{
+		parent: model.Menu{Name: "代码生成", Code: "tool:gen:menu", Type: "menu", Path: "/generator/tables", Component: "generator/TableSelection", Icon: "Cpu", SortOrder: 600, IsHidden: true},
+		permissions: []permissionSeed{
+			{code: "tool:gen:list", name: "代码生成-查看"},
+			{code: "tool:gen:create", name: "代码生成-创建配置"},
+			{code: "tool:gen:update", name: "代码生成-更新配置"},
+			{code: "tool:gen:delete", name: "代码生成-删除配置"},
+			{code: "tool:gen:preview", name: "代码生成-预览"},
+			{code: "tool:gen:generate", name: "代码生成-生成下载"},
+		},
+	},
+}
+
+// createPermissionMenus 创建管理接口的权限码
+// 仅在权限码首次创建时授予角色，已有的角色授权不做改动
+func createPermissionMenus() {
+	db := database.GetDB()
+
+	renameLegacyPermissionCodes()
+
+	for _, group := range permissionGroups {
+		parent := group.parent
+		if err := db.Where("code = ?", parent.Code).First(&parent).Error; err != nil {
+			parent.Status = 1
+			if err := db.Create(&parent).Error; err != nil {
+				logger.Error("Failed to create menu:", parent.Code, err)
+				continue
+			}
+			logger.Info("Created menu:", parent.Code)
+			assignMenuToRoles(parent.ID, permissionGroupRoles(group))
+		}
+
+		for i, seed := range group.permissions {
+			var count int64
+			db.Model(&model.Menu{}).Where("code = ?", seed.code).Count(&count)
+			if count > 0 {
+				continue
+			}
+
+			menu := model.Menu{
+				ParentID:  parent.ID,
+				Name:      seed.name,
+				Code:      seed.code,
+				Type:      "permission",
+				SortOrder: i + 1,
+				Status:    1,
+			}
+			if err := db.Create(&menu).Error; err != nil {
+				logger.Error("Failed to create permission:", seed.code, err)
+				continue
+			}
+			logger.Info("Created permission:", seed.code)
+			assignMenuToRoles(menu.ID, append([]string{"super_admin"}, seed.roles...))
+		}
+	}
+}
+
+// renameLegacyPermissionCodes 将旧版权限码更新为新版权限码，保留已有的角色授权
+func renameLegacyPermissionCodes() {
+	db := database.GetDB()
+
+	for oldCode, newCode := range legacyPermissionCodes {
+		var count int64
+		db.Model(&model.Menu{}).Where("code = ?", newCode).Count(&count)
+		if count > 0 {
+			continue
+		}
+
+		result := db.Model(&model.Menu{}).Where("code = ?", oldCode).Update("code", newCode)
+		if result.Error != nil {
+			logger.Error("Failed to rename permission:", oldCode, result.Error)
+		} else if result.RowsAffected > 0 {
+			logger.Info("Renamed permission:", oldCode, "->", newCode)
+		}
+	}
+}
+
+// permissionGroupRoles 获取权限组涉及的全部角色，用于授予新建的父菜单
+func permissionGroupRoles(group permissionGroup) []string {
+	roles := []string{"super_admin"}
+	seen := map[string]bool{"super_admin": true}
+	for _, seed := range group.permissions {
+		for _, role := range seed.roles {
+			if !seen[role] {
+				seen[role] = true
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
+
+// assignMenuToRoles 为角色分配菜单
+func assignMenuToRoles(menuID uint64, roleCodes []string) {
+	db := database.GetDB()
+
+	for _, roleCode := range roleCodes {
+		var role model.Role
+		if err := db.Where("tenant_id = ? AND code = ?", model.SystemRoleTenantID, roleCode).First(&role).Error; err != nil {
+			logger.Error("Failed to find role:", roleCode, err)
+			continue
+		}
+
+		var count int64
+		db.Model(&model.RoleMenus{}).Where("role_id = ? AND menu_id = ?", role.ID, menuID).Count(&count)
+		if count > 0 {
+			continue
+		}
+
+		if err := db.Create(&model.RoleMenus{RoleId: role.ID, MenuId: menuID}).Error; err != nil {
+			logger.Error("Failed to assign menu to role:", roleCode, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/pkg/database"
+	"github.com/LiteMove/light-stack/pkg/logger"
+)
+
+// systemTenantID 系统租户ID
+const systemTenantID uint64 = 1
+
+// migrateTenantRoles 将角色迁移为租户隔离
+// 1. 删除旧的全局唯一编码索引，创建租户内唯一的联合索引
+// 2. 旧的非系统角色按使用者所属租户归属；无人使用的归属系统租户；被多个租户使用的保留为共享的系统角色
+func migrateTenantRoles() {
+	db := database.GetDB()
+	migrator := db.Migrator()
+
+	for _, index := range []string{"uk_code", "idx_roles_code"} {
+		if migrator.HasIndex(&model.Role{}, index) {
+			if err := migrator.DropIndex(&model.Role{}, index); err != nil {
+				logger.Error("Failed to drop role index:", index, err)
+			} else {
+				logger.Info("Dropped role index:", index)
+			}
+		}
+	}
+
+	var roles []model.Role
+	if err := db.Where("tenant_id = ? AND is_system = ?", model.SystemRoleTenantID, false).Find(&roles).Error; err != nil {
+		logger.Error("Failed to load legacy roles:", err)
+		return
+	}
+
+	for _, role := range roles {
+		var tenantIDs []uint64
+		err := db.Table("user_roles").
+			Joins("JOIN users ON users.id = user_roles.user_id").
+			Where("user_roles.role_id = ?", role.ID).
+			Distinct().
+			Pluck("users.tenant_id", &tenantIDs).Error
+		if err != nil {
+			logger.Error("Failed to load role tenants:", role.Code, err)
+			continue
+		}
+
+		updates := map[string]interface{}{}
+		switch len(tenantIDs) {
+		case 0:
+			updates["tenant_id"] = systemTenantID
+		case 1:
+			updates["tenant_id"] = tenantIDs[0]
+		default:
+			updates["is_system"] = true
+			logger.Warn("Role is used by multiple tenants, keeping it as a shared system role:", role.Code)
+		}
+
+		if err := db.Model(&model.Role{}).Where("id = ?", role.ID).Updates(updates).Error; err != nil {
+			logger.Error("Failed to migrate role:", role.Code, err)
+		} else {
+			logger.Info("Migrated role:", role.Code, updates)
+		}
+	}
+
+	if !migrator.HasIndex(&model.Role{}, "uk_tenant_code") {
+		if err := db.Exec("CREATE UNIQUE INDEX uk_tenant_code ON roles (tenant_id, code)").Error; err != nil {
+			logger.Error("Failed to create role index uk_tenant_code:", err)
+		} else {
+			logger.Info("Created role index uk_tenant_code")
+		}
+	}
+}
+
+// builtinRoleDataScopes 内置系统角色的数据权限
+var builtinRoleDataScopes = map[string]sharedModel.DataScope{
+	"super_admin":  sharedModel.DataScopeAll,
+	"tenant_admin": sharedModel.DataScopeTenant,
+	"user":         sharedModel.DataScopeSelf,
+}
+
+// migrateRoleDataScopes 数据权限字段新增后为内置系统角色设置数据权限，其余角色保持默认的本租户数据
+func migrateRoleDataScopes() {
+	db := database.GetDB()
+
+	for code, dataScope := range builtinRoleDataScopes {
+		result := db.Model(&model.Role{}).
+			Where("tenant_id = ? AND code = ?", model.SystemRoleTenantID, code).
+			Update("data_scope", dataScope)
+		if result.Error != nil {
+			logger.Error("Failed to set role data scope:", code, result.Error)
+		} else if result.RowsAffected > 0 {
+			logger.Info("Set role data scope:", code, dataScope)
+		}
+	}
+}
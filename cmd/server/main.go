@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"net/http"
 
 	"github.com/LiteMove/light-stack/internal/routes"
 	"github.com/LiteMove/light-stack/internal/shared/config"
@@ -65,8 +66,9 @@ func main() {
 		port = "8080"
 	}
 
+	// 剥离租户路径前缀后再交给路由匹配
 	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
+	if err := http.ListenAndServe(":"+port, middleware.TenantPathHandler(r)); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
 }
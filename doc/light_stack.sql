@@ -17,6 +17,34 @@
 SET NAMES utf8mb4;
 SET FOREIGN_KEY_CHECKS = 0;
 
+-- ----------------------------
+-- Table structure for depts
+-- ----------------------------
+DROP TABLE IF EXISTS `depts`;
+CREATE TABLE `depts`  (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '部门ID',
+  `tenant_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '租户ID',
+  `parent_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '上级部门ID，0表示顶级部门',
+  `name` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '部门名称',
+  `leader_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '负责人用户ID，0表示未设置',
+  `phone` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '联系电话',
+  `email` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '邮箱',
+  `sort_order` int(11) NOT NULL DEFAULT 0 COMMENT '排序号',
+  `status` tinyint(4) NOT NULL DEFAULT 1 COMMENT '部门状态：1-启用 2-禁用',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_tenant_id`(`tenant_id`) USING BTREE,
+  INDEX `idx_parent_id`(`parent_id`) USING BTREE,
+  INDEX `idx_leader_id`(`leader_id`) USING BTREE,
+  INDEX `idx_status`(`status`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '部门表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of depts
+-- ----------------------------
+
 -- ----------------------------
 -- Table structure for dict_data
 -- ----------------------------
@@ -99,6 +127,8 @@ CREATE TABLE `files`  (
   `storage_type` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '存储类型',
   `is_public` tinyint(1) NULL DEFAULT 0 COMMENT '是否公开访问，0：私有，1：公有',
   `access_url` varchar(512) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '完整访问URL',
+  `scan_status` tinyint(4) NOT NULL DEFAULT 1 COMMENT '安全扫描状态：1-已通过 2-隔离中 3-检出威胁 4-扫描失败 5-超出扫描大小限制',
+  `scan_result` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '检出的威胁名称或扫描失败原因',
   `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
@@ -106,17 +136,67 @@ CREATE TABLE `files`  (
   INDEX `idx_tenant_id`(`tenant_id`) USING BTREE,
   INDEX `idx_upload_user_id`(`upload_user_id`) USING BTREE,
   INDEX `idx_usage_type`(`usage_type`) USING BTREE,
-  INDEX `idx_md5`(`md5`) USING BTREE
+  INDEX `idx_md5`(`md5`) USING BTREE,
+  INDEX `idx_scan_status`(`scan_status`) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 29 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '文件表' ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of files
 -- ----------------------------
-INSERT INTO `files` VALUES (24, 1, '上线我的 2.0.png', '1758706009945887000.png', 'private/tenant_1/2025/09/24/1758706009945887000.png', 1666407, 'png', 'image/png', '0abf3fe7172f56a49127ba0ea6e889df', 1, 'avatar', 'local', 0, 'http://127.0.0.1:8080/api/static/private/tenant_1/2025/09/24/1758706009945887000.png', '2025-09-24 17:26:50', '2025-09-24 17:51:20', '2025-09-24 17:51:21');
-INSERT INTO `files` VALUES (25, 1, '个人公众号起名.png', '1758707254453896600.png', 'public/tenant_1/2025/09/24/1758707254453896600.png', 1351175, 'png', 'image/png', '65bd9ff02d9567752b7be06a2cb2b791', 1, 'avatar', 'local', 1, 'http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758707254453896600.png', '2025-09-24 17:47:34', '2025-09-24 17:47:34', NULL);
-INSERT INTO `files` VALUES (26, 1, '上线我的 2.0.png', '1758707486724848500.png', 'public/tenant_1/2025/09/24/1758707486724848500.png', 1666407, 'png', 'image/png', '0abf3fe7172f56a49127ba0ea6e889df', 1, 'avatar', 'local', 1, 'http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758707486724848500.png', '2025-09-24 17:51:27', '2025-09-24 17:51:27', NULL);
-INSERT INTO `files` VALUES (27, 1, 'logo.png', '1758708129776068200.png', 'public/tenant_1/2025/09/24/1758708129776068200.png', 9854, 'png', 'image/png', '28048fc01baf30a1d6365d20306a7b3e', 1, 'system-logo', 'local', 1, 'http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758708129776068200.png', '2025-09-24 18:02:10', '2025-09-24 18:02:10', NULL);
-INSERT INTO `files` VALUES (28, 1, '新建 文本文档.txt', '1759026609826753200.txt', 'private/tenant_1/2025/09/28/1759026609826753200.txt', 21, 'txt', 'text/plain', '5b67bd58f9aef918d42d13970ea88217', 1, 'document', 'local', 0, 'http://127.0.0.1:8080/api/static/private/tenant_1/2025/09/28/1759026609826753200.txt', '2025-09-28 10:30:10', '2025-09-28 10:30:10', NULL);
+INSERT INTO `files` VALUES (24, 1, '上线我的 2.0.png', '1758706009945887000.png', 'private/tenant_1/2025/09/24/1758706009945887000.png', 1666407, 'png', 'image/png', '0abf3fe7172f56a49127ba0ea6e889df', 1, 'avatar', 'local', 0, 'http://127.0.0.1:8080/api/static/private/tenant_1/2025/09/24/1758706009945887000.png', 1, NULL, '2025-09-24 17:26:50', '2025-09-24 17:51:20', '2025-09-24 17:51:21');
+INSERT INTO `files` VALUES (25, 1, '个人公众号起名.png', '1758707254453896600.png', 'public/tenant_1/2025/09/24/1758707254453896600.png', 1351175, 'png', 'image/png', '65bd9ff02d9567752b7be06a2cb2b791', 1, 'avatar', 'local', 1, 'http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758707254453896600.png', 1, NULL, '2025-09-24 17:47:34', '2025-09-24 17:47:34', NULL);
+INSERT INTO `files` VALUES (26, 1, '上线我的 2.0.png', '1758707486724848500.png', 'public/tenant_1/2025/09/24/1758707486724848500.png', 1666407, 'png', 'image/png', '0abf3fe7172f56a49127ba0ea6e889df', 1, 'avatar', 'local', 1, 'http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758707486724848500.png', 1, NULL, '2025-09-24 17:51:27', '2025-09-24 17:51:27', NULL);
+INSERT INTO `files` VALUES (27, 1, 'logo.png', '1758708129776068200.png', 'public/tenant_1/2025/09/24/1758708129776068200.png', 9854, 'png', 'image/png', '28048fc01baf30a1d6365d20306a7b3e', 1, 'system-logo', 'local', 1, 'http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758708129776068200.png', 1, NULL, '2025-09-24 18:02:10', '2025-09-24 18:02:10', NULL);
+INSERT INTO `files` VALUES (28, 1, '新建 文本文档.txt', '1759026609826753200.txt', 'private/tenant_1/2025/09/28/1759026609826753200.txt', 21, 'txt', 'text/plain', '5b67bd58f9aef918d42d13970ea88217', 1, 'document', 'local', 0, 'http://127.0.0.1:8080/api/static/private/tenant_1/2025/09/28/1759026609826753200.txt', 1, NULL, '2025-09-28 10:30:10', '2025-09-28 10:30:10', NULL);
+
+-- ----------------------------
+-- Table structure for file_upload_sessions
+-- ----------------------------
+DROP TABLE IF EXISTS `file_upload_sessions`;
+CREATE TABLE `file_upload_sessions`  (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '上传会话ID',
+  `tenant_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '租户ID',
+  `upload_user_id` bigint(20) NOT NULL COMMENT '上传用户ID',
+  `original_name` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '原始文件名',
+  `file_name` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '存储文件名',
+  `file_path` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '合并后的存储路径',
+  `file_size` bigint(20) NOT NULL COMMENT '文件大小（字节）',
+  `file_type` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '文件类型',
+  `mime_type` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'MIME类型',
+  `md5` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '客户端声明的文件MD5',
+  `chunk_size` bigint(20) NOT NULL COMMENT '分片大小（字节）',
+  `total_chunks` int(11) NOT NULL COMMENT '分片总数',
+  `usage_type` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '使用类型',
+  `storage_type` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '存储类型',
+  `is_public` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否公开访问',
+  `upload_id` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '存储端分片上传ID',
+  `status` tinyint(4) NOT NULL DEFAULT 1 COMMENT '状态：1-上传中 2-已完成 3-已取消 4-合并中',
+  `file_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '完成后生成的文件ID',
+  `expires_at` datetime NOT NULL COMMENT '过期时间',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_tenant_id`(`tenant_id`) USING BTREE,
+  INDEX `idx_upload_user_id`(`upload_user_id`) USING BTREE,
+  INDEX `idx_status`(`status`) USING BTREE,
+  INDEX `idx_expires_at`(`expires_at`) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '分片上传会话表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for file_upload_session_parts
+-- ----------------------------
+DROP TABLE IF EXISTS `file_upload_session_parts`;
+CREATE TABLE `file_upload_session_parts`  (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `session_id` bigint(20) NOT NULL COMMENT '上传会话ID',
+  `part_number` int(11) NOT NULL COMMENT '分片序号',
+  `size` bigint(20) NOT NULL COMMENT '分片大小（字节）',
+  `etag` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '分片ETag',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '上传时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `uk_session_part`(`session_id`, `part_number`) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '分片上传已接收分片表' ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Table structure for gen_histories
@@ -299,7 +379,7 @@ CREATE TABLE `menus`  (
   INDEX `idx_parent_id`(`parent_id`) USING BTREE,
   INDEX `idx_type`(`type`) USING BTREE,
   INDEX `idx_sort_order`(`sort_order`) USING BTREE
-) ENGINE = InnoDB AUTO_INCREMENT = 30 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '菜单权限表' ROW_FORMAT = Dynamic;
+) ENGINE = InnoDB AUTO_INCREMENT = 61 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '菜单权限表' ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of menus
@@ -315,23 +395,54 @@ INSERT INTO `menus` VALUES (8, 2, '用户管理-列表', 'system:user:list', 'pe
 INSERT INTO `menus` VALUES (9, 2, '用户管理-创建', 'system:user:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-18 20:21:12', '2025-09-24 21:28:54', NULL);
 INSERT INTO `menus` VALUES (10, 2, '用户管理-更新', 'system:user:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-18 20:21:12', '2025-09-24 21:28:55', NULL);
 INSERT INTO `menus` VALUES (11, 2, '用户管理-删除', 'system:user:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-09-18 20:21:12', '2025-09-24 21:28:57', NULL);
-INSERT INTO `menus` VALUES (12, 3, '角色管理-查看', 'role:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
-INSERT INTO `menus` VALUES (13, 3, '角色管理-创建', 'role:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
-INSERT INTO `menus` VALUES (14, 3, '角色管理-更新', 'role:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
-INSERT INTO `menus` VALUES (15, 3, '角色管理-删除', 'role:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
-INSERT INTO `menus` VALUES (16, 4, '菜单权限-查看', 'menu:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
-INSERT INTO `menus` VALUES (17, 4, '菜单权限-创建', 'menu:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
-INSERT INTO `menus` VALUES (18, 4, '菜单权限-更新', 'menu:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
-INSERT INTO `menus` VALUES (19, 4, '菜单权限-删除', 'menu:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:13', NULL);
-INSERT INTO `menus` VALUES (20, 5, '租户管理-查看', 'tenant:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:13', NULL);
-INSERT INTO `menus` VALUES (21, 5, '租户管理-创建', 'tenant:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:13', NULL);
-INSERT INTO `menus` VALUES (22, 5, '租户管理-更新', 'tenant:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:13', NULL);
-INSERT INTO `menus` VALUES (23, 5, '租户管理-删除', 'tenant:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:13', NULL);
+INSERT INTO `menus` VALUES (12, 3, '角色管理-查看', 'system:role:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
+INSERT INTO `menus` VALUES (13, 3, '角色管理-创建', 'system:role:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
+INSERT INTO `menus` VALUES (14, 3, '角色管理-更新', 'system:role:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
+INSERT INTO `menus` VALUES (15, 3, '角色管理-删除', 'system:role:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
+INSERT INTO `menus` VALUES (16, 4, '菜单权限-查看', 'system:menu:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
+INSERT INTO `menus` VALUES (17, 4, '菜单权限-创建', 'system:menu:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
+INSERT INTO `menus` VALUES (18, 4, '菜单权限-更新', 'system:menu:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:12', NULL);
+INSERT INTO `menus` VALUES (19, 4, '菜单权限-删除', 'system:menu:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:13', NULL);
+INSERT INTO `menus` VALUES (20, 5, '租户管理-查看', 'system:tenant:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:13', NULL);
+INSERT INTO `menus` VALUES (21, 5, '租户管理-创建', 'system:tenant:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:13', NULL);
+INSERT INTO `menus` VALUES (22, 5, '租户管理-更新', 'system:tenant:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:13', NULL);
+INSERT INTO `menus` VALUES (23, 5, '租户管理-删除', 'system:tenant:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-09-18 20:21:12', '2025-09-18 22:16:13', NULL);
 INSERT INTO `menus` VALUES (24, 2, '用户角色-分配', 'system:user:role:assign', 'permission', '', 1, '', '', 0, 0, '0000-00-00 00:00:00', '2025-09-24 21:32:59', NULL);
 INSERT INTO `menus` VALUES (25, 2, '用户角色-查看', 'system:user:role:list', 'permission', '', 1, '', '', 0, 0, '0000-00-00 00:00:00', '2025-09-24 21:33:05', NULL);
 INSERT INTO `menus` VALUES (27, 0, '权限管理', '__', 'directory', '/perm', 1, '', 'UserFilled', 0, 0, '0000-00-00 00:00:00', '2025-09-24 23:15:34', NULL);
 INSERT INTO `menus` VALUES (28, 1, '文件管理', 'file_management', 'menu', '/files', 1, 'system/files/index', 'FolderOpened', 0, 0, '0000-00-00 00:00:00', '2025-09-24 22:55:29', NULL);
 INSERT INTO `menus` VALUES (29, 2, '用户管理-重置密码', 'system:user:reset', 'permission', '', 1, '', '', 0, 0, '0000-00-00 00:00:00', '2025-09-25 11:39:23', NULL);
+INSERT INTO `menus` VALUES (30, 2, '用户管理-详情', 'system:user:detail', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (31, 3, '角色菜单-分配', 'system:role:menu:assign', 'permission', NULL, 1, NULL, NULL, 5, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (32, 6, '字典管理-查看', 'system:dict:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (33, 6, '字典管理-创建', 'system:dict:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (34, 6, '字典管理-更新', 'system:dict:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (35, 6, '字典管理-删除', 'system:dict:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (36, 7, '操作日志-查看', 'system:operlog:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (37, 7, '操作日志-导出', 'system:operlog:export', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (38, 7, '登录日志-查看', 'system:loginlog:list', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (39, 28, '文件管理-列表', 'system:file:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (40, 28, '文件管理-查看', 'system:file:view', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (41, 28, '文件管理-上传', 'system:file:upload', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (42, 28, '文件管理-删除', 'system:file:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (43, 1, '代码生成', 'tool:gen:menu', 'menu', '/generator/tables', 1, 'generator/TableSelection', 'Cpu', 600, 1, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (44, 43, '代码生成-查看', 'tool:gen:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (45, 43, '代码生成-创建配置', 'tool:gen:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (46, 43, '代码生成-更新配置', 'tool:gen:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (47, 43, '代码生成-删除配置', 'tool:gen:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (48, 43, '代码生成-预览', 'tool:gen:preview', 'permission', NULL, 1, NULL, NULL, 5, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (49, 43, '代码生成-生成下载', 'tool:gen:generate', 'permission', NULL, 1, NULL, NULL, 6, 0, '2025-09-26 10:00:00', '2025-09-26 10:00:00', NULL);
+INSERT INTO `menus` VALUES (50, 1, '部门管理', 'system:dept:menu', 'menu', '/system/depts', 1, 'system/depts/index', 'OfficeBuilding', 0, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
+INSERT INTO `menus` VALUES (51, 50, '部门管理-查看', 'system:dept:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
+INSERT INTO `menus` VALUES (52, 50, '部门管理-创建', 'system:dept:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
+INSERT INTO `menus` VALUES (53, 50, '部门管理-更新', 'system:dept:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
+INSERT INTO `menus` VALUES (54, 50, '部门管理-删除', 'system:dept:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
+INSERT INTO `menus` VALUES (55, 1, '岗位管理', 'system:post:menu', 'menu', '/system/posts', 1, 'system/posts/index', 'Postcard', 0, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
+INSERT INTO `menus` VALUES (56, 55, '岗位管理-查看', 'system:post:list', 'permission', NULL, 1, NULL, NULL, 1, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
+INSERT INTO `menus` VALUES (57, 55, '岗位管理-创建', 'system:post:create', 'permission', NULL, 1, NULL, NULL, 2, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
+INSERT INTO `menus` VALUES (58, 55, '岗位管理-更新', 'system:post:update', 'permission', NULL, 1, NULL, NULL, 3, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
+INSERT INTO `menus` VALUES (59, 55, '岗位管理-删除', 'system:post:delete', 'permission', NULL, 1, NULL, NULL, 4, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
+INSERT INTO `menus` VALUES (60, 2, '用户岗位-分配', 'system:user:post:assign', 'permission', NULL, 1, NULL, NULL, 0, 0, '2025-10-01 10:00:00', '2025-10-01 10:00:00', NULL);
 
 -- ----------------------------
 -- Table structure for operation_logs
@@ -352,10 +463,15 @@ CREATE TABLE `operation_logs`  (
   `user_agent` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT 'User-Agent',
   `duration` int(11) NULL DEFAULT NULL COMMENT '执行时长（毫秒）',
   `status` tinyint(4) NOT NULL COMMENT '状态：1-成功 2-失败',
+  `impersonator_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '模拟登录的实际操作人ID，0表示非模拟登录',
+  `impersonator_name` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '模拟登录的实际操作人用户名',
   `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
   PRIMARY KEY (`id`) USING BTREE,
   INDEX `idx_tenant_id`(`tenant_id`) USING BTREE,
   INDEX `idx_user_id`(`user_id`) USING BTREE,
+  INDEX `idx_impersonator_id`(`impersonator_id`) USING BTREE,
   INDEX `idx_operation`(`operation`) USING BTREE,
   INDEX `idx_status`(`status`) USING BTREE,
   INDEX `idx_created_at`(`created_at`) USING BTREE
@@ -365,6 +481,48 @@ CREATE TABLE `operation_logs`  (
 -- Records of operation_logs
 -- ----------------------------
 
+-- ----------------------------
+-- Table structure for password_histories
+-- ----------------------------
+DROP TABLE IF EXISTS `password_histories`;
+CREATE TABLE `password_histories`  (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` bigint(20) NOT NULL COMMENT '用户ID',
+  `password` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '历史密码（bcrypt加密）',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_user_id`(`user_id`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '用户历史密码表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of password_histories
+-- ----------------------------
+
+-- ----------------------------
+-- Table structure for posts
+-- ----------------------------
+DROP TABLE IF EXISTS `posts`;
+CREATE TABLE `posts`  (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '岗位ID',
+  `tenant_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '租户ID',
+  `code` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '岗位编码',
+  `name` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '岗位名称',
+  `sort_order` int(11) NOT NULL DEFAULT 0 COMMENT '排序号',
+  `status` tinyint(4) NOT NULL DEFAULT 1 COMMENT '岗位状态：1-启用 2-禁用',
+  `remark` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '备注',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_tenant_id`(`tenant_id`) USING BTREE,
+  INDEX `idx_code`(`code`) USING BTREE,
+  INDEX `idx_status`(`status`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '岗位表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of posts
+-- ----------------------------
+
 -- ----------------------------
 -- Table structure for role_menus
 -- ----------------------------
@@ -378,7 +536,7 @@ CREATE TABLE `role_menus`  (
   UNIQUE INDEX `uk_role_menu`(`role_id`, `menu_id`) USING BTREE,
   INDEX `idx_role_id`(`role_id`) USING BTREE,
   INDEX `idx_menu_id`(`menu_id`) USING BTREE
-) ENGINE = InnoDB AUTO_INCREMENT = 162 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '角色菜单权限关联表' ROW_FORMAT = Dynamic;
+) ENGINE = InnoDB AUTO_INCREMENT = 219 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '角色菜单权限关联表' ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of role_menus
@@ -422,6 +580,60 @@ INSERT INTO `role_menus` VALUES (158, 1, 19, '2025-09-24 22:41:19');
 INSERT INTO `role_menus` VALUES (159, 1, 23, '2025-09-24 22:41:19');
 INSERT INTO `role_menus` VALUES (160, 1, 6, '2025-09-24 22:41:19');
 INSERT INTO `role_menus` VALUES (161, 1, 7, '2025-09-24 22:41:19');
+INSERT INTO `role_menus` VALUES (162, 1, 30, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (163, 2, 30, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (164, 1, 31, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (165, 2, 31, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (166, 1, 32, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (167, 2, 32, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (168, 1, 33, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (170, 1, 34, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (172, 1, 35, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (174, 1, 36, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (175, 2, 36, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (176, 1, 37, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (177, 2, 37, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (178, 1, 38, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (179, 2, 38, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (180, 1, 39, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (181, 2, 39, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (182, 1, 40, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (183, 2, 40, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (184, 3, 40, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (185, 1, 41, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (186, 2, 41, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (187, 3, 41, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (188, 1, 42, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (189, 2, 42, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (190, 1, 43, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (191, 1, 44, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (192, 1, 45, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (193, 1, 46, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (194, 1, 47, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (195, 1, 48, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (196, 1, 49, '2025-09-26 10:00:00');
+INSERT INTO `role_menus` VALUES (197, 1, 50, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (198, 2, 50, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (199, 1, 51, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (200, 2, 51, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (201, 1, 52, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (202, 2, 52, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (203, 1, 53, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (204, 2, 53, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (205, 1, 54, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (206, 2, 54, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (207, 1, 55, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (208, 2, 55, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (209, 1, 56, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (210, 2, 56, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (211, 1, 57, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (212, 2, 57, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (213, 1, 58, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (214, 2, 58, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (215, 1, 59, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (216, 2, 59, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (217, 1, 60, '2025-10-01 10:00:00');
+INSERT INTO `role_menus` VALUES (218, 2, 60, '2025-10-01 10:00:00');
 
 -- ----------------------------
 -- Table structure for roles
@@ -429,17 +641,20 @@ INSERT INTO `role_menus` VALUES (161, 1, 7, '2025-09-24 22:41:19');
 DROP TABLE IF EXISTS `roles`;
 CREATE TABLE `roles`  (
   `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '角色ID',
+  `tenant_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '租户ID，0表示系统角色（所有租户共享）',
   `name` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '角色名称',
   `code` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '角色编码',
   `description` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '角色描述',
   `status` tinyint(4) NOT NULL DEFAULT 1 COMMENT '角色状态：1-启用 2-禁用',
   `is_system` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否系统角色：0-否 1-是',
   `sort_order` int(11) NOT NULL DEFAULT 0 COMMENT '排序号',
+  `data_scope` tinyint(4) NOT NULL DEFAULT 2 COMMENT '数据权限：1-全部 2-本租户 3-本部门 4-仅本人',
   `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
   PRIMARY KEY (`id`) USING BTREE,
-  UNIQUE INDEX `uk_code`(`code`) USING BTREE,
+  UNIQUE INDEX `uk_tenant_code`(`tenant_id`, `code`) USING BTREE,
+  INDEX `idx_tenant_id`(`tenant_id`) USING BTREE,
   INDEX `idx_status`(`status`) USING BTREE,
   INDEX `idx_is_system`(`is_system`) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 4 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '角色表' ROW_FORMAT = Dynamic;
@@ -447,9 +662,50 @@ CREATE TABLE `roles`  (
 -- ----------------------------
 -- Records of roles
 -- ----------------------------
-INSERT INTO `roles` VALUES (1, '超级管理员', 'super_admin', '拥有系统所有权限，可管理所有租户、用户、角色和菜单权限', 1, 1, 1, '2025-09-18 20:21:12', '2025-09-18 20:21:12', NULL);
-INSERT INTO `roles` VALUES (2, '租户管理员', 'tenant_admin', '租户管理员，可管理本租户下的用户（创建、修改、删除），可以给用户分配非系统角色', 1, 1, 2, '2025-09-18 20:21:12', '2025-09-18 20:21:12', NULL);
-INSERT INTO `roles` VALUES (3, '普通用户', 'user', '普通用户，只能查看和操作自己的信息', 1, 1, 3, '2025-09-18 20:21:12', '2025-09-18 20:21:12', NULL);
+INSERT INTO `roles` VALUES (1, 0, '超级管理员', 'super_admin', '拥有系统所有权限，可管理所有租户、用户、角色和菜单权限', 1, 1, 1, 1, '2025-09-18 20:21:12', '2025-09-18 20:21:12', NULL);
+INSERT INTO `roles` VALUES (2, 0, '租户管理员', 'tenant_admin', '租户管理员，可管理本租户下的用户（创建、修改、删除），可以给用户分配非系统角色', 1, 1, 2, 2, '2025-09-18 20:21:12', '2025-09-18 20:21:12', NULL);
+INSERT INTO `roles` VALUES (3, 0, '普通用户', 'user', '普通用户，只能查看和操作自己的信息', 1, 1, 3, 4, '2025-09-18 20:21:12', '2025-09-18 20:21:12', NULL);
+
+-- ----------------------------
+-- Table structure for tenant_package_menus
+-- ----------------------------
+DROP TABLE IF EXISTS `tenant_package_menus`;
+CREATE TABLE `tenant_package_menus`  (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `package_id` bigint(20) NOT NULL COMMENT '套餐ID',
+  `menu_id` bigint(20) NOT NULL COMMENT '菜单/权限ID',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `uk_package_menu`(`package_id`, `menu_id`) USING BTREE,
+  INDEX `idx_package_id`(`package_id`) USING BTREE,
+  INDEX `idx_menu_id`(`menu_id`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '租户套餐菜单关联表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of tenant_package_menus
+-- ----------------------------
+
+-- ----------------------------
+-- Table structure for tenant_packages
+-- ----------------------------
+DROP TABLE IF EXISTS `tenant_packages`;
+CREATE TABLE `tenant_packages`  (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '套餐ID',
+  `name` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '套餐名称',
+  `description` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '套餐描述',
+  `status` tinyint(4) NOT NULL DEFAULT 1 COMMENT '套餐状态：1-启用 2-禁用',
+  `sort_order` int(11) NOT NULL DEFAULT 0 COMMENT '排序号',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `uk_name`(`name`) USING BTREE,
+  INDEX `idx_status`(`status`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '租户套餐表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of tenant_packages
+-- ----------------------------
 
 -- ----------------------------
 -- Table structure for tenants
@@ -458,25 +714,48 @@ DROP TABLE IF EXISTS `tenants`;
 CREATE TABLE `tenants`  (
   `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '租户ID',
   `name` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '租户名称',
+  `code` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '租户编码，用于子域名和路径前缀识别',
   `domain` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '租户域名',
   `status` tinyint(4) NOT NULL DEFAULT 1 COMMENT '租户状态：1-启用 2-禁用 3-试用 4-过期',
   `expired_at` datetime NULL DEFAULT NULL COMMENT '过期时间',
   `config` json NULL COMMENT '租户配置信息（Logo、主题色等）',
+  `package_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '租户套餐ID，0表示不限制',
   `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
   PRIMARY KEY (`id`) USING BTREE,
   UNIQUE INDEX `uk_domain`(`domain`) USING BTREE,
+  INDEX `idx_code`(`code`) USING BTREE,
   INDEX `idx_status`(`status`) USING BTREE,
-  INDEX `idx_expired_at`(`expired_at`) USING BTREE
+  INDEX `idx_expired_at`(`expired_at`) USING BTREE,
+  INDEX `idx_package_id`(`package_id`) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 4 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '租户信息表' ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of tenants
 -- ----------------------------
-INSERT INTO `tenants` VALUES (1, 'LightStack', 'system', 1, NULL, '{\"logo\": \"http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758708129776068200.png\", \"copyright\": \"\", \"systemName\": \"轻栈管理平台\", \"description\": \"\", \"fileStorage\": {\"type\": \"local\", \"maxFileSize\": 52428800, \"ossProvider\": \"aliyun\", \"allowedTypes\": [\".jpg\", \".jpeg\", \".gif\", \".pdf\", \".doc\", \".docx\", \".xlsx\", \".txt\", \".png\", \".xls\"], \"defaultPublic\": false, \"localAccessDomain\": \"http://127.0.0.1:8080\"}}', '2025-09-18 20:21:12', '2025-09-25 12:31:42', NULL);
-INSERT INTO `tenants` VALUES (2, 'Test', 'test.light-stack.com', 1, '2025-09-24 10:00:00', '{\"logo\": \"\", \"copyright\": \"\", \"systemName\": \"\", \"description\": \"\", \"fileStorage\": {\"type\": \"local\", \"maxFileSize\": 52428800, \"ossProvider\": \"aliyun\", \"allowedTypes\": [\".jpg\", \".pdf\", \".doc\", \".docx\", \".xlsx\", \".txt\", \".png\", \".xls\", \".jpeg\", \".gif\"], \"defaultPublic\": true, \"localAccessDomain\": \"http://127.0.0.1:8080\"}}', '2025-09-19 17:46:27', '2025-09-24 15:10:43', NULL);
-INSERT INTO `tenants` VALUES (3, 'Matuto', 'matuto.com', 1, '2025-10-03 15:59:59', '{\"logo\": \"\", \"copyright\": \"\", \"systemName\": \"\", \"description\": \"\", \"fileStorage\": {\"type\": \"local\", \"maxFileSize\": 52428800, \"ossProvider\": \"aliyun\", \"allowedTypes\": [\".jpg\", \".gif\", \".pdf\", \".doc\", \".xlsx\", \".txt\", \".docx\", \".jpeg\", \".png\", \".xls\"], \"defaultPublic\": false, \"localAccessDomain\": \"http://127.0.0.1:8080\"}}', '2025-09-21 08:43:27', '2025-09-24 15:21:21', NULL);
+INSERT INTO `tenants` VALUES (1, 'LightStack', 'system', 'system', 1, NULL, '{\"logo\": \"http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758708129776068200.png\", \"copyright\": \"\", \"systemName\": \"轻栈管理平台\", \"description\": \"\", \"fileStorage\": {\"type\": \"local\", \"maxFileSize\": 52428800, \"ossProvider\": \"aliyun\", \"allowedTypes\": [\".jpg\", \".jpeg\", \".gif\", \".pdf\", \".doc\", \".docx\", \".xlsx\", \".txt\", \".png\", \".xls\"], \"defaultPublic\": false, \"localAccessDomain\": \"http://127.0.0.1:8080\"}}', 0, '2025-09-18 20:21:12', '2025-09-25 12:31:42', NULL);
+INSERT INTO `tenants` VALUES (2, 'Test', 'test', 'test.light-stack.com', 1, '2025-09-24 10:00:00', '{\"logo\": \"\", \"copyright\": \"\", \"systemName\": \"\", \"description\": \"\", \"fileStorage\": {\"type\": \"local\", \"maxFileSize\": 52428800, \"ossProvider\": \"aliyun\", \"allowedTypes\": [\".jpg\", \".pdf\", \".doc\", \".docx\", \".xlsx\", \".txt\", \".png\", \".xls\", \".jpeg\", \".gif\"], \"defaultPublic\": true, \"localAccessDomain\": \"http://127.0.0.1:8080\"}}', 0, '2025-09-19 17:46:27', '2025-09-24 15:10:43', NULL);
+INSERT INTO `tenants` VALUES (3, 'Matuto', 'matuto', 'matuto.com', 1, '2025-10-03 15:59:59', '{\"logo\": \"\", \"copyright\": \"\", \"systemName\": \"\", \"description\": \"\", \"fileStorage\": {\"type\": \"local\", \"maxFileSize\": 52428800, \"ossProvider\": \"aliyun\", \"allowedTypes\": [\".jpg\", \".gif\", \".pdf\", \".doc\", \".xlsx\", \".txt\", \".docx\", \".jpeg\", \".png\", \".xls\"], \"defaultPublic\": false, \"localAccessDomain\": \"http://127.0.0.1:8080\"}}', 0, '2025-09-21 08:43:27', '2025-09-24 15:21:21', NULL);
+
+-- ----------------------------
+-- Table structure for user_posts
+-- ----------------------------
+DROP TABLE IF EXISTS `user_posts`;
+CREATE TABLE `user_posts`  (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` bigint(20) NOT NULL COMMENT '用户ID',
+  `post_id` bigint(20) NOT NULL COMMENT '岗位ID',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `uk_user_post`(`user_id`, `post_id`) USING BTREE,
+  INDEX `idx_user_id`(`user_id`) USING BTREE,
+  INDEX `idx_post_id`(`post_id`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '用户岗位关联表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of user_posts
+-- ----------------------------
 
 -- ----------------------------
 -- Table structure for user_roles
@@ -520,6 +799,15 @@ CREATE TABLE `users`  (
   `last_login_ip` varchar(45) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '最后登录IP',
   `login_failures` int(11) NOT NULL DEFAULT 0 COMMENT '连续登录失败次数',
   `locked_until` datetime NULL DEFAULT NULL COMMENT '锁定截止时间',
+  `lock_count` int(11) NOT NULL DEFAULT 0 COMMENT '累计锁定次数（用于递增锁定时长）',
+  `last_failed_at` datetime NULL DEFAULT NULL COMMENT '最后登录失败时间',
+  `mfa_enabled` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否启用二次验证：0-否 1-是',
+  `mfa_secret` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT 'TOTP密钥',
+  `mfa_recovery_codes` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL COMMENT '恢复码哈希列表（JSON数组）',
+  `password_changed_at` datetime NULL DEFAULT NULL COMMENT '密码最后修改时间',
+  `must_change_password` tinyint(1) NOT NULL DEFAULT 0 COMMENT '下次登录是否必须修改密码：0-否 1-是',
+  `created_by` bigint(20) NOT NULL DEFAULT 0 COMMENT '创建人ID',
+  `dept_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '所属部门ID，0表示未分配',
   `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
@@ -529,15 +817,17 @@ CREATE TABLE `users`  (
   UNIQUE INDEX `uk_tenant_phone`(`tenant_id`, `phone`) USING BTREE,
   INDEX `idx_tenant_id`(`tenant_id`) USING BTREE,
   INDEX `idx_status`(`status`) USING BTREE,
-  INDEX `idx_is_system`(`is_system`) USING BTREE
+  INDEX `idx_is_system`(`is_system`) USING BTREE,
+  INDEX `idx_created_by`(`created_by`) USING BTREE,
+  INDEX `idx_dept_id`(`dept_id`) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 13 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci COMMENT = '用户表' ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of users
 -- ----------------------------
-INSERT INTO `users` VALUES (1, 1, 'admin', '$2a$10$Ck5B5o1Md2O7K.tER/Hug.5phi4hRazcY04WdF46ykrmV3KdPo.3G', '超级管理员', 'admin@lightstack.com', '15688888888', 'http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758707254453896600.png', 1, 1, '2025-09-28 15:53:08', '', 0, NULL, '2025-09-18 20:21:12', '2025-09-28 15:53:08', NULL);
-INSERT INTO `users` VALUES (10, 2, 'test', '$2a$10$Ck5B5o1Md2O7K.tER/Hug.5phi4hRazcY04WdF46ykrmV3KdPo.3G', 'test', NULL, NULL, '', 1, 0, '2025-09-20 11:52:40', '', 0, NULL, '2025-09-20 10:49:05', '2025-09-23 18:16:28', NULL);
-INSERT INTO `users` VALUES (11, 2, 'test01', '$2a$10$0EcaMxX5aGfGQzO2wG85ye0OOnhY40TH0rUWJYthVIPHimaVRgMq2', 'Test01', NULL, NULL, '', 1, 0, NULL, '', 0, NULL, '2025-09-20 10:49:20', '2025-09-20 10:49:20', NULL);
-INSERT INTO `users` VALUES (12, 1, 'test', '$2a$10$4daTLk90ZT.qEUFlVniYjO/4DJ/s1/d1BuwMhGKaou45.BjghDGka', '测试用户', 'test@qq.com', NULL, 'http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758707486724848500.png', 1, 0, '2025-09-24 22:31:15', '', 0, NULL, '2025-09-21 08:50:31', '2025-09-28 15:53:19', NULL);
+INSERT INTO `users` VALUES (1, 1, 'admin', '$2a$10$Ck5B5o1Md2O7K.tER/Hug.5phi4hRazcY04WdF46ykrmV3KdPo.3G', '超级管理员', 'admin@lightstack.com', '15688888888', 'http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758707254453896600.png', 1, 1, '2025-09-28 15:53:08', '', 0, NULL, 0, NULL, 0, NULL, NULL, NULL, 0, 0, 0, '2025-09-18 20:21:12', '2025-09-28 15:53:08', NULL);
+INSERT INTO `users` VALUES (10, 2, 'test', '$2a$10$Ck5B5o1Md2O7K.tER/Hug.5phi4hRazcY04WdF46ykrmV3KdPo.3G', 'test', NULL, NULL, '', 1, 0, '2025-09-20 11:52:40', '', 0, NULL, 0, NULL, 0, NULL, NULL, NULL, 0, 0, 0, '2025-09-20 10:49:05', '2025-09-23 18:16:28', NULL);
+INSERT INTO `users` VALUES (11, 2, 'test01', '$2a$10$0EcaMxX5aGfGQzO2wG85ye0OOnhY40TH0rUWJYthVIPHimaVRgMq2', 'Test01', NULL, NULL, '', 1, 0, NULL, '', 0, NULL, 0, NULL, 0, NULL, NULL, NULL, 0, 0, 0, '2025-09-20 10:49:20', '2025-09-20 10:49:20', NULL);
+INSERT INTO `users` VALUES (12, 1, 'test', '$2a$10$4daTLk90ZT.qEUFlVniYjO/4DJ/s1/d1BuwMhGKaou45.BjghDGka', '测试用户', 'test@qq.com', NULL, 'http://127.0.0.1:8080/api/static/public/tenant_1/2025/09/24/1758707486724848500.png', 1, 0, '2025-09-24 22:31:15', '', 0, NULL, 0, NULL, 0, NULL, NULL, NULL, 0, 0, 0, '2025-09-21 08:50:31', '2025-09-28 15:53:19', NULL);
 
 SET FOREIGN_KEY_CHECKS = 1;
@@ -2,7 +2,6 @@ package controller
 
 import (
 	"strconv"
-	"strings"
 
 	"github.com/LiteMove/light-stack/internal/modules/auth/service"
 	"github.com/LiteMove/light-stack/internal/shared/middleware"
@@ -15,7 +14,12 @@ import (
 // ChangePasswordRequest 修改密码请求
 type ChangePasswordRequest struct {
 	OldPassword string `json:"oldPassword" validate:"required"`
-	NewPassword string `json:"newPassword" validate:"required,min=6"`
+	NewPassword string `json:"newPassword" validate:"required,max=128"`
+}
+
+// RefreshTokenRequest 刷新token请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
 }
 
 // AssignRolesRequest 分配角色请求
@@ -49,6 +53,10 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		tenantID = uint64(1) // 默认系统租户
 	}
 
+	// 填充客户端信息（用于登录日志）
+	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.Request.UserAgent()
+
 	tokenResp, err := c.authService.Login(tenantID, &req)
 	if err != nil {
 		response.BadRequest(ctx, err.Error())
@@ -58,6 +66,91 @@ func (c *AuthController) Login(ctx *gin.Context) {
 	response.Success(ctx, tokenResp)
 }
 
+// MfaSetupRequest 登录时获取二次验证密钥请求
+type MfaSetupRequest struct {
+	MfaToken string `json:"mfaToken" binding:"required"`
+}
+
+// VerifyMfa 登录二次验证
+func (c *AuthController) VerifyMfa(ctx *gin.Context) {
+	var req service.MfaLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "参数格式错误")
+		return
+	}
+
+	// 填充客户端信息（用于登录日志）
+	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.Request.UserAgent()
+
+	tokenResp, err := c.authService.VerifyMfa(&req)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, tokenResp)
+}
+
+// BeginMfaSetup 登录时被要求启用二次验证，获取待绑定的密钥
+func (c *AuthController) BeginMfaSetup(ctx *gin.Context) {
+	var req MfaSetupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "参数格式错误")
+		return
+	}
+
+	setup, err := c.authService.BeginMfaSetup(req.MfaToken)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, setup)
+}
+
+// EnrollMfa 登录时被要求启用二次验证，确认绑定并完成登录
+func (c *AuthController) EnrollMfa(ctx *gin.Context) {
+	var req service.MfaLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "参数格式错误")
+		return
+	}
+
+	// 填充客户端信息（用于登录日志）
+	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.Request.UserAgent()
+
+	enrollResp, err := c.authService.EnrollMfa(&req)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, enrollResp)
+}
+
+// ChangeExpiredPassword 登录时密码已过期或被重置，修改密码后继续登录
+func (c *AuthController) ChangeExpiredPassword(ctx *gin.Context) {
+	var req service.ExpiredPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "参数格式错误")
+		return
+	}
+
+	// 填充客户端信息（用于登录日志）
+	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.Request.UserAgent()
+
+	loginResp, err := c.authService.ChangeExpiredPassword(&req)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, loginResp)
+}
+
 // Register 用户注册
 func (c *AuthController) Register(ctx *gin.Context) {
 	var req service.RegisterRequest
@@ -80,23 +173,15 @@ func (c *AuthController) Register(ctx *gin.Context) {
 	response.Success(ctx, user)
 }
 
-// RefreshToken 刷新token
+// RefreshToken 使用刷新token换取新的token对
 func (c *AuthController) RefreshToken(ctx *gin.Context) {
-	// 从请求头中获取token
-	authHeader := ctx.GetHeader("Authorization")
-	if authHeader == "" {
-		response.Unauthorized(ctx, "缺少Authorization头")
-		return
-	}
-
-	// 提取token
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-	if tokenString == authHeader {
-		response.Unauthorized(ctx, "无效的Authorization格式")
+	var req RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "参数格式错误")
 		return
 	}
 
-	tokenResp, err := c.authService.RefreshToken(tokenString)
+	tokenResp, err := c.authService.RefreshToken(req.RefreshToken)
 	if err != nil {
 		response.Unauthorized(ctx, err.Error())
 		return
@@ -196,7 +281,7 @@ func (c *AuthController) AssignUserRoles(ctx *gin.Context) {
 		return
 	}
 
-	err = c.authService.AssignUserRoles(uint64(userID), req.RoleIDs)
+	err = c.authService.AssignUserRoles(uint64(userID), req.RoleIDs, ctx.GetBool("is_super_admin"))
 	if err != nil {
 		response.BadRequest(ctx, err.Error())
 		return
@@ -225,13 +310,30 @@ func (c *AuthController) GetUserRoles(ctx *gin.Context) {
 
 // Logout 用户登出
 func (c *AuthController) Logout(ctx *gin.Context) {
-	// 获取用户ID并清除权限缓存
-	userID := ctx.GetUint64("userId")
-	if userID != 0 {
-		permission.ClearUserPermissions(userID)
+	// 未携带有效token时直接视为登出成功
+	claims := middleware.GetClaimsFromContext(ctx)
+	if claims != nil {
+		if err := c.authService.Logout(claims); err != nil {
+			response.InternalServerError(ctx, err.Error())
+			return
+		}
 	}
 
-	// 对于JWT，登出通常由客户端删除token即可
-	// 如果需要服务端记录登出状态，可以将token加入黑名单
 	response.Success(ctx, gin.H{"message": "登出成功"})
 }
+
+// LogoutAll 登出所有会话
+func (c *AuthController) LogoutAll(ctx *gin.Context) {
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == 0 {
+		response.Unauthorized(ctx, "用户未登录")
+		return
+	}
+
+	if err := c.authService.LogoutAllSessions(userID); err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{"message": "已登出所有会话"})
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/LiteMove/light-stack/internal/modules/auth/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
+	"github.com/LiteMove/light-stack/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationController 模拟登录控制器
+type ImpersonationController struct {
+	impersonationService service.ImpersonationService
+}
+
+// NewImpersonationController 创建模拟登录控制器
+func NewImpersonationController(impersonationService service.ImpersonationService) *ImpersonationController {
+	return &ImpersonationController{
+		impersonationService: impersonationService,
+	}
+}
+
+// StartImpersonation 超级管理员模拟指定用户登录
+func (c *ImpersonationController) StartImpersonation(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "用户ID格式错误")
+		return
+	}
+
+	claims := middleware.GetClaimsFromContext(ctx)
+	if claims == nil {
+		response.Unauthorized(ctx, "用户未登录")
+		return
+	}
+
+	result, err := c.impersonationService.Start(claims, userID)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, result)
+}
+
+// EndImpersonation 结束模拟登录
+func (c *ImpersonationController) EndImpersonation(ctx *gin.Context) {
+	claims := middleware.GetClaimsFromContext(ctx)
+	if claims == nil {
+		response.Unauthorized(ctx, "用户未登录")
+		return
+	}
+
+	if err := c.impersonationService.End(claims); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{"message": "已结束模拟登录"})
+}
+
+// GetImpersonation 获取当前会话的模拟登录状态
+func (c *ImpersonationController) GetImpersonation(ctx *gin.Context) {
+	response.Success(ctx, c.impersonationService.GetStatus(middleware.GetClaimsFromContext(ctx)))
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"github.com/LiteMove/light-stack/internal/modules/auth/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
+	"github.com/LiteMove/light-stack/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// MfaController 二次验证控制器
+type MfaController struct {
+	mfaService service.MfaService
+	validator  *validator.Validate
+}
+
+// NewMfaController 创建二次验证控制器
+func NewMfaController(mfaService service.MfaService) *MfaController {
+	return &MfaController{
+		mfaService: mfaService,
+		validator:  validator.New(),
+	}
+}
+
+// MfaCodeRequest 二次验证码请求
+type MfaCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// GetMfaStatus 获取二次验证状态
+func (c *MfaController) GetMfaStatus(ctx *gin.Context) {
+	userID := middleware.GetUserIDFromContext(ctx)
+
+	status, err := c.mfaService.GetStatus(userID)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, status)
+}
+
+// SetupMfa 生成待绑定的TOTP密钥
+func (c *MfaController) SetupMfa(ctx *gin.Context) {
+	userID := middleware.GetUserIDFromContext(ctx)
+
+	setup, err := c.mfaService.BeginSetup(userID)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, setup)
+}
+
+// EnableMfa 校验验证码并启用二次验证
+func (c *MfaController) EnableMfa(ctx *gin.Context) {
+	req, ok := c.bindCodeRequest(ctx)
+	if !ok {
+		return
+	}
+
+	recoveryCodes, err := c.mfaService.Enable(middleware.GetUserIDFromContext(ctx), req.Code)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{
+		"message":       "二次验证已启用",
+		"recoveryCodes": recoveryCodes,
+	})
+}
+
+// DisableMfa 校验验证码并关闭二次验证
+func (c *MfaController) DisableMfa(ctx *gin.Context) {
+	req, ok := c.bindCodeRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.mfaService.Disable(middleware.GetUserIDFromContext(ctx), req.Code); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{
+		"message": "二次验证已关闭",
+	})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+func (c *MfaController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	req, ok := c.bindCodeRequest(ctx)
+	if !ok {
+		return
+	}
+
+	recoveryCodes, err := c.mfaService.RegenerateRecoveryCodes(middleware.GetUserIDFromContext(ctx), req.Code)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{
+		"recoveryCodes": recoveryCodes,
+	})
+}
+
+// bindCodeRequest 绑定并验证二次验证码请求
+func (c *MfaController) bindCodeRequest(ctx *gin.Context) (*MfaCodeRequest, bool) {
+	var req MfaCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return nil, false
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return nil, false
+	}
+
+	return &req, true
+}
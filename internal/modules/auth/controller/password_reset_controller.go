@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"github.com/LiteMove/light-stack/internal/modules/auth/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
+	"github.com/LiteMove/light-stack/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// PasswordResetController 找回密码控制器
+type PasswordResetController struct {
+	resetService service.PasswordResetService
+	validator    *validator.Validate
+}
+
+// NewPasswordResetController 创建找回密码控制器
+func NewPasswordResetController(resetService service.PasswordResetService) *PasswordResetController {
+	return &PasswordResetController{
+		resetService: resetService,
+		validator:    validator.New(),
+	}
+}
+
+// ForgotPasswordRequest 找回密码请求
+type ForgotPasswordRequest struct {
+	Account string `json:"account" validate:"required,max=100"` // 用户名或邮箱
+}
+
+// ResetPasswordRequest 重置密码请求
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,max=128"`
+}
+
+// ForgotPassword 发送重置密码邮件
+func (c *PasswordResetController) ForgotPassword(ctx *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	// 参数验证
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	// 从上下文获取租户ID
+	tenantID, exists := middleware.GetTenantIDFromContext(ctx)
+	if !exists {
+		tenantID = uint64(1) // 默认系统租户
+	}
+
+	if err := c.resetService.ForgotPassword(tenantID, req.Account); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{
+		"message": "如果账号存在且已绑定邮箱，重置密码邮件将很快送达",
+	})
+}
+
+// ResetPassword 使用重置令牌设置新密码
+func (c *PasswordResetController) ResetPassword(ctx *gin.Context) {
+	var req ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	// 参数验证
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	if err := c.resetService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{
+		"message": "密码重置成功，请使用新密码登录",
+	})
+}
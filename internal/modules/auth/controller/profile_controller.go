@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"strconv"
+
 	"github.com/LiteMove/light-stack/internal/modules/auth/service"
 	systemModel "github.com/LiteMove/light-stack/internal/modules/system/model"
 	"github.com/LiteMove/light-stack/internal/shared/middleware"
@@ -35,7 +37,7 @@ type UpdateProfileRequest struct {
 // ProfileChangePasswordRequest 修改密码请求
 type ProfileChangePasswordRequest struct {
 	OldPassword string `json:"oldPassword" validate:"required,min=6"`
-	NewPassword string `json:"newPassword" validate:"required,min=6"`
+	NewPassword string `json:"newPassword" validate:"required,max=128"`
 }
 
 // UpdateTenantConfigRequest 更新租户配置请求
@@ -45,6 +47,10 @@ type UpdateTenantConfigRequest struct {
 	Description string                        `json:"description"`
 	Copyright   string                        `json:"copyright"`
 	FileStorage systemModel.FileStorageConfig `json:"fileStorage" validate:"required"`
+	Security    *systemModel.SecurityConfig   `json:"security"` // 为空时保持原有安全策略
+	Mail        *systemModel.MailConfig       `json:"mail"`     // 为空时保持原有邮件配置
+	// 为空时保持原有密码策略
+	PasswordPolicy *systemModel.PasswordPolicyConfig `json:"passwordPolicy"`
 }
 
 // GetProfile 获取个人信息
@@ -136,6 +142,35 @@ func (c *ProfileController) ChangePassword(ctx *gin.Context) {
 	})
 }
 
+// GetLoginHistory 获取最近的登录记录
+func (c *ProfileController) GetLoginHistory(ctx *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := ctx.Get("userId")
+	if !exists {
+		response.Unauthorized(ctx, "用户未登录")
+		return
+	}
+
+	id := userID.(uint64)
+
+	// 获取条数参数，默认20条，最多100条
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	history, err := c.profileService.GetLoginHistory(id, limit)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, history)
+}
+
 // GetTenantConfig 获取所在租户配置（仅租户管理员）
 func (c *ProfileController) GetTenantConfig(ctx *gin.Context) {
 	// 获取当前用户ID和租户ID
@@ -228,11 +263,14 @@ func (c *ProfileController) UpdateTenantConfig(ctx *gin.Context) {
 
 	// 构建租户配置
 	config := &systemModel.TenantConfig{
-		SystemName:  req.SystemName,
-		Logo:        req.Logo,
-		Description: req.Description,
-		Copyright:   req.Copyright,
-		FileStorage: req.FileStorage,
+		SystemName:     req.SystemName,
+		Logo:           req.Logo,
+		Description:    req.Description,
+		Copyright:      req.Copyright,
+		FileStorage:    req.FileStorage,
+		Security:       req.Security,
+		Mail:           req.Mail,
+		PasswordPolicy: req.PasswordPolicy,
 	}
 
 	// 调用服务更新租户配置
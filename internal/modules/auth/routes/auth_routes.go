@@ -16,20 +16,42 @@ func RegisterAuthRoutes(api *gin.RouterGroup) {
 		auth.POST("/register", globals.AuthCtrl().Register)
 		auth.POST("/refresh", globals.AuthCtrl().RefreshToken)
 		auth.POST("/logout", globals.AuthCtrl().Logout)
+		auth.POST("/logout-all", middleware.Auth(), middleware.RejectImpersonation(globals.AuthCtrl().LogoutAll)) // 登出所有会话
+
+		auth.POST("/mfa/verify", globals.AuthCtrl().VerifyMfa)                    // 登录二次验证
+		auth.POST("/mfa/setup", globals.AuthCtrl().BeginMfaSetup)                 // 登录时绑定身份验证器：获取密钥
+		auth.POST("/mfa/enroll", globals.AuthCtrl().EnrollMfa)                    // 登录时绑定身份验证器：确认并完成登录
+		auth.POST("/forgot-password", globals.PasswordResetCtrl().ForgotPassword) // 发送重置密码邮件
+		auth.POST("/reset-password", globals.PasswordResetCtrl().ResetPassword)   // 使用重置令牌设置新密码
+		auth.POST("/password/expired", globals.AuthCtrl().ChangeExpiredPassword)  // 登录时修改过期密码并继续登录
+
+		// 模拟登录状态查询和结束（由超级管理员在系统管理中发起）
+		auth.GET("/impersonation", middleware.Auth(), globals.ImpersonationCtrl().GetImpersonation)                                                          // 获取模拟登录状态
+		auth.POST("/impersonation/end", middleware.Auth(), middleware.OperationLog(globals.OperationLogSvc()), globals.ImpersonationCtrl().EndImpersonation) // 结束模拟登录
 	}
 
 	// 用户档案路由（需要认证）
 	profile := v1.Group("/profile")
 	profile.Use(middleware.Auth())
+	profile.Use(middleware.OperationLog(globals.OperationLogSvc())) // 记录写操作审计日志
 	{
 		profile.GET("", globals.AuthCtrl().GetProfile)
 		profile.PUT("", globals.AuthCtrl().UpdateProfile)
-		profile.PUT("/password", globals.AuthCtrl().ChangePassword)
+		profile.PUT("/password", middleware.RejectImpersonation(globals.AuthCtrl().ChangePassword))
+		profile.GET("/login-history", globals.ProfileCtrl().GetLoginHistory) // 获取最近登录记录
+
+		// 二次验证
+		profile.GET("/mfa", globals.MfaCtrl().GetMfaStatus)                                                            // 获取二次验证状态
+		profile.POST("/mfa/setup", middleware.RejectImpersonation(globals.MfaCtrl().SetupMfa))                         // 生成待绑定的密钥
+		profile.POST("/mfa/enable", middleware.RejectImpersonation(globals.MfaCtrl().EnableMfa))                       // 确认绑定并启用
+		profile.POST("/mfa/disable", middleware.RejectImpersonation(globals.MfaCtrl().DisableMfa))                     // 关闭二次验证
+		profile.POST("/mfa/recovery-codes", middleware.RejectImpersonation(globals.MfaCtrl().RegenerateRecoveryCodes)) // 重新生成恢复码
+
 		// 用户查看和修改租户配置
 		profile.Use(middleware.TenantMiddleware(globals.TenantSvc()))
 		{
-			profile.GET("/tenant-config", globals.ProfileCtrl().GetTenantConfig)    // 获取所在租户配置
-			profile.PUT("/tenant-config", globals.ProfileCtrl().UpdateTenantConfig) // 更新所在租户配置
+			profile.GET("/tenant-config", globals.ProfileCtrl().GetTenantConfig)                                    // 获取所在租户配置
+			profile.PUT("/tenant-config", middleware.RejectImpersonation(globals.ProfileCtrl().UpdateTenantConfig)) // 更新所在租户配置
 		}
 	}
 }
@@ -1,27 +1,53 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
 	"strings"
+	"time"
 
 	systemModel "github.com/LiteMove/light-stack/internal/modules/system/model"
+	systemService "github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/internal/shared/config"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
 	"github.com/LiteMove/light-stack/internal/shared/utils"
+	"github.com/LiteMove/light-stack/pkg/cache"
 	"github.com/LiteMove/light-stack/pkg/jwt"
 	"github.com/LiteMove/light-stack/pkg/logger"
 	"github.com/LiteMove/light-stack/pkg/permission"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	passwordChallengeKeyPrefix = "pwd_change:" // 登录时强制修改密码的挑战
+	passwordChallengeTTL       = 10 * time.Minute
 )
 
 // AuthService 认证服务接口
 type AuthService interface {
 	// 用户登录
-	Login(tenantID uint64, req *LoginRequest) (*TokenResponse, error)
+	Login(tenantID uint64, req *LoginRequest) (*LoginResponse, error)
+	// 登录二次验证
+	VerifyMfa(req *MfaLoginRequest) (*TokenResponse, error)
+	// 登录时被要求启用二次验证：获取密钥
+	BeginMfaSetup(mfaToken string) (*MfaSetupResponse, error)
+	// 登录时被要求启用二次验证：确认绑定并完成登录
+	EnrollMfa(req *MfaLoginRequest) (*MfaEnrollResponse, error)
+	// 登录时被要求修改密码：设置新密码并继续登录
+	ChangeExpiredPassword(req *ExpiredPasswordRequest) (*LoginResponse, error)
 	// 用户注册
 	Register(tenantID uint64, req *RegisterRequest) (*systemModel.UserProfile, error)
-	// 刷新token
-	RefreshToken(tokenString string) (*TokenResponse, error)
+	// 使用刷新token换取新的token对
+	RefreshToken(refreshToken string) (*TokenResponse, error)
 	// 验证token
 	ValidateToken(tokenString string) (*jwt.Claims, error)
+	// 登出（吊销当前token）
+	Logout(claims *jwt.Claims) error
+	// 登出所有会话
+	LogoutAllSessions(userID uint64) error
 	// 修改密码
 	ChangePassword(userID uint64, oldPassword, newPassword string) error
 	// 获取用户信息
@@ -29,7 +55,7 @@ type AuthService interface {
 	// 更新用户信息
 	UpdateUserProfile(userID uint64, req *UpdateProfileRequest) (*systemModel.UserProfile, error)
 	// 为用户分配角色
-	AssignUserRoles(userID uint64, roleIDs []uint64) error
+	AssignUserRoles(userID uint64, roleIDs []uint64, isSuperAdmin bool) error
 	// 获取用户角色
 	GetUserRoles(userID uint64) ([]*systemModel.Role, error)
 }
@@ -38,17 +64,25 @@ type AuthService interface {
 
 // authService 认证服务实现
 type authService struct {
-	userRepo repository2.UserRepository
-	roleRepo repository2.RoleRepository
-	menuRepo repository2.MenuRepository
+	userRepo     repository2.UserRepository
+	roleRepo     repository2.RoleRepository
+	menuRepo     repository2.MenuRepository
+	tenantRepo   repository2.TenantRepository
+	loginLogRepo repository2.LoginLogRepository
+	mfaService   MfaService
+	pwdPolicy    systemService.PasswordPolicyService
 }
 
 // NewAuthService 创建认证服务实例
-func NewAuthService(userRepo repository2.UserRepository, roleRepo repository2.RoleRepository, menuRepo repository2.MenuRepository) AuthService {
+func NewAuthService(userRepo repository2.UserRepository, roleRepo repository2.RoleRepository, menuRepo repository2.MenuRepository, tenantRepo repository2.TenantRepository, loginLogRepo repository2.LoginLogRepository, mfaService MfaService, pwdPolicy systemService.PasswordPolicyService) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		roleRepo: roleRepo,
-		menuRepo: menuRepo,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		menuRepo:     menuRepo,
+		tenantRepo:   tenantRepo,
+		loginLogRepo: loginLogRepo,
+		mfaService:   mfaService,
+		pwdPolicy:    pwdPolicy,
 	}
 }
 
@@ -56,16 +90,39 @@ func NewAuthService(userRepo repository2.UserRepository, roleRepo repository2.Ro
 type LoginRequest struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
+
+	// 客户端信息，由控制器从请求中填充
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// MfaLoginRequest 登录二次验证请求
+type MfaLoginRequest struct {
+	MfaToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP验证码或恢复码
+
+	// 客户端信息，由控制器从请求中填充
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// ExpiredPasswordRequest 登录时修改过期密码请求
+type ExpiredPasswordRequest struct {
+	PasswordToken string `json:"passwordToken" binding:"required"`
+	NewPassword   string `json:"newPassword" binding:"required,max=128"`
+
+	// 客户端信息，由控制器从请求中填充
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 // RegisterRequest 注册请求
 type RegisterRequest struct {
-	Username string   `json:"username" validate:"required,min=3,max=50"`
-	Email    string   `json:"email" validate:"email,max=100"`
-	Password string   `json:"password" validate:"required,min=6"`
-	Nickname string   `json:"nickname" validate:"max=100"`
-	Phone    string   `json:"phone" validate:"max=20"`
-	RoleIDs  []uint64 `json:"roleIds"` // 分配的角色ID列表
+	Username string `json:"username" validate:"required,min=3,max=50"`
+	Email    string `json:"email" validate:"email,max=100"`
+	Password string `json:"password" validate:"required,max=128"` // 长度和复杂度由租户密码策略校验
+	Nickname string `json:"nickname" validate:"max=100"`
+	Phone    string `json:"phone" validate:"max=20"`
 }
 
 // UpdateProfileRequest 更新用户信息请求
@@ -77,12 +134,36 @@ type UpdateProfileRequest struct {
 
 // TokenResponse token响应
 type TokenResponse struct {
-	AccessToken string `json:"accessToken"`
-	ExpiresIn   int    `json:"expiresIn"`
+	AccessToken      string `json:"accessToken"`
+	RefreshToken     string `json:"refreshToken"`
+	ExpiresIn        int    `json:"expiresIn"`        // 访问token有效期（秒）
+	RefreshExpiresIn int    `json:"refreshExpiresIn"` // 刷新token有效期（秒）
+}
+
+// LoginResponse 登录响应，需要修改密码或二次验证时仅返回挑战token
+type LoginResponse struct {
+	*TokenResponse
+	PasswordChangeRequired bool   `json:"passwordChangeRequired"` // 密码已过期或被管理员重置
+	PasswordToken          string `json:"passwordToken,omitempty"`
+	MfaRequired            bool   `json:"mfaRequired"`
+	MfaSetupRequired       bool   `json:"mfaSetupRequired,omitempty"` // 需先绑定身份验证器
+	MfaToken               string `json:"mfaToken,omitempty"`
+}
+
+// passwordChallenge 登录时强制修改密码的挑战
+type passwordChallenge struct {
+	UserID   uint64 `json:"userId"`
+	TenantID uint64 `json:"tenantId"`
+}
+
+// MfaEnrollResponse 登录时绑定二次验证的响应
+type MfaEnrollResponse struct {
+	*TokenResponse
+	RecoveryCodes []string `json:"recoveryCodes"`
 }
 
 // Login 用户登录
-func (s *authService) Login(tenantID uint64, req *LoginRequest) (*TokenResponse, error) {
+func (s *authService) Login(tenantID uint64, req *LoginRequest) (*LoginResponse, error) {
 	// 参数验证
 	if strings.TrimSpace(req.Username) == "" {
 		return nil, errors.New("用户名不能为空")
@@ -107,46 +188,275 @@ func (s *authService) Login(tenantID uint64, req *LoginRequest) (*TokenResponse,
 
 	if err != nil {
 		logger.WithField("username", req.Username).Warn("Login attempt with invalid username")
+		s.recordLoginLog(tenantID, nil, req, sharedModel.LogStatusFailed, "用户不存在")
 		return nil, errors.New("用户名或密码错误")
 	}
 
+	// 检查用户是否被锁定
+	if user.IsLocked() {
+		logger.WithField("userId", user.ID).Warn("Login attempt with locked user")
+		s.recordLoginLog(tenantID, user, req, sharedModel.LogStatusFailed, "账户已被锁定")
+		if user.Status != 3 && user.LockedUntil != nil {
+			return nil, fmt.Errorf("账户已被锁定，请于%s后重试", user.LockedUntil.Format("2006-01-02 15:04:05"))
+		}
+		return nil, errors.New("账户已被锁定")
+	}
+
 	// 检查用户状态
 	if !user.IsActive() {
 		logger.WithFields(map[string]interface{}{
 			"userId": user.ID,
 			"status": user.Status,
 		}).Warn("Login attempt with inactive user")
+		s.recordLoginLog(tenantID, user, req, sharedModel.LogStatusFailed, "账户已被禁用")
 		return nil, errors.New("账户已被禁用")
 	}
 
-	// 检查用户是否被锁定
-	if user.IsLocked() {
-		logger.WithField("userId", user.ID).Warn("Login attempt with locked user")
-		return nil, errors.New("账户已被锁定")
-	}
-
 	// 验证密码
 	if !utils.VerifyPassword(user.Password, req.Password) {
-		// 记录登录失败
-		s.userRepo.RecordLoginFailure(user.ID)
 		logger.WithField("userId", user.ID).Warn("Login attempt with wrong password")
+		s.recordLoginLog(tenantID, user, req, sharedModel.LogStatusFailed, "密码错误")
+		// 记录登录失败，达到阈值时锁定账户
+		if lockUntil := s.handleLoginFailure(user); lockUntil != nil {
+			return nil, fmt.Errorf("登录失败次数过多，账户已锁定至%s", lockUntil.Format("2006-01-02 15:04:05"))
+		}
 		return nil, errors.New("用户名或密码错误")
 	}
 
-	// 生成JWT token，使用主要角色
-	var userRoles []string
-	for _, role := range user.Roles {
-		userRoles = append(userRoles, role.Code)
+	// 密码已过期或被管理员重置时，须先修改密码
+	if s.pwdPolicy.IsChangeRequired(user) {
+		passwordToken, err := s.createPasswordChallenge(tenantID, user)
+		if err != nil {
+			logger.WithField("userId", user.ID).Error("Failed to create password challenge:", err)
+			s.recordLoginLog(tenantID, user, req, sharedModel.LogStatusFailed, "创建修改密码挑战失败")
+			return nil, errors.New("登录失败")
+		}
+		return &LoginResponse{
+			PasswordChangeRequired: true,
+			PasswordToken:          passwordToken,
+		}, nil
+	}
+
+	return s.continueLogin(tenantID, user, req)
+}
+
+// continueLogin 密码校验通过后继续登录：需要二次验证时返回挑战，否则签发token
+func (s *authService) continueLogin(tenantID uint64, user *systemModel.User, req *LoginRequest) (*LoginResponse, error) {
+	// 已启用二次验证或租户要求启用时，返回二次验证挑战
+	if user.MfaEnabled || s.mfaService.IsRequired(user) {
+		mfaToken, err := s.mfaService.CreateChallenge(tenantID, user)
+		if err != nil {
+			logger.WithField("userId", user.ID).Error("Failed to create mfa challenge:", err)
+			s.recordLoginLog(tenantID, user, req, sharedModel.LogStatusFailed, "创建二次验证失败")
+			return nil, errors.New("登录失败")
+		}
+		return &LoginResponse{
+			MfaRequired:      true,
+			MfaSetupRequired: !user.MfaEnabled,
+			MfaToken:         mfaToken,
+		}, nil
+	}
+
+	tokenResp, err := s.completeLogin(tenantID, user, req)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResponse{TokenResponse: tokenResp}, nil
+}
+
+// VerifyMfa 校验二次验证码并完成登录
+func (s *authService) VerifyMfa(req *MfaLoginRequest) (*TokenResponse, error) {
+	challenge, user, err := s.loadMfaChallenge(req.MfaToken)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.SetupRequired {
+		return nil, errors.New("请先绑定身份验证器")
+	}
+
+	loginReq := &LoginRequest{Username: user.Username, IP: req.IP, UserAgent: req.UserAgent}
+	ok, err := s.mfaService.VerifyCode(user, req.Code)
+	if err != nil {
+		logger.WithField("userId", user.ID).Error("Failed to verify mfa code:", err)
+		return nil, errors.New("二次验证失败")
+	}
+	if !ok {
+		logger.WithField("userId", user.ID).Warn("Login attempt with wrong mfa code")
+		s.recordLoginLog(challenge.TenantID, user, loginReq, sharedModel.LogStatusFailed, "二次验证码错误")
+		// 二次验证失败同样计入登录失败，达到阈值时锁定账户并作废挑战
+		if lockUntil := s.handleLoginFailure(user); lockUntil != nil {
+			s.mfaService.DeleteChallenge(req.MfaToken)
+			return nil, fmt.Errorf("登录失败次数过多，账户已锁定至%s", lockUntil.Format("2006-01-02 15:04:05"))
+		}
+		return nil, errors.New("验证码错误")
+	}
+
+	s.mfaService.DeleteChallenge(req.MfaToken)
+	return s.completeLogin(challenge.TenantID, user, loginReq)
+}
+
+// BeginMfaSetup 登录时被要求启用二次验证，生成待绑定的密钥
+func (s *authService) BeginMfaSetup(mfaToken string) (*MfaSetupResponse, error) {
+	challenge, _, err := s.loadMfaChallenge(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.SetupRequired {
+		return nil, errors.New("已绑定身份验证器")
+	}
+
+	return s.mfaService.BeginSetup(challenge.UserID)
+}
+
+// EnrollMfa 登录时被要求启用二次验证，确认绑定后完成登录
+func (s *authService) EnrollMfa(req *MfaLoginRequest) (*MfaEnrollResponse, error) {
+	challenge, user, err := s.loadMfaChallenge(req.MfaToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.SetupRequired {
+		return nil, errors.New("已绑定身份验证器")
+	}
+
+	recoveryCodes, err := s.mfaService.Enable(user.ID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mfaService.DeleteChallenge(req.MfaToken)
+	loginReq := &LoginRequest{Username: user.Username, IP: req.IP, UserAgent: req.UserAgent}
+	tokenResp, err := s.completeLogin(challenge.TenantID, user, loginReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MfaEnrollResponse{
+		TokenResponse: tokenResp,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// ChangeExpiredPassword 登录时被要求修改密码，设置新密码后继续登录流程
+func (s *authService) ChangeExpiredPassword(req *ExpiredPasswordRequest) (*LoginResponse, error) {
+	challenge, err := s.getPasswordChallenge(req.PasswordToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByIDWithRoles(challenge.UserID)
+	if err != nil {
+		s.deletePasswordChallenge(req.PasswordToken)
+		return nil, errors.New("用户不存在")
+	}
+	if user.IsLocked() || !user.IsActive() {
+		s.deletePasswordChallenge(req.PasswordToken)
+		return nil, errors.New("账户已被锁定或禁用")
+	}
+
+	if err := s.pwdPolicy.ValidateNewPassword(user.TenantID, user, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		logger.WithField("userId", user.ID).Error("Failed to hash new password:", err)
+		return nil, errors.New("密码修改失败")
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword); err != nil {
+		logger.WithField("userId", user.ID).Error("Failed to update password:", err)
+		return nil, errors.New("密码修改失败")
+	}
+	s.deletePasswordChallenge(req.PasswordToken)
+	logger.WithField("userId", user.ID).Info("Expired password changed at login")
+
+	loginReq := &LoginRequest{Username: user.Username, IP: req.IP, UserAgent: req.UserAgent}
+	return s.continueLogin(challenge.TenantID, user, loginReq)
+}
+
+// createPasswordChallenge 创建登录时修改密码的挑战，返回挑战token
+func (s *authService) createPasswordChallenge(tenantID uint64, user *systemModel.User) (string, error) {
+	token, err := utils.GenerateRandomString(43)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(passwordChallenge{UserID: user.ID, TenantID: tenantID})
+	if err != nil {
+		return "", err
+	}
+	if err := cache.Set(passwordChallengeKeyPrefix+token, data, passwordChallengeTTL); err != nil {
+		return "", fmt.Errorf("保存修改密码挑战失败: %w", err)
+	}
+	return token, nil
+}
+
+// getPasswordChallenge 获取登录时修改密码的挑战
+func (s *authService) getPasswordChallenge(token string) (*passwordChallenge, error) {
+	data, err := cache.Get(passwordChallengeKeyPrefix + token)
+	if errors.Is(err, redis.Nil) {
+		return nil, errors.New("修改密码已超时，请重新登录")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取修改密码挑战失败: %w", err)
+	}
+
+	var challenge passwordChallenge
+	if err := json.Unmarshal([]byte(data), &challenge); err != nil {
+		return nil, errors.New("修改密码已超时，请重新登录")
+	}
+	return &challenge, nil
+}
+
+// deletePasswordChallenge 删除登录时修改密码的挑战
+func (s *authService) deletePasswordChallenge(token string) {
+	if err := cache.Del(passwordChallengeKeyPrefix + token); err != nil {
+		logger.Warn("Failed to delete password challenge:", err)
+	}
+}
+
+// loadMfaChallenge 获取二次验证挑战及对应用户，并重新检查用户状态
+func (s *authService) loadMfaChallenge(mfaToken string) (*MfaChallenge, *systemModel.User, error) {
+	challenge, err := s.mfaService.GetChallenge(mfaToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByIDWithRoles(challenge.UserID)
+	if err != nil {
+		s.mfaService.DeleteChallenge(mfaToken)
+		return nil, nil, errors.New("用户不存在")
+	}
+	if user.IsLocked() {
+		s.mfaService.DeleteChallenge(mfaToken)
+		return nil, nil, errors.New("账户已被锁定")
+	}
+	if !user.IsActive() {
+		s.mfaService.DeleteChallenge(mfaToken)
+		return nil, nil, errors.New("账户已被禁用")
+	}
+
+	return challenge, user, nil
+}
+
+// completeLogin 签发token并记录登录成功
+func (s *authService) completeLogin(tenantID uint64, user *systemModel.User, req *LoginRequest) (*TokenResponse, error) {
+	// 每次登录创建新的token族
+	familyID, err := jwt.NewTokenFamily()
+	if err != nil {
+		logger.WithField("userId", user.ID).Error("Failed to create token family:", err)
+		s.recordLoginLog(tenantID, user, req, sharedModel.LogStatusFailed, "生成token失败")
+		return nil, errors.New("登录失败")
 	}
 
-	token, err := jwt.GenerateToken(user.ID, user.Username, userRoles)
+	tokenResp, err := s.issueTokens(user, familyID)
 	if err != nil {
 		logger.WithField("userId", user.ID).Error("Failed to generate token:", err)
+		s.recordLoginLog(tenantID, user, req, sharedModel.LogStatusFailed, "生成token失败")
 		return nil, errors.New("登录失败")
 	}
 
 	// 更新最后登录信息
-	if err := s.userRepo.UpdateLoginInfo(user.ID, ""); err != nil {
+	if err := s.userRepo.UpdateLoginInfo(user.ID, req.IP); err != nil {
 		logger.WithField("userId", user.ID).Warn("Failed to update login info:", err)
 	}
 
@@ -156,17 +466,122 @@ func (s *authService) Login(tenantID uint64, req *LoginRequest) (*TokenResponse,
 	}
 
 	logger.WithField("userId", user.ID).Info("User logged in successfully")
+	s.recordLoginLog(tenantID, user, req, sharedModel.LogStatusSuccess, "登录成功")
+
+	return tokenResp, nil
+}
+
+// issueTokens 在指定token族下签发访问token和刷新token
+func (s *authService) issueTokens(user *systemModel.User, familyID string) (*TokenResponse, error) {
+	var userRoles []string
+	for _, role := range user.Roles {
+		userRoles = append(userRoles, role.Code)
+	}
+
+	accessToken, err := jwt.GenerateToken(user.ID, user.TenantID, user.Username, userRoles, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := jwt.GenerateRefreshToken(user.ID, familyID)
+	if err != nil {
+		return nil, err
+	}
 
 	return &TokenResponse{
-		AccessToken: token,
-		ExpiresIn:   3600, // 1小时
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        jwt.ExpiresIn(),
+		RefreshExpiresIn: int(jwt.RefreshExpiresIn().Seconds()),
 	}, nil
 }
 
+// handleLoginFailure 记录登录失败，窗口期内失败次数达到阈值时锁定账户，返回锁定截止时间
+func (s *authService) handleLoginFailure(user *systemModel.User) *time.Time {
+	policy := s.getSecurityConfig(user.TenantID)
+
+	window := time.Duration(policy.LoginFailureWindow) * time.Minute
+	failures, err := s.userRepo.RecordLoginFailure(user.ID, time.Now().Add(-window))
+	if err != nil {
+		logger.WithField("userId", user.ID).Error("Failed to record login failure:", err)
+		return nil
+	}
+	if failures < policy.LoginMaxFailures {
+		return nil
+	}
+
+	// 重复锁定时锁定时长翻倍，不超过最长锁定时长
+	lockMinutes := policy.LoginLockMinutes
+	for i := 0; i < user.LockCount && lockMinutes < policy.LoginMaxLockMinutes; i++ {
+		lockMinutes *= 2
+	}
+	if lockMinutes > policy.LoginMaxLockMinutes {
+		lockMinutes = policy.LoginMaxLockMinutes
+	}
+
+	lockUntil := time.Now().Add(time.Duration(lockMinutes) * time.Minute)
+	if err := s.userRepo.LockUser(user.ID, lockUntil); err != nil {
+		logger.WithField("userId", user.ID).Error("Failed to lock user:", err)
+		return nil
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"userId":      user.ID,
+		"failures":    failures,
+		"lockMinutes": lockMinutes,
+	}).Warn("User locked due to repeated login failures")
+	return &lockUntil
+}
+
+// getSecurityConfig 获取租户安全策略，读取失败时使用默认策略
+func (s *authService) getSecurityConfig(tenantID uint64) *systemModel.SecurityConfig {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err == nil {
+		if policy, err := tenant.GetSecurityConfig(); err == nil {
+			return policy
+		}
+	}
+	logger.WithField("tenantId", tenantID).Warn("Failed to load tenant security config, using defaults")
+	return systemModel.DefaultSecurityConfig()
+}
+
+// recordLoginLog 记录登录日志，user为nil表示用户名不存在
+func (s *authService) recordLoginLog(tenantID uint64, user *systemModel.User, req *LoginRequest, status int, message string) {
+	browser, os := utils.ParseUserAgent(req.UserAgent)
+	userAgent := req.UserAgent
+	if runes := []rune(userAgent); len(runes) > 500 {
+		userAgent = string(runes[:500])
+	}
+
+	loginLog := &sharedModel.LoginLog{
+		TenantID:  tenantID,
+		Username:  req.Username,
+		IP:        req.IP,
+		UserAgent: userAgent,
+		Location:  utils.GetIPLocation(req.IP),
+		Browser:   browser,
+		OS:        os,
+		Status:    status,
+		Message:   message,
+		LoginTime: time.Now(),
+	}
+	if user != nil {
+		loginLog.UserID = &user.ID
+		loginLog.Username = user.Username
+	}
+	if runes := []rune(loginLog.Username); len(runes) > 50 {
+		loginLog.Username = string(runes[:50])
+	}
+
+	if err := s.loginLogRepo.Create(loginLog); err != nil {
+		logger.WithField("username", req.Username).Error("Failed to save login log:", err)
+	}
+}
+
 // Register 用户注册
 func (s *authService) Register(tenantID uint64, req *RegisterRequest) (*systemModel.UserProfile, error) {
 	// 参数验证
-	if err := s.validateRegisterRequest(req); err != nil {
+	if err := s.validateRegisterRequest(tenantID, req); err != nil {
 		return nil, err
 	}
 
@@ -200,11 +615,13 @@ func (s *authService) Register(tenantID uint64, req *RegisterRequest) (*systemMo
 	}
 
 	// 创建用户
+	now := time.Now()
 	user := &systemModel.User{
-		Username: req.Username,
-		Password: hashedPassword,
-		Nickname: req.Nickname,
-		Status:   1, // 启用状态
+		Username:          req.Username,
+		Password:          hashedPassword,
+		Nickname:          req.Nickname,
+		Status:            1, // 启用状态
+		PasswordChangedAt: &now,
 	}
 
 	// 处理可选字段
@@ -222,19 +639,8 @@ func (s *authService) Register(tenantID uint64, req *RegisterRequest) (*systemMo
 		return nil, errors.New("注册失败")
 	}
 
-	// 分配角色
-	if len(req.RoleIDs) > 0 {
-		if err := s.roleRepo.AssignRolesToUser(user.ID, req.RoleIDs); err != nil {
-			logger.WithField("userId", user.ID).Error("Failed to assign roles:", err)
-			// 注册已成功，角色分配失败只记录警告
-		}
-	} else {
-		// 如果没有指定角色，分配默认用户角色
-		userRole, err := s.roleRepo.GetByCode("user")
-		if err == nil {
-			s.roleRepo.AssignRolesToUser(user.ID, []uint64{userRole.ID})
-		}
-	}
+	// 分配配置的注册默认角色，注册已成功，角色分配失败只记录警告
+	s.assignRegisterRole(tenantID, user.ID)
 
 	logger.WithField("userId", user.ID).Info("User registered successfully")
 
@@ -244,40 +650,40 @@ func (s *authService) Register(tenantID uint64, req *RegisterRequest) (*systemMo
 	return &profile, nil
 }
 
-// RefreshToken 刷新token
-func (s *authService) RefreshToken(tokenString string) (*TokenResponse, error) {
-	// 解析原token
-	claims, err := jwt.ParseToken(tokenString)
+// RefreshToken 使用刷新token换取新的token对，旧的刷新token随即失效
+func (s *authService) RefreshToken(refreshToken string) (*TokenResponse, error) {
+	session, err := jwt.UseRefreshToken(refreshToken)
 	if err != nil {
-		return nil, errors.New("无效的token")
+		if errors.Is(err, jwt.ErrRefreshTokenReused) {
+			logger.Warn("Refresh token reuse detected, token family revoked")
+			return nil, errors.New("刷新token已失效，请重新登录")
+		}
+		if errors.Is(err, jwt.ErrRefreshTokenInvalid) {
+			return nil, errors.New("刷新token已失效，请重新登录")
+		}
+		logger.Error("Failed to use refresh token:", err)
+		return nil, errors.New("刷新token失败")
 	}
 
 	// 检查用户是否仍然有效
-	user, err := s.userRepo.GetByIDWithRoles(claims.UserID)
+	user, err := s.userRepo.GetByIDWithRoles(session.UserID)
 	if err != nil {
+		_ = jwt.RevokeTokenFamily(session.FamilyID)
 		return nil, errors.New("用户不存在")
 	}
 
 	if !user.IsActive() {
+		_ = jwt.RevokeTokenFamily(session.FamilyID)
 		return nil, errors.New("账户已被禁用")
 	}
 
-	// 生成新token
-	var userRoles []string
-	for _, role := range user.Roles {
-		userRoles = append(userRoles, role.Code)
-	}
-
-	newToken, err := jwt.GenerateToken(user.ID, user.Username, userRoles)
+	tokenResp, err := s.issueTokens(user, session.FamilyID)
 	if err != nil {
 		logger.WithField("userId", user.ID).Error("Failed to refresh token:", err)
 		return nil, errors.New("刷新token失败")
 	}
 
-	return &TokenResponse{
-		AccessToken: newToken,
-		ExpiresIn:   3600,
-	}, nil
+	return tokenResp, nil
 }
 
 // ValidateToken 验证token
@@ -300,6 +706,33 @@ func (s *authService) ValidateToken(tokenString string) (*jwt.Claims, error) {
 	return claims, nil
 }
 
+// Logout 登出，吊销当前token及其刷新token族并清除权限缓存
+func (s *authService) Logout(claims *jwt.Claims) error {
+	if err := jwt.RevokeToken(claims); err != nil {
+		logger.WithField("userId", claims.UserID).Error("Failed to revoke token:", err)
+		return errors.New("登出失败")
+	}
+	if err := jwt.RevokeTokenFamily(claims.FamilyID); err != nil {
+		logger.WithField("userId", claims.UserID).Error("Failed to revoke token family:", err)
+		return errors.New("登出失败")
+	}
+
+	permission.ClearUserPermissions(claims.UserID)
+	return nil
+}
+
+// LogoutAllSessions 登出所有会话，吊销用户已签发的全部token
+func (s *authService) LogoutAllSessions(userID uint64) error {
+	if err := jwt.RevokeUserTokens(userID); err != nil {
+		logger.WithField("userId", userID).Error("Failed to revoke user tokens:", err)
+		return errors.New("登出失败")
+	}
+
+	permission.ClearUserPermissions(userID)
+	logger.WithField("userId", userID).Info("All sessions logged out")
+	return nil
+}
+
 // ChangePassword 修改密码
 func (s *authService) ChangePassword(userID uint64, oldPassword, newPassword string) error {
 	// 获取用户信息
@@ -313,8 +746,8 @@ func (s *authService) ChangePassword(userID uint64, oldPassword, newPassword str
 		return errors.New("原密码错误")
 	}
 
-	// 验证新密码强度
-	if err := utils.ValidatePasswordStrength(newPassword); err != nil {
+	// 校验密码策略
+	if err := s.pwdPolicy.ValidateNewPassword(user.TenantID, user, newPassword); err != nil {
 		return err
 	}
 
@@ -418,9 +851,23 @@ func (s *authService) UpdateUserProfile(userID uint64, req *UpdateProfileRequest
 	return &profile, nil
 }
 
-// AssignUserRoles 为用户分配角色
-func (s *authService) AssignUserRoles(userID uint64, roleIDs []uint64) error {
-	return s.roleRepo.UpdateUserRoles(userID, roleIDs)
+// AssignUserRoles 为用户分配角色，超级管理员角色和系统角色仅超级管理员可分配
+func (s *authService) AssignUserRoles(userID uint64, roleIDs []uint64, isSuperAdmin bool) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return errors.New("用户不存在")
+	}
+
+	if err := systemService.CheckAssignRoles(s.roleRepo, user, roleIDs, isSuperAdmin); err != nil {
+		return err
+	}
+
+	if err := s.roleRepo.UpdateUserRoles(userID, roleIDs); err != nil {
+		return err
+	}
+
+	permission.InvalidateUsers(userID)
+	return nil
 }
 
 // GetUserRoles 获取用户角色
@@ -428,8 +875,30 @@ func (s *authService) GetUserRoles(userID uint64) ([]*systemModel.Role, error) {
 	return s.roleRepo.GetUserRoles(userID)
 }
 
+// assignRegisterRole 为自助注册的用户分配配置的默认角色，不会分配超级管理员角色
+func (s *authService) assignRegisterRole(tenantID, userID uint64) {
+	roleCode := config.Get().Tenant.RegisterRoleCode
+	if roleCode == "" {
+		return
+	}
+
+	role, err := s.roleRepo.GetByCode(tenantID, roleCode)
+	if err != nil {
+		logger.WithField("roleCode", roleCode).Warn("Register role not found:", err)
+		return
+	}
+	if role.ID == systemModel.SuperAdminId {
+		logger.WithField("roleCode", roleCode).Error("Refused to assign super admin role on register")
+		return
+	}
+
+	if err := s.roleRepo.AssignRolesToUser(userID, []uint64{role.ID}); err != nil {
+		logger.WithField("userId", userID).Error("Failed to assign register role:", err)
+	}
+}
+
 // validateRegisterRequest 验证注册请求
-func (s *authService) validateRegisterRequest(req *RegisterRequest) error {
+func (s *authService) validateRegisterRequest(tenantID uint64, req *RegisterRequest) error {
 	if strings.TrimSpace(req.Username) == "" {
 		return errors.New("用户名不能为空")
 	}
@@ -440,8 +909,8 @@ func (s *authService) validateRegisterRequest(req *RegisterRequest) error {
 		return errors.New("密码不能为空")
 	}
 
-	// 验证密码强度
-	if err := utils.ValidatePasswordStrength(req.Password); err != nil {
+	// 校验密码策略
+	if err := s.pwdPolicy.ValidateNewPassword(tenantID, nil, req.Password); err != nil {
 		return err
 	}
 
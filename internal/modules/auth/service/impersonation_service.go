@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/pkg/jwt"
+	"github.com/LiteMove/light-stack/pkg/logger"
+)
+
+// ImpersonationService 模拟登录服务接口
+type ImpersonationService interface {
+	// 超级管理员以指定用户身份签发限时访问token
+	Start(operator *jwt.Claims, userID uint64) (*ImpersonationResponse, error)
+	// 结束模拟登录，吊销模拟token
+	End(claims *jwt.Claims) error
+	// 获取当前token的模拟登录状态
+	GetStatus(claims *jwt.Claims) *ImpersonationStatus
+}
+
+// ImpersonationResponse 模拟登录响应，不含刷新token，到期后需重新发起
+type ImpersonationResponse struct {
+	AccessToken string               `json:"accessToken"`
+	ExpiresIn   int                  `json:"expiresIn"` // 访问token有效期（秒）
+	Status      *ImpersonationStatus `json:"impersonation"`
+}
+
+// ImpersonationStatus 模拟登录状态，供前端展示模拟提示横幅
+type ImpersonationStatus struct {
+	Active       bool              `json:"active"`
+	UserID       uint64            `json:"userId,omitempty"`
+	Username     string            `json:"username,omitempty"`
+	TenantID     uint64            `json:"tenantId,omitempty"`
+	Impersonator *jwt.Impersonator `json:"impersonator,omitempty"`
+	ExpiresAt    *time.Time        `json:"expiresAt,omitempty"`
+}
+
+// impersonationService 模拟登录服务实现
+type impersonationService struct {
+	userRepo repository2.UserRepository
+}
+
+// NewImpersonationService 创建模拟登录服务
+func NewImpersonationService(userRepo repository2.UserRepository) ImpersonationService {
+	return &impersonationService{
+		userRepo: userRepo,
+	}
+}
+
+// Start 以指定用户身份签发模拟token，token中记录实际操作人
+func (s *impersonationService) Start(operator *jwt.Claims, userID uint64) (*ImpersonationResponse, error) {
+	if operator.IsImpersonated() {
+		return nil, errors.New("模拟登录中不能再次模拟其他用户")
+	}
+	if operator.UserID == userID {
+		return nil, errors.New("不能模拟自己")
+	}
+
+	user, err := s.userRepo.GetByIDWithRoles(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	if !user.IsActive() {
+		return nil, errors.New("用户已被禁用或锁定")
+	}
+
+	if user.IsSuperAdmin() {
+		return nil, errors.New("不能模拟超级管理员")
+	}
+
+	roles := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roles = append(roles, role.Code)
+	}
+
+	impersonator := &jwt.Impersonator{
+		UserID:   operator.UserID,
+		Username: operator.Username,
+		TenantID: operator.TenantID,
+	}
+	claims, token, err := jwt.GenerateImpersonationToken(user.ID, user.TenantID, user.Username, roles, impersonator)
+	if err != nil {
+		logger.WithField("userId", user.ID).Error("Failed to generate impersonation token:", err)
+		return nil, errors.New("签发模拟登录token失败")
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"impersonatorId": operator.UserID,
+		"userId":         user.ID,
+		"tenantId":       user.TenantID,
+	}).Info("Impersonation started")
+
+	return &ImpersonationResponse{
+		AccessToken: token,
+		ExpiresIn:   int(jwt.ImpersonationExpiresIn().Seconds()),
+		Status:      s.GetStatus(claims),
+	}, nil
+}
+
+// End 吊销模拟token，前端随后恢复操作人原有的token
+func (s *impersonationService) End(claims *jwt.Claims) error {
+	if !claims.IsImpersonated() {
+		return errors.New("当前不在模拟登录中")
+	}
+	if err := jwt.RevokeToken(claims); err != nil {
+		logger.WithField("userId", claims.UserID).Error("Failed to revoke impersonation token:", err)
+		return errors.New("结束模拟登录失败")
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"impersonatorId": claims.Impersonator.UserID,
+		"userId":         claims.UserID,
+	}).Info("Impersonation ended")
+	return nil
+}
+
+// GetStatus 获取模拟登录状态
+func (s *impersonationService) GetStatus(claims *jwt.Claims) *ImpersonationStatus {
+	if claims == nil || !claims.IsImpersonated() {
+		return &ImpersonationStatus{Active: false}
+	}
+
+	status := &ImpersonationStatus{
+		Active:       true,
+		UserID:       claims.UserID,
+		Username:     claims.Username,
+		TenantID:     claims.TenantID,
+		Impersonator: claims.Impersonator,
+	}
+	if claims.ExpiresAt != nil {
+		status.ExpiresAt = &claims.ExpiresAt.Time
+	}
+	return status
+}
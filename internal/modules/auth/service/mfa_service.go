@@ -0,0 +1,355 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	systemModel "github.com/LiteMove/light-stack/internal/modules/system/model"
+	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/internal/shared/config"
+	"github.com/LiteMove/light-stack/internal/shared/utils"
+	"github.com/LiteMove/light-stack/pkg/cache"
+	"github.com/LiteMove/light-stack/pkg/logger"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	mfaSetupKeyPrefix     = "mfa:setup:"     // 待确认的TOTP密钥
+	mfaChallengeKeyPrefix = "mfa:challenge:" // 登录二次验证挑战
+	mfaLastStepKeyPrefix  = "mfa:last_step:" // 最近一次使用的TOTP时间步，防止验证码重放
+
+	mfaSetupTTL        = 10 * time.Minute
+	mfaChallengeTTL    = 5 * time.Minute
+	mfaRecoveryCodeNum = 10
+)
+
+// MfaService 二次验证服务接口
+type MfaService interface {
+	// 获取二次验证状态
+	GetStatus(userID uint64) (*MfaStatus, error)
+	// 生成待确认的TOTP密钥
+	BeginSetup(userID uint64) (*MfaSetupResponse, error)
+	// 校验验证码并启用二次验证，返回恢复码
+	Enable(userID uint64, code string) ([]string, error)
+	// 校验验证码并关闭二次验证
+	Disable(userID uint64, code string) error
+	// 重新生成恢复码
+	RegenerateRecoveryCodes(userID uint64, code string) ([]string, error)
+	// 校验TOTP验证码或恢复码（恢复码使用后失效）
+	VerifyCode(user *systemModel.User, code string) (bool, error)
+	// 检查用户是否被要求启用二次验证
+	IsRequired(user *systemModel.User) bool
+
+	// 登录二次验证挑战
+	CreateChallenge(tenantID uint64, user *systemModel.User) (string, error)
+	GetChallenge(token string) (*MfaChallenge, error)
+	DeleteChallenge(token string)
+}
+
+// MfaStatus 二次验证状态
+type MfaStatus struct {
+	Enabled            bool `json:"enabled"`
+	Required           bool `json:"required"`
+	RecoveryCodesCount int  `json:"recoveryCodesCount"`
+}
+
+// MfaSetupResponse 二次验证密钥信息
+type MfaSetupResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauthUri"`
+}
+
+// MfaChallenge 登录二次验证挑战
+type MfaChallenge struct {
+	UserID        uint64 `json:"userId"`
+	TenantID      uint64 `json:"tenantId"`
+	SetupRequired bool   `json:"setupRequired"` // 用户尚未启用但被要求启用
+}
+
+// mfaService 二次验证服务实现
+type mfaService struct {
+	userRepo   repository2.UserRepository
+	tenantRepo repository2.TenantRepository
+}
+
+// NewMfaService 创建二次验证服务
+func NewMfaService(userRepo repository2.UserRepository, tenantRepo repository2.TenantRepository) MfaService {
+	return &mfaService{
+		userRepo:   userRepo,
+		tenantRepo: tenantRepo,
+	}
+}
+
+// GetStatus 获取二次验证状态
+func (s *mfaService) GetStatus(userID uint64) (*MfaStatus, error) {
+	user, err := s.userRepo.GetByIDWithRoles(userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %w", err)
+	}
+
+	return &MfaStatus{
+		Enabled:            user.MfaEnabled,
+		Required:           s.IsRequired(user),
+		RecoveryCodesCount: len(user.GetRecoveryCodeHashes()),
+	}, nil
+}
+
+// BeginSetup 生成待确认的TOTP密钥，确认前不会生效
+func (s *mfaService) BeginSetup(userID uint64) (*MfaSetupResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %w", err)
+	}
+	if user.MfaEnabled {
+		return nil, errors.New("已启用二次验证，请先关闭后再重新绑定")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("生成密钥失败: %w", err)
+	}
+
+	if err := cache.Set(mfaSetupKeyPrefix+strconv.FormatUint(userID, 10), secret, mfaSetupTTL); err != nil {
+		return nil, fmt.Errorf("保存密钥失败: %w", err)
+	}
+
+	issuer := "Light Stack"
+	if cfg := config.Get(); cfg != nil && cfg.App.Name != "" {
+		issuer = cfg.App.Name
+	}
+
+	return &MfaSetupResponse{
+		Secret:     secret,
+		OtpauthURI: utils.TOTPAuthURI(issuer, user.Username, secret),
+	}, nil
+}
+
+// Enable 校验验证码并启用二次验证，返回恢复码（仅展示一次）
+func (s *mfaService) Enable(userID uint64, code string) ([]string, error) {
+	setupKey := mfaSetupKeyPrefix + strconv.FormatUint(userID, 10)
+	secret, err := cache.Get(setupKey)
+	if errors.Is(err, redis.Nil) {
+		return nil, errors.New("密钥已过期，请重新获取")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取密钥失败: %w", err)
+	}
+
+	step, ok := utils.ValidateTOTPCode(secret, code, time.Now())
+	if !ok {
+		return nil, errors.New("验证码错误")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateMfa(userID, true, secret, hashes); err != nil {
+		return nil, fmt.Errorf("启用二次验证失败: %w", err)
+	}
+
+	s.markStepUsed(userID, step)
+	if err := cache.Del(setupKey); err != nil {
+		logger.WithField("userId", userID).Warn("Failed to delete mfa setup secret:", err)
+	}
+
+	logger.WithField("userId", userID).Info("MFA enabled")
+	return codes, nil
+}
+
+// Disable 校验验证码并关闭二次验证
+func (s *mfaService) Disable(userID uint64, code string) error {
+	user, err := s.userRepo.GetByIDWithRoles(userID)
+	if err != nil {
+		return fmt.Errorf("获取用户信息失败: %w", err)
+	}
+	if !user.MfaEnabled {
+		return errors.New("未启用二次验证")
+	}
+	if s.IsRequired(user) {
+		return errors.New("租户要求管理员启用二次验证，无法关闭")
+	}
+
+	ok, err := s.VerifyCode(user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("验证码错误")
+	}
+
+	if err := s.userRepo.UpdateMfa(userID, false, "", ""); err != nil {
+		return fmt.Errorf("关闭二次验证失败: %w", err)
+	}
+
+	logger.WithField("userId", userID).Info("MFA disabled")
+	return nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，原有恢复码全部失效
+func (s *mfaService) RegenerateRecoveryCodes(userID uint64, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %w", err)
+	}
+	if !user.MfaEnabled {
+		return nil, errors.New("未启用二次验证")
+	}
+
+	// 仅接受TOTP验证码，避免用恢复码换取新的恢复码
+	step, ok := utils.ValidateTOTPCode(user.MfaSecret, code, time.Now())
+	if !ok || !s.markStepUsed(userID, step) {
+		return nil, errors.New("验证码错误")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateRecoveryCodes(userID, hashes); err != nil {
+		return nil, fmt.Errorf("更新恢复码失败: %w", err)
+	}
+
+	return codes, nil
+}
+
+// VerifyCode 校验TOTP验证码或恢复码（恢复码使用后失效）
+func (s *mfaService) VerifyCode(user *systemModel.User, code string) (bool, error) {
+	if !user.MfaEnabled || user.MfaSecret == "" {
+		return false, nil
+	}
+
+	// TOTP验证码，同一时间步只能使用一次
+	if step, ok := utils.ValidateTOTPCode(user.MfaSecret, code, time.Now()); ok {
+		return s.markStepUsed(user.ID, step), nil
+	}
+
+	// 恢复码
+	codeHash := utils.HashRecoveryCode(code)
+	hashes := user.GetRecoveryCodeHashes()
+	for i, hash := range hashes {
+		if !utils.SecureCompare(hash, codeHash) {
+			continue
+		}
+
+		remaining := append(hashes[:i:i], hashes[i+1:]...)
+		data, err := json.Marshal(remaining)
+		if err != nil {
+			return false, err
+		}
+		if err := s.userRepo.UpdateRecoveryCodes(user.ID, string(data)); err != nil {
+			return false, fmt.Errorf("更新恢复码失败: %w", err)
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"userId":    user.ID,
+			"remaining": len(remaining),
+		}).Info("MFA recovery code used")
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// IsRequired 检查租户是否要求该用户启用二次验证
+func (s *mfaService) IsRequired(user *systemModel.User) bool {
+	if !user.IsAdmin() {
+		return false
+	}
+
+	tenant, err := s.tenantRepo.GetByID(user.TenantID)
+	if err != nil {
+		return false
+	}
+	policy, err := tenant.GetSecurityConfig()
+	if err != nil {
+		return false
+	}
+	return policy.RequireAdminMfa
+}
+
+// CreateChallenge 创建登录二次验证挑战，返回挑战token
+func (s *mfaService) CreateChallenge(tenantID uint64, user *systemModel.User) (string, error) {
+	token, err := utils.GenerateRandomString(43)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := MfaChallenge{
+		UserID:        user.ID,
+		TenantID:      tenantID,
+		SetupRequired: !user.MfaEnabled,
+	}
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return "", err
+	}
+
+	if err := cache.Set(mfaChallengeKeyPrefix+token, data, mfaChallengeTTL); err != nil {
+		return "", fmt.Errorf("保存二次验证挑战失败: %w", err)
+	}
+	return token, nil
+}
+
+// GetChallenge 获取登录二次验证挑战
+func (s *mfaService) GetChallenge(token string) (*MfaChallenge, error) {
+	data, err := cache.Get(mfaChallengeKeyPrefix + token)
+	if errors.Is(err, redis.Nil) {
+		return nil, errors.New("二次验证已过期，请重新登录")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取二次验证挑战失败: %w", err)
+	}
+
+	var challenge MfaChallenge
+	if err := json.Unmarshal([]byte(data), &challenge); err != nil {
+		return nil, errors.New("二次验证已过期，请重新登录")
+	}
+	return &challenge, nil
+}
+
+// DeleteChallenge 删除登录二次验证挑战
+func (s *mfaService) DeleteChallenge(token string) {
+	if err := cache.Del(mfaChallengeKeyPrefix + token); err != nil {
+		logger.Warn("Failed to delete mfa challenge:", err)
+	}
+}
+
+// markStepUsed 记录已使用的TOTP时间步，时间步不大于上次记录时视为重放
+func (s *mfaService) markStepUsed(userID uint64, step int64) bool {
+	key := mfaLastStepKeyPrefix + strconv.FormatUint(userID, 10)
+	if value, err := cache.Get(key); err == nil {
+		if last, err := strconv.ParseInt(value, 10, 64); err == nil && step <= last {
+			return false
+		}
+	}
+
+	if err := cache.Set(key, step, 2*time.Minute); err != nil {
+		logger.WithField("userId", userID).Warn("Failed to record mfa step:", err)
+	}
+	return true
+}
+
+// newRecoveryCodes 生成恢复码，返回明文列表和哈希JSON
+func newRecoveryCodes() ([]string, string, error) {
+	codes, err := utils.GenerateRecoveryCodes(mfaRecoveryCodeNum)
+	if err != nil {
+		return nil, "", fmt.Errorf("生成恢复码失败: %w", err)
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, utils.HashRecoveryCode(code))
+	}
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, "", err
+	}
+	return codes, string(data), nil
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	systemModel "github.com/LiteMove/light-stack/internal/modules/system/model"
+	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
+	systemService "github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/internal/shared/config"
+	"github.com/LiteMove/light-stack/internal/shared/utils"
+	"github.com/LiteMove/light-stack/pkg/cache"
+	"github.com/LiteMove/light-stack/pkg/jwt"
+	"github.com/LiteMove/light-stack/pkg/logger"
+	"github.com/LiteMove/light-stack/pkg/mailer"
+	"github.com/LiteMove/light-stack/pkg/permission"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	passwordResetKeyPrefix      = "pwd_reset:"          // 重置令牌哈希 -> 用户ID
+	passwordResetUserKeyPrefix  = "pwd_reset_user:"     // 用户ID -> 当前有效的令牌哈希
+	passwordResetThrottlePrefix = "pwd_reset_throttle:" // 发送频率限制
+
+	passwordResetTTL      = 30 * time.Minute
+	passwordResetThrottle = time.Minute
+)
+
+// PasswordResetService 找回密码服务接口
+type PasswordResetService interface {
+	// 发送重置密码邮件（账号不存在时同样返回成功，避免账号枚举）
+	ForgotPassword(tenantID uint64, account string) error
+	// 使用重置令牌设置新密码
+	ResetPassword(token, newPassword string) error
+}
+
+// passwordResetService 找回密码服务实现
+type passwordResetService struct {
+	userRepo      repository2.UserRepository
+	tenantRepo    repository2.TenantRepository
+	defaultMailer mailer.Mailer
+	pwdPolicy     systemService.PasswordPolicyService
+}
+
+// NewPasswordResetService 创建找回密码服务
+func NewPasswordResetService(userRepo repository2.UserRepository, tenantRepo repository2.TenantRepository, defaultMailer mailer.Mailer, pwdPolicy systemService.PasswordPolicyService) PasswordResetService {
+	return &passwordResetService{
+		userRepo:      userRepo,
+		tenantRepo:    tenantRepo,
+		defaultMailer: defaultMailer,
+		pwdPolicy:     pwdPolicy,
+	}
+}
+
+// ForgotPassword 生成重置令牌并发送重置密码邮件
+func (s *passwordResetService) ForgotPassword(tenantID uint64, account string) error {
+	account = strings.TrimSpace(account)
+	if account == "" {
+		return errors.New("请输入用户名或邮箱")
+	}
+
+	var user *systemModel.User
+	var err error
+	if strings.Contains(account, "@") {
+		user, err = s.userRepo.GetByEmail(tenantID, account)
+	} else {
+		user, err = s.userRepo.GetByUsername(tenantID, account)
+	}
+	if err != nil || !user.IsActive() || user.Email == nil || *user.Email == "" {
+		logger.WithField("account", account).Warn("Password reset requested for unknown or unavailable account")
+		return nil
+	}
+
+	// 限制发送频率
+	userKey := strconv.FormatUint(user.ID, 10)
+	ok, err := cache.SetNX(passwordResetThrottlePrefix+userKey, 1, passwordResetThrottle)
+	if err != nil {
+		return fmt.Errorf("发送重置邮件失败: %w", err)
+	}
+	if !ok {
+		// 频率受限时同样返回成功，避免暴露账号是否存在
+		logger.WithField("userId", user.ID).Warn("Password reset requested too frequently")
+		return nil
+	}
+
+	token, err := utils.GenerateResetToken()
+	if err != nil {
+		return fmt.Errorf("生成重置令牌失败: %w", err)
+	}
+	tokenHash := hashResetToken(token)
+
+	// 作废之前未使用的令牌，每个用户只保留最新的一个
+	if previous, err := cache.Get(passwordResetUserKeyPrefix + userKey); err == nil {
+		_ = cache.Del(passwordResetKeyPrefix + previous)
+	}
+	if err := cache.Set(passwordResetKeyPrefix+tokenHash, user.ID, passwordResetTTL); err != nil {
+		return fmt.Errorf("保存重置令牌失败: %w", err)
+	}
+	if err := cache.Set(passwordResetUserKeyPrefix+userKey, tokenHash, passwordResetTTL); err != nil {
+		return fmt.Errorf("保存重置令牌失败: %w", err)
+	}
+
+	msg := s.buildResetMessage(user, token)
+	sender := s.tenantMailer(user.TenantID)
+
+	// 异步发送，避免响应时间暴露账号是否存在
+	go func() {
+		if err := sender.Send(msg); err != nil {
+			logger.WithField("userId", user.ID).Error("Failed to send password reset mail:", err)
+		}
+	}()
+
+	logger.WithField("userId", user.ID).Info("Password reset mail requested")
+	return nil
+}
+
+// ResetPassword 校验重置令牌并设置新密码，令牌仅能使用一次
+func (s *passwordResetService) ResetPassword(token, newPassword string) error {
+	// 先读取令牌校验密码策略，避免令牌因密码不合规被消耗
+	tokenHash := hashResetToken(strings.TrimSpace(token))
+	value, err := cache.Get(passwordResetKeyPrefix + tokenHash)
+	if errors.Is(err, redis.Nil) {
+		return errors.New("重置链接无效或已过期")
+	}
+	if err != nil {
+		return fmt.Errorf("校验重置令牌失败: %w", err)
+	}
+	userID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return errors.New("重置链接无效或已过期")
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || !user.IsActive() {
+		return errors.New("重置链接无效或已过期")
+	}
+	if err := s.pwdPolicy.ValidateNewPassword(user.TenantID, user, newPassword); err != nil {
+		return err
+	}
+
+	// 原子消费令牌，并发请求只有一个能成功
+	value, err = cache.GetDel(passwordResetKeyPrefix + tokenHash)
+	if errors.Is(err, redis.Nil) {
+		return errors.New("重置链接无效或已过期")
+	}
+	if err != nil {
+		return fmt.Errorf("校验重置令牌失败: %w", err)
+	}
+
+	if value != strconv.FormatUint(userID, 10) {
+		return errors.New("重置链接无效或已过期")
+	}
+	_ = cache.Del(passwordResetUserKeyPrefix + value)
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		logger.WithField("userId", userID).Error("Failed to hash new password:", err)
+		return errors.New("密码重置失败")
+	}
+	if err := s.userRepo.UpdatePassword(userID, hashedPassword); err != nil {
+		logger.WithField("userId", userID).Error("Failed to update password:", err)
+		return errors.New("密码重置失败")
+	}
+
+	// 重置成功后解除登录锁定，并让已登录的会话全部失效
+	if err := s.userRepo.UnlockUser(userID); err != nil {
+		logger.WithField("userId", userID).Warn("Failed to unlock user after password reset:", err)
+	}
+	if err := jwt.RevokeUserTokens(userID); err != nil {
+		logger.WithField("userId", userID).Warn("Failed to revoke tokens after password reset:", err)
+	}
+	permission.ClearUserPermissions(userID)
+
+	logger.WithField("userId", userID).Info("Password reset successfully")
+	return nil
+}
+
+// buildResetMessage 构建重置密码邮件
+func (s *passwordResetService) buildResetMessage(user *systemModel.User, token string) *mailer.Message {
+	appName := "light-stack"
+	frontendURL := ""
+	if cfg := config.Get(); cfg != nil {
+		appName = cfg.App.Name
+		frontendURL = strings.TrimRight(cfg.App.FrontendURL, "/")
+	}
+	link := fmt.Sprintf("%s/reset-password?token=%s", frontendURL, token)
+
+	body := fmt.Sprintf("您好 %s：\n\n我们收到了重置您账户密码的请求，请在%d分钟内点击以下链接设置新密码：\n\n%s\n\n如果这不是您本人的操作，请忽略此邮件，您的密码不会被修改。\n\n%s",
+		user.Username, int(passwordResetTTL.Minutes()), link, appName)
+
+	return &mailer.Message{
+		To:      []string{*user.Email},
+		Subject: fmt.Sprintf("[%s] 重置密码", appName),
+		Body:    body,
+	}
+}
+
+// tenantMailer 获取租户的邮件发送器，租户未配置SMTP时使用系统默认发送器
+func (s *passwordResetService) tenantMailer(tenantID uint64) mailer.Mailer {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		return s.defaultMailer
+	}
+	tenantConfig, err := tenant.GetConfig()
+	if err != nil || tenantConfig.Mail == nil || tenantConfig.Mail.SMTPHost == "" {
+		return s.defaultMailer
+	}
+
+	mailConfig := tenantConfig.Mail
+	return mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host:     mailConfig.SMTPHost,
+		Port:     mailConfig.SMTPPort,
+		Username: mailConfig.SMTPUsername,
+		Password: mailConfig.SMTPPassword,
+		UseTLS:   mailConfig.SMTPUseTLS,
+		From: mailer.Sender{
+			Address: mailConfig.FromAddress,
+			Name:    mailConfig.FromName,
+		},
+	})
+}
+
+// hashResetToken 计算重置令牌哈希，缓存中不保存明文
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -6,6 +6,8 @@ import (
 	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
 
 	systemModel "github.com/LiteMove/light-stack/internal/modules/system/model"
+	systemService "github.com/LiteMove/light-stack/internal/modules/system/service"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
 	"github.com/LiteMove/light-stack/internal/shared/utils"
 )
 
@@ -15,6 +17,7 @@ type ProfileService interface {
 	GetProfile(userID uint64) (*systemModel.UserProfile, error)
 	UpdateProfile(userID uint64, nickname, email, phone, avatar string) error
 	ChangePassword(userID uint64, oldPassword, newPassword string) error
+	GetLoginHistory(userID uint64, limit int) ([]sharedModel.LoginLogProfile, error)
 
 	// 租户配置操作（仅租户管理员）
 	IsTenantAdmin(userID, tenantID uint64) (bool, error)
@@ -24,17 +27,21 @@ type ProfileService interface {
 
 // profileService 个人中心服务实现
 type profileService struct {
-	userRepo   repository2.UserRepository
-	roleRepo   repository2.RoleRepository
-	tenantRepo repository2.TenantRepository
+	userRepo     repository2.UserRepository
+	roleRepo     repository2.RoleRepository
+	tenantRepo   repository2.TenantRepository
+	loginLogRepo repository2.LoginLogRepository
+	pwdPolicy    systemService.PasswordPolicyService
 }
 
 // NewProfileService 创建个人中心服务
-func NewProfileService(userRepo repository2.UserRepository, roleRepo repository2.RoleRepository, tenantRepo repository2.TenantRepository) ProfileService {
+func NewProfileService(userRepo repository2.UserRepository, roleRepo repository2.RoleRepository, tenantRepo repository2.TenantRepository, loginLogRepo repository2.LoginLogRepository, pwdPolicy systemService.PasswordPolicyService) ProfileService {
 	return &profileService{
-		userRepo:   userRepo,
-		roleRepo:   roleRepo,
-		tenantRepo: tenantRepo,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		tenantRepo:   tenantRepo,
+		loginLogRepo: loginLogRepo,
+		pwdPolicy:    pwdPolicy,
 	}
 }
 
@@ -52,14 +59,15 @@ func (s *profileService) GetProfile(userID uint64) (*systemModel.UserProfile, er
 	}
 
 	profile := &systemModel.UserProfile{
-		ID:       user.ID,
-		Username: user.Username,
-		Nickname: user.Nickname,
-		Email:    user.Email,
-		Phone:    user.Phone,
-		Status:   user.Status,
-		TenantID: user.TenantID,
-		Roles:    make([]systemModel.RoleProfile, 0, len(roles)),
+		ID:         user.ID,
+		Username:   user.Username,
+		Nickname:   user.Nickname,
+		Email:      user.Email,
+		Phone:      user.Phone,
+		Status:     user.Status,
+		TenantID:   user.TenantID,
+		MfaEnabled: user.MfaEnabled,
+		Roles:      make([]systemModel.RoleProfile, 0, len(roles)),
 	}
 
 	// 转换角色信息
@@ -124,6 +132,11 @@ func (s *profileService) ChangePassword(userID uint64, oldPassword, newPassword
 		return errors.New("原密码不正确")
 	}
 
+	// 校验密码策略
+	if err := s.pwdPolicy.ValidateNewPassword(user.TenantID, user, newPassword); err != nil {
+		return err
+	}
+
 	// 加密新密码
 	hashedPassword, err := utils.HashPassword(newPassword)
 	if err != nil {
@@ -131,14 +144,28 @@ func (s *profileService) ChangePassword(userID uint64, oldPassword, newPassword
 	}
 
 	// 更新密码
-	user.Password = hashedPassword
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.UpdatePassword(userID, hashedPassword); err != nil {
 		return fmt.Errorf("更新密码失败: %w", err)
 	}
 
 	return nil
 }
 
+// GetLoginHistory 获取最近的登录记录
+func (s *profileService) GetLoginHistory(userID uint64, limit int) ([]sharedModel.LoginLogProfile, error) {
+	logs, err := s.loginLogRepo.GetRecentByUser(userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("获取登录记录失败: %w", err)
+	}
+
+	history := make([]sharedModel.LoginLogProfile, 0, len(logs))
+	for _, log := range logs {
+		history = append(history, log.ToProfile())
+	}
+
+	return history, nil
+}
+
 // IsTenantAdmin 检查用户是否为租户管理员
 func (s *profileService) IsTenantAdmin(userID, tenantID uint64) (bool, error) {
 	// 获取用户信息
@@ -197,6 +224,9 @@ func (s *profileService) GetTenantConfig(tenantID uint64) (*systemModel.TenantCo
 	if config.FileStorage.MaxFileSize == 0 {
 		config.FileStorage.MaxFileSize = 50 * 1024 * 1024 // 50MB
 	}
+	if config.FileStorage.MaxChunkedFileSize == 0 {
+		config.FileStorage.MaxChunkedFileSize = 5 * 1024 * 1024 * 1024 // 5GB
+	}
 	if len(config.FileStorage.AllowedTypes) == 0 {
 		config.FileStorage.AllowedTypes = []string{".jpg", ".jpeg", ".png", ".gif", ".pdf", ".doc", ".docx", ".xls", ".xlsx", ".txt"}
 	}
@@ -220,6 +250,19 @@ func (s *profileService) UpdateTenantConfig(tenantID uint64, config *systemModel
 		return fmt.Errorf("配置验证失败: %w", err)
 	}
 
+	// 未提交安全策略和邮件配置时保持原有配置
+	if existing, err := tenant.GetConfig(); err == nil {
+		if config.Security == nil {
+			config.Security = existing.Security
+		}
+		if config.Mail == nil {
+			config.Mail = existing.Mail
+		}
+		if config.PasswordPolicy == nil {
+			config.PasswordPolicy = existing.PasswordPolicy
+		}
+	}
+
 	// 设置配置
 	if err := tenant.SetConfig(config); err != nil {
 		return fmt.Errorf("设置租户配置失败: %w", err)
@@ -247,6 +290,9 @@ func (s *profileService) validateTenantConfig(config *systemModel.TenantConfig)
 	if fileStorage.MaxFileSize <= 0 {
 		return errors.New("文件大小限制必须大于0")
 	}
+	if fileStorage.MaxChunkedFileSize < 0 {
+		return errors.New("分片上传文件大小限制不能小于0")
+	}
 
 	// 如果是OSS存储，验证OSS配置
 	if fileStorage.Type == "oss" {
@@ -264,5 +310,24 @@ func (s *profileService) validateTenantConfig(config *systemModel.TenantConfig)
 		}
 	}
 
+	// 验证安全策略配置
+	if config.Security != nil {
+		if err := config.Security.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// 验证密码策略配置
+	if config.PasswordPolicy != nil {
+		if err := config.PasswordPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// 验证邮件配置
+	if config.Mail != nil && config.Mail.SMTPHost != "" && config.Mail.FromAddress == "" && config.Mail.SMTPUsername == "" {
+		return errors.New("发件地址不能为空")
+	}
+
 	return nil
 }
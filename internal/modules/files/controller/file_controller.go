@@ -1,12 +1,15 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/LiteMove/light-stack/internal/modules/files/service"
 	"github.com/LiteMove/light-stack/internal/shared/middleware"
+	"github.com/LiteMove/light-stack/internal/shared/storage"
 	"github.com/LiteMove/light-stack/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -39,18 +42,15 @@ func (fc *FileController) UploadFile(c *gin.Context) {
 	// 获取使用类型（可选）
 	usageType := c.PostForm("usageType")
 
-	// 获取是否公开（如果未指定，则使用租户配置的默认值）
-	isPublicStr := c.PostForm("isPublic")
-	var isPublic bool
-	if isPublicStr != "" {
-		isPublic = isPublicStr == "true"
-	} else {
-		// 使用租户配置的默认公开设置，由FileService处理
-		isPublic = false // 这里先设为false，在FileService中会应用租户默认设置
+	// 获取是否公开（未指定时由FileService使用租户配置的默认值）
+	var isPublic *bool
+	if isPublicStr := c.PostForm("isPublic"); isPublicStr != "" {
+		value := isPublicStr == "true"
+		isPublic = &value
 	}
 
 	// 上传文件（现在由FileService根据租户配置处理所有验证）
-	uploadedFile, err := fc.fileService.UploadFile(file, userID, tenantID, usageType, isPublic)
+	uploadedFile, err := fc.fileService.UploadFile(c.Request.Context(), file, userID, tenantID, usageType, isPublic)
 	if err != nil {
 		response.Error(c, http.StatusBadRequest, err.Error())
 		return
@@ -68,7 +68,7 @@ func (fc *FileController) GetFile(c *gin.Context) {
 		return
 	}
 
-	file, err := fc.fileService.GetFileByID(id)
+	file, err := fc.fileService.GetFileByID(c.Request.Context(), id)
 	if err != nil {
 		response.Error(c, http.StatusNotFound, "文件不存在")
 		return
@@ -91,25 +91,55 @@ func (fc *FileController) GetPrivateFile(c *gin.Context) {
 	tenantID, _ := middleware.GetTenantIDFromContext(c)
 
 	// 获取文件信息并验证权限
-	file, fileContent, err := fc.fileService.GetPrivateFileContent(id, userID, tenantID)
+	file, reader, err := fc.fileService.OpenPrivateFile(c.Request.Context(), id, userID, tenantID)
 	if err != nil {
-		if err.Error() == "file not found" {
+		if err.Error() == "file not found" || errors.Is(err, storage.ErrObjectNotFound) {
 			response.Error(c, http.StatusNotFound, "文件不存在")
 		} else if err.Error() == "access denied" {
 			response.Error(c, http.StatusForbidden, "无权访问此文件")
+		} else if err.Error() == "file quarantined" {
+			response.Error(c, http.StatusForbidden, "文件未通过安全扫描，暂不可访问")
 		} else {
 			response.Error(c, http.StatusInternalServerError, err.Error())
 		}
 		return
 	}
+	defer reader.Close()
 
-	// 设置响应头
+	// 设置响应头，以存储的MD5作为ETag
 	c.Header("Content-Type", file.MimeType)
-	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", file.OriginalName))
-	c.Header("Content-Length", fmt.Sprintf("%d", len(fileContent)))
+	c.Header("Content-Disposition", contentDisposition("inline", file.OriginalName))
+	c.Header("ETag", `"`+file.MD5+`"`)
+	c.Header("Cache-Control", "private, no-cache")
 
-	// 返回文件内容
-	c.Data(http.StatusOK, file.MimeType, fileContent)
+	// 流式返回文件内容，由ServeContent处理Range、If-None-Match等条件请求
+	http.ServeContent(c.Writer, c.Request, file.OriginalName, file.CreatedAt, reader)
+}
+
+// contentDisposition 生成Content-Disposition，非ASCII文件名按RFC 6266使用filename*编码，
+// 同时提供替换后的ASCII文件名兼容旧客户端
+func contentDisposition(disposition, filename string) string {
+	var fallback, encoded strings.Builder
+	for i := 0; i < len(filename); i++ {
+		ch := filename[i]
+		switch {
+		case ch < 0x20 || ch >= 0x7f || ch == '"' || ch == '\\':
+			fallback.WriteByte('_')
+		default:
+			fallback.WriteByte(ch)
+		}
+
+		if ('A' <= ch && ch <= 'Z') || ('a' <= ch && ch <= 'z') || ('0' <= ch && ch <= '9') || strings.IndexByte("!#$&+-.^_`|~", ch) >= 0 {
+			encoded.WriteByte(ch)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", ch)
+		}
+	}
+
+	if fallback.String() == filename {
+		return fmt.Sprintf(`%s; filename="%s"`, disposition, filename)
+	}
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, fallback.String(), encoded.String())
 }
 
 // 下载、预览、复制链接功能已移除，统一使用文件的 access_url 字段
@@ -124,7 +154,7 @@ func (fc *FileController) DeleteFile(c *gin.Context) {
 		return
 	}
 
-	err = fc.fileService.DeleteFile(id)
+	err = fc.fileService.DeleteFile(c.Request.Context(), id)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "删除文件失败")
 		return
@@ -178,8 +208,8 @@ func (fc *FileController) GetUserFiles(c *gin.Context) {
 
 // GetAllFiles 获取所有文件列表（按租户）
 func (fc *FileController) GetAllFiles(c *gin.Context) {
-	// 获取租户信息
-	tenantID, _ := middleware.GetTenantIDFromContext(c)
+	// 获取当前用户的数据权限
+	filter := middleware.GetDataFilterFromContext(c)
 
 	// 获取分页参数
 	pageStr := c.DefaultQuery("page", "1")
@@ -211,7 +241,7 @@ func (fc *FileController) GetAllFiles(c *gin.Context) {
 	}
 
 	// 获取文件列表
-	files, total, err := fc.fileService.GetAllFiles(tenantID, page, pageSize, filters)
+	files, total, err := fc.fileService.GetAllFiles(filter, page, pageSize, filters)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "获取文件列表失败")
 		return
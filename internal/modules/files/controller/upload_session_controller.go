@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LiteMove/light-stack/internal/modules/files/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
+	"github.com/LiteMove/light-stack/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// InitUpload 初始化分片上传，文件已存在时直接返回文件信息
+func (fc *FileController) InitUpload(c *gin.Context) {
+	var req service.InitUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	tenantID, _ := middleware.GetTenantIDFromContext(c)
+
+	result, err := fc.fileService.InitUploadSession(c.Request.Context(), &req, userID, tenantID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetUpload 获取上传会话及已接收的分片
+func (fc *FileController) GetUpload(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的上传会话ID")
+		return
+	}
+
+	result, err := fc.fileService.GetUploadSession(c.Request.Context(), id, middleware.GetUserIDFromContext(c))
+	if err != nil {
+		uploadSessionError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// UploadChunk 上传分片，请求体为分片原始内容
+func (fc *FileController) UploadChunk(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的上传会话ID")
+		return
+	}
+	partNumber, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		response.BadRequest(c, "无效的分片序号")
+		return
+	}
+	if c.Request.ContentLength <= 0 {
+		response.Error(c, http.StatusLengthRequired, "请求需要声明Content-Length")
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, c.Request.ContentLength)
+	err = fc.fileService.UploadChunk(c.Request.Context(), id, middleware.GetUserIDFromContext(c), partNumber, body, c.Request.ContentLength)
+	if err != nil {
+		uploadSessionError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"partNumber": partNumber})
+}
+
+// CompleteUpload 完成分片上传，合并分片并校验MD5
+func (fc *FileController) CompleteUpload(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的上传会话ID")
+		return
+	}
+
+	file, err := fc.fileService.CompleteUploadSession(c.Request.Context(), id, middleware.GetUserIDFromContext(c))
+	if err != nil {
+		uploadSessionError(c, err)
+		return
+	}
+
+	response.Success(c, file.ToProfile())
+}
+
+// AbortUpload 取消分片上传
+func (fc *FileController) AbortUpload(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的上传会话ID")
+		return
+	}
+
+	if err := fc.fileService.AbortUploadSession(c.Request.Context(), id, middleware.GetUserIDFromContext(c)); err != nil {
+		uploadSessionError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "已取消上传"})
+}
+
+// PresignDirectUpload 获取浏览器直传对象存储的上传策略，本地存储返回服务端上传接口
+func (fc *FileController) PresignDirectUpload(c *gin.Context) {
+	var req service.DirectUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	tenantID, _ := middleware.GetTenantIDFromContext(c)
+
+	result, err := fc.fileService.PresignDirectUpload(c.Request.Context(), &req, userID, tenantID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ConfirmDirectUpload 确认浏览器直传完成并创建文件记录
+func (fc *FileController) ConfirmDirectUpload(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	tenantID, _ := middleware.GetTenantIDFromContext(c)
+
+	file, err := fc.fileService.ConfirmDirectUpload(c.Request.Context(), req.Token, userID, tenantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDirectUploadInvalid),
+			errors.Is(err, service.ErrDirectUploadNotFound),
+			errors.Is(err, service.ErrDirectUploadMismatch),
+			errors.Is(err, service.ErrFileContentMismatch):
+			response.BadRequest(c, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, file.ToProfile())
+}
+
+// uploadSessionError 按错误类型返回分片上传错误
+func uploadSessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUploadSessionNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrUploadSessionClosed):
+		response.Error(c, http.StatusGone, err.Error())
+	case errors.Is(err, service.ErrUploadChunkInvalid),
+		errors.Is(err, service.ErrUploadIncomplete),
+		errors.Is(err, service.ErrUploadMD5Mismatch),
+		errors.Is(err, service.ErrFileContentMismatch):
+		response.BadRequest(c, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -7,6 +7,15 @@ import (
 	"github.com/LiteMove/light-stack/internal/shared/model"
 )
 
+// 文件安全扫描状态
+const (
+	FileScanStatusClean    = 1 // 已通过扫描（未启用扫描时直接通过）
+	FileScanStatusPending  = 2 // 隔离中，等待扫描
+	FileScanStatusInfected = 3 // 检出威胁，文件已删除
+	FileScanStatusFailed   = 4 // 扫描失败，等待重试
+	FileScanStatusOversize = 5 // 超出扫描大小限制，保持隔离，需管理员处理
+)
+
 // File 文件模型
 type File struct {
 	model.TenantBaseModel
@@ -22,6 +31,8 @@ type File struct {
 	StorageType  string `json:"storageType" gorm:"not null;default:'local';size:20" validate:"required,oneof=local oss"`
 	IsPublic     bool   `json:"isPublic" gorm:"not null;default:false"`
 	AccessURL    string `json:"accessUrl" gorm:"size:1000"`
+	ScanStatus   int    `json:"scanStatus" gorm:"not null;default:1;index:idx_scan_status"`
+	ScanResult   string `json:"scanResult" gorm:"size:255"` // 检出的威胁名称或扫描失败原因
 
 	// 关联关系
 	UploadUser *systemModel.User `json:"upload_user,omitempty" gorm:"foreignKey:UploadUserID"`
@@ -48,6 +59,8 @@ type FileProfile struct {
 	StorageType  string    `json:"storageType"`
 	IsPublic     bool      `json:"isPublic"`
 	AccessURL    string    `json:"accessUrl"`
+	ScanStatus   int       `json:"scanStatus"`
+	ScanResult   string    `json:"scanResult"`
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 }
@@ -69,11 +82,18 @@ func (f *File) ToProfile() FileProfile {
 		StorageType:  f.StorageType,
 		IsPublic:     f.IsPublic,
 		AccessURL:    f.AccessURL,
+		ScanStatus:   f.ScanStatus,
+		ScanResult:   f.ScanResult,
 		CreatedAt:    f.CreatedAt,
 		UpdatedAt:    f.UpdatedAt,
 	}
 }
 
+// IsQuarantined 文件是否尚未通过安全扫描
+func (f *File) IsQuarantined() bool {
+	return f.ScanStatus != FileScanStatusClean
+}
+
 // GetSizeInKB 获取文件大小（KB）
 func (f *File) GetSizeInKB() float64 {
 	return float64(f.FileSize) / 1024
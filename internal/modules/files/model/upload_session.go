@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/model"
+)
+
+// 分片上传会话状态
+const (
+	UploadSessionStatusUploading  = 1 // 上传中
+	UploadSessionStatusCompleted  = 2 // 已完成
+	UploadSessionStatusAborted    = 3 // 已取消
+	UploadSessionStatusCompleting = 4 // 合并中
+)
+
+// UploadSession 分片上传会话，断线后可查询已接收的分片继续上传
+type UploadSession struct {
+	model.TenantBaseModel
+	UploadUserID uint64    `json:"uploadUserId" gorm:"not null;index:idx_upload_user_id"`
+	OriginalName string    `json:"originalName" gorm:"not null;size:255"`
+	FileName     string    `json:"fileName" gorm:"not null;size:255"`
+	FilePath     string    `json:"filePath" gorm:"not null;size:500"` // 合并后的存储路径
+	FileSize     int64     `json:"fileSize" gorm:"not null"`
+	FileType     string    `json:"fileType" gorm:"not null;size:100"`
+	MimeType     string    `json:"mimeType" gorm:"not null;size:100"`
+	MD5          string    `json:"md5" gorm:"not null;size:32"` // 客户端声明的文件MD5，完成时校验
+	ChunkSize    int64     `json:"chunkSize" gorm:"not null"`
+	TotalChunks  int       `json:"totalChunks" gorm:"not null"`
+	UsageType    string    `json:"usageType" gorm:"size:50"`
+	StorageType  string    `json:"storageType" gorm:"not null;size:20"`
+	IsPublic     bool      `json:"isPublic" gorm:"not null;default:false"`
+	UploadID     string    `json:"-" gorm:"not null;size:255"` // 存储端分片上传ID
+	Status       int       `json:"status" gorm:"not null;default:1;index:idx_status"`
+	FileID       uint64    `json:"fileId" gorm:"not null;default:0"` // 完成后生成的文件ID
+	ExpiresAt    time.Time `json:"expiresAt" gorm:"not null;index:idx_expires_at"`
+}
+
+// TableName 指定表名
+func (UploadSession) TableName() string {
+	return "file_upload_sessions"
+}
+
+// IsExpired 会话是否已过期
+func (s *UploadSession) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// ChunkLength 指定分片的字节数，最后一个分片可能小于分片大小
+func (s *UploadSession) ChunkLength(partNumber int) int64 {
+	if partNumber == s.TotalChunks {
+		return s.FileSize - int64(s.TotalChunks-1)*s.ChunkSize
+	}
+	return s.ChunkSize
+}
+
+// UploadSessionPart 已接收的分片
+type UploadSessionPart struct {
+	ID         uint64    `json:"-" gorm:"primarykey"`
+	SessionID  uint64    `json:"-" gorm:"not null;uniqueIndex:uk_session_part"`
+	PartNumber int       `json:"partNumber" gorm:"not null;uniqueIndex:uk_session_part"`
+	Size       int64     `json:"size" gorm:"not null"`
+	ETag       string    `json:"-" gorm:"column:etag;not null;size:255"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (UploadSessionPart) TableName() string {
+	return "file_upload_session_parts"
+}
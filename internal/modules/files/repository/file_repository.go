@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/LiteMove/light-stack/internal/modules/files/model"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/pkg/database"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +21,11 @@ func NewFileRepository(db *gorm.DB) *FileRepository {
 	return &FileRepository{db: db}
 }
 
+// WithContext 绑定请求上下文，按上下文中的租户自动隔离数据
+func (r *FileRepository) WithContext(ctx context.Context) *FileRepository {
+	return &FileRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create 创建文件记录
 func (r *FileRepository) Create(file *model.File) error {
 	return r.db.Create(file).Error
@@ -32,10 +41,10 @@ func (r *FileRepository) GetByID(id uint64) (*model.File, error) {
 	return &file, nil
 }
 
-// GetByMD5AndTenant 根据MD5和租户ID查找文件
+// GetByMD5AndTenant 根据MD5和租户ID查找扫描通过的文件，待扫描、扫描失败、超出扫描限制及检出威胁的文件不参与秒传
 func (r *FileRepository) GetByMD5AndTenant(md5 string, tenantID uint64) (*model.File, error) {
 	var file model.File
-	err := r.db.Where("md5 = ? AND tenant_id = ?", md5, tenantID).First(&file).Error
+	err := r.db.Where("md5 = ? AND tenant_id = ? AND scan_status = ?", md5, tenantID, model.FileScanStatusClean).First(&file).Error
 	if err != nil {
 		return nil, err
 	}
@@ -67,12 +76,13 @@ func (r *FileRepository) GetFilesByUser(userID uint64, tenantID uint64, offset,
 	return files, total, nil
 }
 
-// GetAllFiles 获取所有文件列表（按租户）
-func (r *FileRepository) GetAllFiles(tenantID uint64, offset, limit int, filters map[string]interface{}) ([]*model.File, int64, error) {
+// GetAllFiles 获取所有文件列表（按租户和数据权限）
+func (r *FileRepository) GetAllFiles(filter sharedModel.DataFilter, offset, limit int, filters map[string]interface{}) ([]*model.File, int64, error) {
 	var files []*model.File
 	var total int64
 
-	db := r.db.Where("tenant_id = ?", tenantID)
+	db := r.db.Where("tenant_id = ?", filter.TenantID).
+		Scopes(database.DataScope(filter, database.DataScopeColumns{Owner: []string{"upload_user_id"}}))
 
 	// 应用过滤条件
 	if filename, ok := filters["filename"]; ok && filename != "" {
@@ -128,3 +138,11 @@ func (r *FileRepository) GetCountByTenantID(tenantID uint64) (int64, error) {
 	err := r.db.Model(&model.File{}).Where("tenant_id = ?", tenantID).Count(&count).Error
 	return count, err
 }
+
+// GetPendingScan 获取等待扫描或扫描失败且长时间未处理的文件（跨租户，后台重试使用）
+func (r *FileRepository) GetPendingScan(before time.Time, limit int) ([]*model.File, error) {
+	var files []*model.File
+	err := r.db.Where("scan_status IN ? AND updated_at < ?", []int{model.FileScanStatusPending, model.FileScanStatusFailed}, before).
+		Limit(limit).Find(&files).Error
+	return files, err
+}
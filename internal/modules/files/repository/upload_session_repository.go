@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/modules/files/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UploadSessionRepository 分片上传会话数据访问层
+type UploadSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewUploadSessionRepository 创建分片上传会话数据访问层实例
+func NewUploadSessionRepository(db *gorm.DB) *UploadSessionRepository {
+	return &UploadSessionRepository{db: db}
+}
+
+// WithContext 绑定请求上下文，按上下文中的租户自动隔离数据
+func (r *UploadSessionRepository) WithContext(ctx context.Context) *UploadSessionRepository {
+	return &UploadSessionRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create 创建上传会话
+func (r *UploadSessionRepository) Create(session *model.UploadSession) error {
+	return r.db.Create(session).Error
+}
+
+// GetByID 根据ID获取上传会话
+func (r *UploadSessionRepository) GetByID(id uint64) (*model.UploadSession, error) {
+	var session model.UploadSession
+	err := r.db.Where("id = ?", id).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UpdateStatus 更新会话状态，仅更新处于指定状态的会话，返回是否更新成功，用于防止并发完成或取消
+func (r *UploadSessionRepository) UpdateStatus(id uint64, from, to int, fileID uint64) (bool, error) {
+	result := r.db.Model(&model.UploadSession{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{"status": to, "file_id": fileID})
+	return result.RowsAffected > 0, result.Error
+}
+
+// SavePart 保存已接收的分片，重复上传同一分片时覆盖
+func (r *UploadSessionRepository) SavePart(part *model.UploadSessionPart) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "session_id"}, {Name: "part_number"}},
+		DoUpdates: clause.AssignmentColumns([]string{"size", "etag", "created_at"}),
+	}).Create(part).Error
+}
+
+// GetParts 获取会话已接收的分片，按分片号排序
+func (r *UploadSessionRepository) GetParts(sessionID uint64) ([]*model.UploadSessionPart, error) {
+	var parts []*model.UploadSessionPart
+	err := r.db.Where("session_id = ?", sessionID).Order("part_number ASC").Find(&parts).Error
+	return parts, err
+}
+
+// DeleteParts 删除会话的分片记录
+func (r *UploadSessionRepository) DeleteParts(sessionID uint64) error {
+	return r.db.Where("session_id = ?", sessionID).Delete(&model.UploadSessionPart{}).Error
+}
+
+// GetExpired 获取已过期且未完成的会话（跨租户，后台清理使用）
+func (r *UploadSessionRepository) GetExpired(before time.Time, limit int) ([]*model.UploadSession, error) {
+	var sessions []*model.UploadSession
+	err := r.db.Where("status = ? AND expires_at < ?", model.UploadSessionStatusUploading, before).
+		Limit(limit).Find(&sessions).Error
+	return sessions, err
+}
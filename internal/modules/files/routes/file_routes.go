@@ -10,15 +10,28 @@ func RegisterFileRoutes(api *gin.RouterGroup) {
 	v1 := api.Group("/v1")
 
 	// 文件管理路由（需要认证）
-	files := v1.Group("/files")
-	files.Use(middleware.Auth())
+	group := v1.Group("/files")
+	group.Use(middleware.Auth())
+	group.Use(middleware.OperationLog(globals.OperationLogSvc())) // 记录写操作审计日志
+	files := middleware.Guard(group)
 	{
-		files.GET("", globals.FileCtrl().GetAllFiles)                // 获取所有文件列表
-		files.GET("/:id", globals.FileCtrl().GetFile)                // 获取文件信息
-		files.GET("/:id/private", globals.FileCtrl().GetPrivateFile) // 获取私有文件内容
-		files.DELETE("/:id", globals.FileCtrl().DeleteFile)          // 删除文件
-		files.POST("/upload", globals.FileCtrl().UploadFile)         // 上传文件
-		files.GET("/user", globals.FileCtrl().GetUserFiles)          // 获取用户文件列表
+		files.GET("", globals.FileCtrl().GetAllFiles, "system:file:list")                                         // 获取所有文件列表
+		files.GET("/:id", globals.FileCtrl().GetFile, "system:file:view")                                         // 获取文件信息
+		files.GET("/:id/private", globals.FileCtrl().GetPrivateFile, "system:file:view")                          // 获取私有文件内容
+		files.DELETE("/:id", middleware.RejectImpersonation(globals.FileCtrl().DeleteFile), "system:file:delete") // 删除文件
+		files.POST("/upload", globals.FileCtrl().UploadFile, "system:file:upload")                                // 上传文件
+		files.GET("/user", globals.FileCtrl().GetUserFiles, "system:file:view")                                   // 获取用户文件列表
+
+		// 分片上传（断点续传）
+		files.POST("/uploads", globals.FileCtrl().InitUpload, "system:file:upload")                    // 初始化分片上传
+		files.GET("/uploads/:id", globals.FileCtrl().GetUpload, "system:file:upload")                  // 查询已接收的分片
+		files.PUT("/uploads/:id/chunks/:number", globals.FileCtrl().UploadChunk, "system:file:upload") // 上传分片
+		files.POST("/uploads/:id/complete", globals.FileCtrl().CompleteUpload, "system:file:upload")   // 完成分片上传
+		files.DELETE("/uploads/:id", globals.FileCtrl().AbortUpload, "system:file:upload")             // 取消分片上传
+
+		// 浏览器直传对象存储
+		files.POST("/direct-uploads", globals.FileCtrl().PresignDirectUpload, "system:file:upload")         // 获取直传策略
+		files.POST("/direct-uploads/confirm", globals.FileCtrl().ConfirmDirectUpload, "system:file:upload") // 确认直传完成
 	}
 
 }
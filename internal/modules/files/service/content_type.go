@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"mime"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// ErrFileContentMismatch 文件内容与扩展名不符
+var ErrFileContentMismatch = errors.New("文件内容与扩展名不符")
+
+// sniffLen 内容类型检测读取的文件头字节数
+const sniffLen = 3072
+
+// activeContentExts 浏览器可能作为页面或脚本执行的文本类型，内容仅为纯文本时也不放行
+var activeContentExts = map[string]bool{
+	"html": true, "htm": true, "xhtml": true, "shtml": true, "svg": true, "xml": true, "js": true, "mjs": true,
+}
+
+// containerExts 基于容器格式的文档，文件头不足以识别具体格式时按容器类型放行
+var containerExts = map[string]string{
+	"docx": "application/zip", "xlsx": "application/zip", "pptx": "application/zip",
+	"odt": "application/zip", "ods": "application/zip", "odp": "application/zip",
+	"doc": "application/x-ole-storage", "xls": "application/x-ole-storage", "ppt": "application/x-ole-storage",
+}
+
+// headBuffer 保存写入内容的前若干字节，用于在计算MD5的同时检测内容类型
+type headBuffer struct {
+	buf []byte
+}
+
+// Write 仅保留前 sniffLen 字节
+func (b *headBuffer) Write(p []byte) (int, error) {
+	if n := sniffLen - len(b.buf); n > 0 {
+		if len(p) < n {
+			n = len(p)
+		}
+		b.buf = append(b.buf, p[:n]...)
+	}
+	return len(p), nil
+}
+
+// detectMimeType 根据文件头检测内容类型并校验与扩展名是否一致，返回检测到的MIME类型（不含参数）
+func (s *FileService) detectMimeType(head []byte, filename string) (string, error) {
+	detected := mimetype.Detect(head)
+	ext := strings.ToLower(s.getFileExtension(filename))
+	expected, _, _ := mime.ParseMediaType(mime.TypeByExtension("." + ext))
+	container := containerExts[ext]
+
+	// 检测为可执行的文本内容（如HTML）时必须与扩展名完全一致，不按父类型（纯文本）放行
+	active := activeContentExts[strings.TrimPrefix(detected.Extension(), ".")]
+	for m := detected; m != nil; m = m.Parent() {
+		if (ext != "" && m.Extension() == "."+ext) || (expected != "" && m.Is(expected)) || (container != "" && m.Is(container)) {
+			return baseMimeType(detected.String()), nil
+		}
+		if active {
+			break
+		}
+	}
+
+	switch {
+	case detected.Is("text/plain") && !activeContentExts[ext] && (expected == "" || strings.HasPrefix(expected, "text/")):
+		// 纯文本无法细分格式（如 .md、.csv、.log），扩展名为文本类型时放行
+		return "text/plain", nil
+	case detected.Is("application/octet-stream") && (expected == "" || expected == "application/octet-stream"):
+		// 无法识别的二进制内容，且扩展名没有对应的已知类型
+		return s.getMimeType("", filename), nil
+	}
+	return "", ErrFileContentMismatch
+}
+
+// baseMimeType 去掉MIME类型中的参数，如 text/plain; charset=utf-8 -> text/plain
+func baseMimeType(mimeType string) string {
+	base, _, _ := strings.Cut(mimeType, ";")
+	return strings.TrimSpace(base)
+}
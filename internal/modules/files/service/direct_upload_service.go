@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/modules/files/model"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/internal/shared/storage"
+	"github.com/LiteMove/light-stack/pkg/cache"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 浏览器直传错误
+var (
+	ErrDirectUploadInvalid  = errors.New("上传凭证无效或已过期")
+	ErrDirectUploadNotFound = errors.New("文件尚未上传到存储，请上传完成后再确认")
+	ErrDirectUploadMismatch = errors.New("上传的文件与声明的大小或MD5不一致，请重新上传")
+)
+
+const (
+	directUploadKeyPrefix = "file_direct_upload:" // 直传凭证 -> 待确认的上传信息
+	directUploadExpire    = 15 * time.Minute      // 上传策略有效期
+	directUploadConfirm   = time.Hour             // 策略过期后等待确认的时长，覆盖大文件上传耗时
+	localUploadURL        = "/api/v1/files/upload"
+)
+
+// DirectUploadRequest 浏览器直传请求
+type DirectUploadRequest struct {
+	FileName  string `json:"fileName" binding:"required,max=255"`
+	FileSize  int64  `json:"fileSize" binding:"required,min=1"`
+	MimeType  string `json:"mimeType" binding:"max=100"`
+	MD5       string `json:"md5" binding:"omitempty,len=32,hexadecimal"` // 可选，提供时用于秒传和上传后校验
+	UsageType string `json:"usageType" binding:"max=50"`
+	IsPublic  *bool  `json:"isPublic"` // 未指定时使用租户配置的默认值
+}
+
+// DirectUploadResult 浏览器直传信息
+// Direct 为 false 时存储不支持直传（如本地存储），按 Upload 提交到服务端上传接口即可，无需确认
+type DirectUploadResult struct {
+	Direct  bool                     `json:"direct"`
+	Instant bool                     `json:"instant"` // 文件已存在，无需上传
+	Token   string                   `json:"token,omitempty"`
+	Upload  *storage.PresignedUpload `json:"upload,omitempty"`
+	File    *model.FileProfile       `json:"file,omitempty"`
+}
+
+// pendingDirectUpload 待确认的直传文件
+type pendingDirectUpload struct {
+	TenantID     uint64 `json:"tenantId"`
+	UserID       uint64 `json:"userId"`
+	OriginalName string `json:"originalName"`
+	FileName     string `json:"fileName"`
+	FilePath     string `json:"filePath"`
+	FileSize     int64  `json:"fileSize"`
+	FileType     string `json:"fileType"`
+	MimeType     string `json:"mimeType"`
+	MD5          string `json:"md5"`
+	UsageType    string `json:"usageType"`
+	StorageType  string `json:"storageType"`
+	IsPublic     bool   `json:"isPublic"`
+}
+
+// PresignDirectUpload 生成浏览器直传对象存储的上传策略，限制上传路径、大小和内容类型
+func (s *FileService) PresignDirectUpload(ctx context.Context, req *DirectUploadRequest, userID, tenantID uint64) (*DirectUploadResult, error) {
+	storageManager, storageConfig, err := s.newStorageManager(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	isPublic := s.resolveIsPublic(req.UsageType, req.IsPublic, storageConfig)
+
+	// 本地存储沿用服务端上传接口
+	if storageConfig.Type == "local" {
+		return &DirectUploadResult{
+			Upload: &storage.PresignedUpload{
+				Method: http.MethodPost,
+				URL:    localUploadURL,
+				Fields: map[string]string{
+					"usageType": req.UsageType,
+					"isPublic":  strconv.FormatBool(isPublic),
+				},
+				FileField: "file",
+			},
+		}, nil
+	}
+
+	// 验证文件大小限制
+	if req.FileSize > storageConfig.MaxFileSize {
+		return nil, fmt.Errorf("file size exceeds limit: %d > %d", req.FileSize, storageConfig.MaxFileSize)
+	}
+
+	// 验证文件类型
+	fileExt := s.getFileExtension(req.FileName)
+	if !s.isAllowedFileType(fileExt, storageConfig.AllowedTypes) {
+		return nil, fmt.Errorf("file type not allowed: %s", fileExt)
+	}
+
+	// 文件已存在时秒传
+	md5Hash := strings.ToLower(req.MD5)
+	if existingFile := s.findInstantFile(ctx, md5Hash, req.FileSize, tenantID); existingFile != nil {
+		profile := existingFile.ToProfile()
+		return &DirectUploadResult{Direct: true, Instant: true, File: &profile}, nil
+	}
+
+	// 内容类型按扩展名确定，确认时再校验文件内容；启用病毒扫描时上传到隔离区
+	fileName := s.generateFileName(req.FileName)
+	storagePath, quarantined := s.uploadPath(tenantID, fileName, isPublic)
+	mimeType := s.getMimeType("", req.FileName)
+
+	upload, err := storageManager.PresignUpload(storagePath, isPublic && !quarantined, mimeType, req.FileSize, directUploadExpire)
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign upload: %w", err)
+	}
+
+	pending, err := json.Marshal(&pendingDirectUpload{
+		TenantID:     tenantID,
+		UserID:       userID,
+		OriginalName: req.FileName,
+		FileName:     fileName,
+		FilePath:     storagePath,
+		FileSize:     req.FileSize,
+		FileType:     fileExt,
+		MimeType:     mimeType,
+		MD5:          md5Hash,
+		UsageType:    req.UsageType,
+		StorageType:  storageConfig.Type,
+		IsPublic:     isPublic,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode direct upload: %w", err)
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate upload token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	if err := cache.Set(directUploadKeyPrefix+token, pending, directUploadExpire+directUploadConfirm); err != nil {
+		return nil, fmt.Errorf("failed to save direct upload: %w", err)
+	}
+
+	return &DirectUploadResult{Direct: true, Token: token, Upload: upload}, nil
+}
+
+// ConfirmDirectUpload 确认浏览器直传完成，校验存储中的文件后创建文件记录，凭证仅能使用一次
+func (s *FileService) ConfirmDirectUpload(ctx context.Context, token string, userID, tenantID uint64) (*model.File, error) {
+	key := directUploadKeyPrefix + strings.TrimSpace(token)
+	value, err := cache.Get(key)
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrDirectUploadInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get direct upload: %w", err)
+	}
+
+	var pending pendingDirectUpload
+	if err := json.Unmarshal([]byte(value), &pending); err != nil {
+		return nil, ErrDirectUploadInvalid
+	}
+	if pending.TenantID != tenantID || pending.UserID != userID {
+		return nil, ErrDirectUploadInvalid
+	}
+
+	storageManager, storageConfig, err := s.newStorageManager(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 文件未上传时保留凭证，允许上传完成后再次确认
+	info, err := storageManager.Stat(pending.FilePath)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, ErrDirectUploadNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat uploaded file: %w", err)
+	}
+
+	// 原子消费凭证，并发确认只有一个能成功
+	if _, err := cache.GetDel(key); errors.Is(err, redis.Nil) {
+		return nil, ErrDirectUploadInvalid
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to consume direct upload: %w", err)
+	}
+
+	if info.Size != pending.FileSize || info.Size > storageConfig.MaxFileSize {
+		storageManager.Delete(pending.FilePath)
+		return nil, ErrDirectUploadMismatch
+	}
+
+	md5Hash, head, err := s.directUploadMD5(storageManager, pending.FilePath, info)
+	if err != nil {
+		storageManager.Delete(pending.FilePath)
+		return nil, fmt.Errorf("failed to calculate MD5: %w", err)
+	}
+	if pending.MD5 != "" && pending.MD5 != md5Hash {
+		storageManager.Delete(pending.FilePath)
+		return nil, ErrDirectUploadMismatch
+	}
+
+	mimeType, err := s.detectMimeType(head, pending.OriginalName)
+	if err != nil {
+		storageManager.Delete(pending.FilePath)
+		return nil, err
+	}
+
+	// 上传期间已有相同文件时保留原文件
+	if existingFile := s.findInstantFile(ctx, md5Hash, info.Size, tenantID); existingFile != nil {
+		storageManager.Delete(pending.FilePath)
+		return existingFile, nil
+	}
+
+	fileModel := &model.File{
+		TenantBaseModel: sharedModel.TenantBaseModel{
+			TenantID: tenantID,
+		},
+		OriginalName: pending.OriginalName,
+		FileName:     pending.FileName,
+		FilePath:     pending.FilePath,
+		FileSize:     info.Size,
+		FileType:     pending.FileType,
+		MimeType:     mimeType,
+		MD5:          md5Hash,
+		UploadUserID: userID,
+		UsageType:    pending.UsageType,
+		StorageType:  pending.StorageType,
+		IsPublic:     pending.IsPublic,
+		ScanStatus:   model.FileScanStatusClean,
+	}
+	// 隔离区中的文件扫描通过后才生成访问地址
+	if storage.IsQuarantinePath(pending.FilePath) {
+		fileModel.ScanStatus = model.FileScanStatusPending
+	} else {
+		fileModel.AccessURL = storageManager.GetURL(pending.FilePath, pending.IsPublic)
+	}
+	if err := s.fileRepo.WithContext(ctx).Create(fileModel); err != nil {
+		storageManager.Delete(pending.FilePath)
+		return nil, fmt.Errorf("failed to save file record: %w", err)
+	}
+
+	s.submitScan(fileModel)
+	return fileModel, nil
+}
+
+// directUploadMD5 获取直传文件的MD5和文件头，普通上传的对象ETag即为内容MD5，否则读取文件计算
+func (s *FileService) directUploadMD5(storageManager *storage.Manager, path string, info *storage.ObjectInfo) (string, []byte, error) {
+	etag := strings.ToLower(info.ETag)
+	if len(etag) == 32 {
+		if _, err := hex.DecodeString(etag); err == nil {
+			head, err := s.storedFileHead(storageManager, path)
+			return etag, head, err
+		}
+	}
+	return s.storedFileMD5(storageManager, path)
+}
@@ -1,12 +1,12 @@
 package service
 
 import (
+	"context"
 	"crypto/md5"
 	"fmt"
 	"github.com/LiteMove/light-stack/internal/modules/files/repository"
 	"io"
 	"mime/multipart"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -15,6 +15,7 @@ import (
 	systemModel "github.com/LiteMove/light-stack/internal/modules/system/model"
 	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
 	"github.com/LiteMove/light-stack/internal/shared/storage"
+	"github.com/LiteMove/light-stack/pkg/scanner"
 )
 
 // TenantService 租户服务接口（跨模块依赖）
@@ -25,19 +26,23 @@ type TenantService interface {
 // FileService 文件服务
 type FileService struct {
 	fileRepo      *repository.FileRepository
+	sessionRepo   *repository.UploadSessionRepository
 	tenantService TenantService
+	scanner       scanner.Scanner
 }
 
 // NewFileService 创建文件服务实例
-func NewFileService(fileRepo *repository.FileRepository, tenantService TenantService) *FileService {
+func NewFileService(fileRepo *repository.FileRepository, sessionRepo *repository.UploadSessionRepository, tenantService TenantService, fileScanner scanner.Scanner) *FileService {
 	return &FileService{
 		fileRepo:      fileRepo,
+		sessionRepo:   sessionRepo,
 		tenantService: tenantService,
+		scanner:       fileScanner,
 	}
 }
 
 // UploadFile 上传文件（支持新的存储架构）
-func (s *FileService) UploadFile(file *multipart.FileHeader, userID, tenantID uint64, usageType string, isPublic bool) (*model.File, error) {
+func (s *FileService) UploadFile(ctx context.Context, file *multipart.FileHeader, userID, tenantID uint64, usageType string, publicOption *bool) (*model.File, error) {
 	// 获取租户的存储配置
 	tenant, err := s.tenantService.GetTenant(tenantID)
 	if err != nil {
@@ -49,13 +54,7 @@ func (s *FileService) UploadFile(file *multipart.FileHeader, userID, tenantID ui
 		return nil, fmt.Errorf("failed to get storage config: %w", err)
 	}
 
-	// 如果没有明确指定isPublic，则使用租户配置的默认值
-	// 特殊处理：某些类型的文件应该强制为公开访问
-	if usageType == "system-logo" || usageType == "avatar" {
-		isPublic = true // 系统logo和头像强制公开访问
-	} else if !isPublic {
-		isPublic = storageConfig.DefaultPublic
-	}
+	isPublic := s.resolveIsPublic(usageType, publicOption, storageConfig)
 
 	// 验证文件大小限制
 	if file.Size > storageConfig.MaxFileSize {
@@ -75,16 +74,21 @@ func (s *FileService) UploadFile(file *multipart.FileHeader, userID, tenantID ui
 	}
 	defer src.Close()
 
-	// 计算文件MD5
-	md5Hash, err := s.calculateMD5(src)
+	// 计算文件MD5，同时保留文件头用于内容类型检测
+	head := &headBuffer{}
+	md5Hash, err := s.calculateMD5(io.TeeReader(src, head))
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate MD5: %w", err)
 	}
 
-	// 检查文件是否已存在（基于MD5和租户）
-	existingFile, err := s.fileRepo.GetByMD5AndTenant(md5Hash, tenantID)
-	if err == nil && existingFile != nil {
-		// 文件已存在，直接返回现有文件信息
+	// 根据文件内容检测类型，拒绝内容与扩展名不符的文件
+	mimeType, err := s.detectMimeType(head.buf, file.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// 文件已存在，直接返回现有文件信息
+	if existingFile := s.findInstantFile(ctx, md5Hash, file.Size, tenantID); existingFile != nil {
 		return existingFile, nil
 	}
 
@@ -94,9 +98,8 @@ func (s *FileService) UploadFile(file *multipart.FileHeader, userID, tenantID ui
 	// 生成唯一文件名
 	fileName := s.generateFileName(file.Filename)
 
-	// 生成存储路径
-	dateDir := time.Now().Format("2006/01/02")
-	storagePath := storage.GenerateStoragePath(tenantID, dateDir, fileName, isPublic)
+	// 生成存储路径，启用病毒扫描时先上传到隔离区
+	storagePath, quarantined := s.uploadPath(tenantID, fileName, isPublic)
 
 	// 创建存储管理器
 	storageManager, err := storage.NewManager(storageConfig)
@@ -109,10 +112,13 @@ func (s *FileService) UploadFile(file *multipart.FileHeader, userID, tenantID ui
 	}
 
 	// 上传文件到存储系统
-	accessURL, err := storageManager.Upload(src, storagePath, isPublic)
+	accessURL, err := storageManager.Upload(src, storagePath, isPublic && !quarantined)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload file: %w", err)
 	}
+	if quarantined {
+		accessURL = "" // 扫描通过后才生成访问地址
+	}
 
 	// 创建文件记录
 	fileModel := &model.File{
@@ -124,34 +130,61 @@ func (s *FileService) UploadFile(file *multipart.FileHeader, userID, tenantID ui
 		FilePath:     storagePath,
 		FileSize:     file.Size,
 		FileType:     fileExt,
-		MimeType:     s.getMimeType(file.Header.Get("Content-Type"), file.Filename),
+		MimeType:     mimeType,
 		MD5:          md5Hash,
 		UploadUserID: userID,
 		UsageType:    usageType,
 		StorageType:  storageConfig.Type,
 		IsPublic:     isPublic,
 		AccessURL:    accessURL,
+		ScanStatus:   s.scanStatus(quarantined),
 	}
 
 	// 保存到数据库
-	if err := s.fileRepo.Create(fileModel); err != nil {
+	if err := s.fileRepo.WithContext(ctx).Create(fileModel); err != nil {
 		// 删除已上传的文件
 		storageManager.Delete(storagePath)
 		return nil, fmt.Errorf("failed to save file record: %w", err)
 	}
 
+	s.submitScan(fileModel)
 	return fileModel, nil
 }
 
+// resolveIsPublic 确定文件是否公开访问：系统logo和头像强制公开，未指定时使用租户配置的默认值
+func (s *FileService) resolveIsPublic(usageType string, isPublic *bool, storageConfig *systemModel.FileStorageConfig) bool {
+	if usageType == "system-logo" || usageType == "avatar" {
+		return true
+	}
+	if isPublic != nil {
+		return *isPublic
+	}
+	return storageConfig.DefaultPublic
+}
+
+// findInstantFile 查找租户内MD5和大小相同且扫描通过的已有文件用于秒传，不存在时返回nil
+func (s *FileService) findInstantFile(ctx context.Context, md5Hash string, fileSize int64, tenantID uint64) *model.File {
+	if md5Hash == "" {
+		return nil
+	}
+	existingFile, err := s.fileRepo.WithContext(ctx).GetByMD5AndTenant(md5Hash, tenantID)
+	if err != nil || existingFile == nil || existingFile.FileSize != fileSize {
+		return nil
+	}
+	return existingFile
+}
+
 // GetFileByID 根据ID获取文件
-func (s *FileService) GetFileByID(id uint64) (*model.File, error) {
-	return s.fileRepo.GetByID(id)
+func (s *FileService) GetFileByID(ctx context.Context, id uint64) (*model.File, error) {
+	return s.fileRepo.WithContext(ctx).GetByID(id)
 }
 
 // DeleteFile 删除文件（支持新的存储架构）
-func (s *FileService) DeleteFile(id uint64) error {
+func (s *FileService) DeleteFile(ctx context.Context, id uint64) error {
+	fileRepo := s.fileRepo.WithContext(ctx)
+
 	// 获取文件信息
-	file, err := s.fileRepo.GetByID(id)
+	file, err := fileRepo.GetByID(id)
 	if err != nil {
 		return fmt.Errorf("file not found: %w", err)
 	}
@@ -180,7 +213,7 @@ func (s *FileService) DeleteFile(id uint64) error {
 	}
 
 	// 删除数据库记录
-	if err := s.fileRepo.Delete(id); err != nil {
+	if err := fileRepo.Delete(id); err != nil {
 		return fmt.Errorf("failed to delete file record: %w", err)
 	}
 
@@ -194,15 +227,15 @@ func (s *FileService) GetFilesByUser(userID, tenantID uint64, page, pageSize int
 }
 
 // GetAllFiles 获取所有文件列表（管理员功能）
-func (s *FileService) GetAllFiles(tenantID uint64, page, pageSize int, filters map[string]interface{}) ([]*model.File, int64, error) {
+func (s *FileService) GetAllFiles(filter sharedModel.DataFilter, page, pageSize int, filters map[string]interface{}) ([]*model.File, int64, error) {
 	offset := (page - 1) * pageSize
-	return s.fileRepo.GetAllFiles(tenantID, offset, pageSize, filters)
+	return s.fileRepo.GetAllFiles(filter, offset, pageSize, filters)
 }
 
-// GetPrivateFileContent 获取私有文件内容（带权限验证）
-func (s *FileService) GetPrivateFileContent(fileID, userID, tenantID uint64) (*model.File, []byte, error) {
+// OpenPrivateFile 打开私有文件用于流式下载（带权限验证），调用方负责关闭
+func (s *FileService) OpenPrivateFile(ctx context.Context, fileID, userID, tenantID uint64) (*model.File, io.ReadSeekCloser, error) {
 	// 获取文件信息
-	file, err := s.GetFileByID(fileID)
+	file, err := s.GetFileByID(ctx, fileID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("file not found")
 	}
@@ -212,6 +245,11 @@ func (s *FileService) GetPrivateFileContent(fileID, userID, tenantID uint64) (*m
 		return nil, nil, fmt.Errorf("access denied")
 	}
 
+	// 未通过安全扫描的文件不允许下载
+	if file.IsQuarantined() {
+		return nil, nil, fmt.Errorf("file quarantined")
+	}
+
 	// 验证文件是否为私有文件
 	if file.IsPublic {
 		return nil, nil, fmt.Errorf("public file should be accessed directly")
@@ -221,17 +259,17 @@ func (s *FileService) GetPrivateFileContent(fileID, userID, tenantID uint64) (*m
 	// 这里可以添加更复杂的权限逻辑，比如检查用户角色等
 	// 目前简化为：只要是同一租户的用户就可以访问私有文件
 
-	// 读取文件内容
-	fileContent, err := s.readFileContent(file)
+	// 打开文件内容
+	reader, err := s.openFileContent(file)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read file content: %w", err)
+		return nil, nil, fmt.Errorf("failed to open file content: %w", err)
 	}
 
-	return file, fileContent, nil
+	return file, reader, nil
 }
 
-// readFileContent 从存储中读取文件内容
-func (s *FileService) readFileContent(file *model.File) ([]byte, error) {
+// openFileContent 通过租户的存储提供者打开文件内容，本地和OSS存储均按需读取
+func (s *FileService) openFileContent(file *model.File) (io.ReadSeekCloser, error) {
 	// 获取租户的存储配置
 	tenant, err := s.tenantService.GetTenant(file.TenantID)
 	if err != nil {
@@ -249,16 +287,7 @@ func (s *FileService) readFileContent(file *model.File) ([]byte, error) {
 		return nil, fmt.Errorf("failed to create storage manager: %w", err)
 	}
 
-	// 获取文件完整路径
-	fullPath := storageManager.GetFullPath(file.FilePath, file.IsPublic)
-
-	// 读取文件内容
-	content, err := os.ReadFile(fullPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
-	}
-
-	return content, nil
+	return storageManager.Open(file.FilePath)
 }
 
 // isAllowedFileType 检查文件类型是否允许
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/modules/files/model"
+	"github.com/LiteMove/light-stack/internal/shared/storage"
+	"github.com/LiteMove/light-stack/pkg/logger"
+	"github.com/LiteMove/light-stack/pkg/scanner"
+)
+
+const (
+	scanRetryDelay     = 10 * time.Minute // 等待扫描的文件超过该时长未处理时由后台任务重试
+	scanRetryBatchSize = 100              // 每次重试扫描的文件数
+	scanResultMaxLen   = 255
+)
+
+// uploadPath 生成上传路径，启用病毒扫描时先上传到隔离区，返回是否隔离
+func (s *FileService) uploadPath(tenantID uint64, fileName string, isPublic bool) (string, bool) {
+	dateDir := time.Now().Format("2006/01/02")
+	if scanner.Enabled(s.scanner) {
+		return storage.GenerateQuarantinePath(tenantID, dateDir, fileName), true
+	}
+	return storage.GenerateStoragePath(tenantID, dateDir, fileName, isPublic), false
+}
+
+// scanStatus 新上传文件的扫描状态
+func (s *FileService) scanStatus(quarantined bool) int {
+	if quarantined {
+		return model.FileScanStatusPending
+	}
+	return model.FileScanStatusClean
+}
+
+// submitScan 对隔离区中的文件发起异步扫描
+func (s *FileService) submitScan(file *model.File) {
+	if file.ScanStatus != model.FileScanStatusPending {
+		return
+	}
+	go func(fileID uint64) {
+		if err := s.ScanFile(fileID); err != nil {
+			logger.WithField("fileId", fileID).Warn("Failed to scan file:", err)
+		}
+	}(file.ID)
+}
+
+// ScanFile 扫描隔离区中的文件，通过后移动到正式存储路径，检出威胁时删除文件
+func (s *FileService) ScanFile(fileID uint64) error {
+	file, err := s.fileRepo.GetByID(fileID)
+	if err != nil {
+		return fmt.Errorf("file not found: %w", err)
+	}
+	if file.ScanStatus != model.FileScanStatusPending && file.ScanStatus != model.FileScanStatusFailed {
+		return nil
+	}
+
+	storageManager, _, err := s.newStorageManager(file.TenantID)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.scanStoredFile(storageManager, file.FilePath)
+	if errors.Is(err, scanner.ErrTooLarge) {
+		// 超出扫描大小限制，重试也无法完成，保持隔离等待管理员处理
+		file.ScanStatus = model.FileScanStatusOversize
+		file.ScanResult = truncateScanResult(err.Error())
+		logger.WithFields(map[string]interface{}{
+			"fileId":   file.ID,
+			"fileSize": file.FileSize,
+		}).Warn("File exceeds scanner size limit")
+		return s.fileRepo.UpdateFile(file)
+	}
+	if err != nil {
+		return s.markScanFailed(file, err)
+	}
+
+	if !result.Clean {
+		if err := storageManager.Delete(file.FilePath); err != nil {
+			logger.WithField("fileId", file.ID).Warn("Failed to delete infected file:", err)
+		}
+		file.ScanStatus = model.FileScanStatusInfected
+		file.ScanResult = truncateScanResult(result.Threat)
+		file.AccessURL = ""
+		logger.WithFields(map[string]interface{}{
+			"fileId":   file.ID,
+			"tenantId": file.TenantID,
+			"threat":   result.Threat,
+		}).Warn("Infected file detected")
+		return s.fileRepo.UpdateFile(file)
+	}
+
+	// 扫描通过，从隔离区移动到正式路径
+	if storage.IsQuarantinePath(file.FilePath) {
+		releasePath := storage.ReleasePath(file.FilePath, file.IsPublic)
+		accessURL, err := storageManager.Move(file.FilePath, releasePath, file.IsPublic)
+		if err != nil {
+			return s.markScanFailed(file, fmt.Errorf("failed to release file: %w", err))
+		}
+		file.FilePath = releasePath
+		file.AccessURL = accessURL
+	}
+
+	file.ScanStatus = model.FileScanStatusClean
+	file.ScanResult = ""
+	return s.fileRepo.UpdateFile(file)
+}
+
+// RescanQuarantinedFiles 重试长时间未完成或扫描失败的文件（跨租户）
+func (s *FileService) RescanQuarantinedFiles() (int, error) {
+	files, err := s.fileRepo.GetPendingScan(time.Now().Add(-scanRetryDelay), scanRetryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending scan files: %w", err)
+	}
+
+	count := 0
+	for _, file := range files {
+		if err := s.ScanFile(file.ID); err != nil {
+			logger.WithField("fileId", file.ID).Warn("Failed to rescan file:", err)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// StartScanRetryJob 启动后台任务，定期重试扫描隔离区中的文件，未启用扫描时不启动
+func (s *FileService) StartScanRetryJob(interval time.Duration) {
+	if !scanner.Enabled(s.scanner) {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			count, err := s.RescanQuarantinedFiles()
+			if err != nil {
+				logger.Error("Failed to rescan quarantined files:", err)
+			} else if count > 0 {
+				logger.WithField("count", count).Info("Rescanned quarantined files")
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// scanStoredFile 读取存储中的文件进行扫描
+func (s *FileService) scanStoredFile(storageManager *storage.Manager, path string) (*scanner.Result, error) {
+	reader, err := storageManager.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return s.scanner.Scan(reader)
+}
+
+// markScanFailed 标记扫描失败，等待后台任务重试
+func (s *FileService) markScanFailed(file *model.File, scanErr error) error {
+	file.ScanStatus = model.FileScanStatusFailed
+	file.ScanResult = truncateScanResult(scanErr.Error())
+	if err := s.fileRepo.UpdateFile(file); err != nil {
+		return fmt.Errorf("failed to update scan status: %w", err)
+	}
+	return scanErr
+}
+
+// truncateScanResult 截断扫描结果以适应字段长度
+func truncateScanResult(result string) string {
+	runes := []rune(result)
+	if len(runes) > scanResultMaxLen {
+		return string(runes[:scanResultMaxLen])
+	}
+	return result
+}
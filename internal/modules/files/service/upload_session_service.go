@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/modules/files/model"
+	"github.com/LiteMove/light-stack/internal/modules/files/repository"
+	systemModel "github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/internal/shared/config"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/internal/shared/storage"
+	"github.com/LiteMove/light-stack/pkg/logger"
+)
+
+// 分片上传错误
+var (
+	ErrUploadSessionNotFound = errors.New("上传会话不存在")
+	ErrUploadSessionClosed   = errors.New("上传会话已结束或已过期")
+	ErrUploadChunkInvalid    = errors.New("分片序号或大小不正确")
+	ErrUploadIncomplete      = errors.New("分片尚未全部上传")
+	ErrUploadMD5Mismatch     = errors.New("文件MD5校验失败，请重新上传")
+)
+
+const (
+	minChunkSize     = 5 << 20 // 对象存储分片上传除最后一片外的最小分片大小
+	maxChunkCount    = 10000   // 对象存储分片上传的最大分片数
+	cleanupBatchSize = 100     // 每次清理的过期会话数
+)
+
+// InitUploadRequest 初始化分片上传请求
+type InitUploadRequest struct {
+	FileName  string `json:"fileName" binding:"required,max=255"`
+	FileSize  int64  `json:"fileSize" binding:"required,min=1"`
+	MD5       string `json:"md5" binding:"required,len=32,hexadecimal"`
+	MimeType  string `json:"mimeType" binding:"max=100"`
+	UsageType string `json:"usageType" binding:"max=50"`
+	IsPublic  *bool  `json:"isPublic"` // 未指定时使用租户配置的默认值
+}
+
+// UploadSessionResult 分片上传会话信息
+type UploadSessionResult struct {
+	Instant       bool                 `json:"instant"` // 文件已存在，无需上传
+	Session       *model.UploadSession `json:"session,omitempty"`
+	UploadedParts []int                `json:"uploadedParts"` // 已接收的分片序号
+	File          *model.FileProfile   `json:"file,omitempty"`
+}
+
+// InitUploadSession 初始化分片上传，相同MD5的文件已存在时直接返回该文件
+func (s *FileService) InitUploadSession(ctx context.Context, req *InitUploadRequest, userID, tenantID uint64) (*UploadSessionResult, error) {
+	storageManager, storageConfig, err := s.newStorageManager(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证文件大小限制
+	if req.FileSize > storageConfig.MaxChunkedFileSize {
+		return nil, fmt.Errorf("file size exceeds limit: %d > %d", req.FileSize, storageConfig.MaxChunkedFileSize)
+	}
+
+	// 验证文件类型
+	fileExt := s.getFileExtension(req.FileName)
+	if !s.isAllowedFileType(fileExt, storageConfig.AllowedTypes) {
+		return nil, fmt.Errorf("file type not allowed: %s", fileExt)
+	}
+
+	// 文件已存在时秒传
+	md5Hash := strings.ToLower(req.MD5)
+	if existingFile := s.findInstantFile(ctx, md5Hash, req.FileSize, tenantID); existingFile != nil {
+		profile := existingFile.ToProfile()
+		return &UploadSessionResult{Instant: true, UploadedParts: []int{}, File: &profile}, nil
+	}
+
+	isPublic := s.resolveIsPublic(req.UsageType, req.IsPublic, storageConfig)
+
+	// 启用病毒扫描时上传到隔离区
+	chunkSize := uploadChunkSize(req.FileSize)
+	fileName := s.generateFileName(req.FileName)
+	storagePath, quarantined := s.uploadPath(tenantID, fileName, isPublic)
+
+	uploadID, err := storageManager.InitMultipart(storagePath, isPublic && !quarantined)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init multipart upload: %w", err)
+	}
+
+	session := &model.UploadSession{
+		TenantBaseModel: sharedModel.TenantBaseModel{
+			TenantID: tenantID,
+		},
+		UploadUserID: userID,
+		OriginalName: req.FileName,
+		FileName:     fileName,
+		FilePath:     storagePath,
+		FileSize:     req.FileSize,
+		FileType:     fileExt,
+		MimeType:     s.getMimeType(req.MimeType, req.FileName),
+		MD5:          md5Hash,
+		ChunkSize:    chunkSize,
+		TotalChunks:  int((req.FileSize + chunkSize - 1) / chunkSize),
+		UsageType:    req.UsageType,
+		StorageType:  storageConfig.Type,
+		IsPublic:     isPublic,
+		UploadID:     uploadID,
+		Status:       model.UploadSessionStatusUploading,
+		ExpiresAt:    time.Now().Add(uploadSessionExpire()),
+	}
+	if err := s.sessionRepo.WithContext(ctx).Create(session); err != nil {
+		storageManager.AbortMultipart(storagePath, uploadID)
+		return nil, fmt.Errorf("failed to save upload session: %w", err)
+	}
+
+	return &UploadSessionResult{Session: session, UploadedParts: []int{}}, nil
+}
+
+// GetUploadSession 获取上传会话及已接收的分片，用于断点续传
+func (s *FileService) GetUploadSession(ctx context.Context, sessionID, userID uint64) (*UploadSessionResult, error) {
+	session, err := s.getUserSession(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := s.sessionRepo.WithContext(ctx).GetParts(session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uploaded parts: %w", err)
+	}
+
+	result := &UploadSessionResult{Session: session, UploadedParts: make([]int, 0, len(parts))}
+	for _, part := range parts {
+		result.UploadedParts = append(result.UploadedParts, part.PartNumber)
+	}
+	if session.Status == model.UploadSessionStatusCompleted && session.FileID != 0 {
+		if file, err := s.fileRepo.WithContext(ctx).GetByID(session.FileID); err == nil {
+			profile := file.ToProfile()
+			result.File = &profile
+		}
+	}
+	return result, nil
+}
+
+// UploadChunk 上传分片，分片序号从1开始，除最后一片外大小必须等于会话的分片大小
+func (s *FileService) UploadChunk(ctx context.Context, sessionID, userID uint64, partNumber int, data io.Reader, size int64) error {
+	session, err := s.getOpenSession(ctx, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if partNumber < 1 || partNumber > session.TotalChunks || size != session.ChunkLength(partNumber) {
+		return ErrUploadChunkInvalid
+	}
+
+	storageManager, _, err := s.newStorageManager(session.TenantID)
+	if err != nil {
+		return err
+	}
+
+	etag, err := storageManager.UploadPart(session.FilePath, session.UploadID, partNumber, data, size)
+	if err != nil {
+		return fmt.Errorf("failed to upload chunk %d: %w", partNumber, err)
+	}
+
+	return s.sessionRepo.WithContext(ctx).SavePart(&model.UploadSessionPart{
+		SessionID:  session.ID,
+		PartNumber: partNumber,
+		Size:       size,
+		ETag:       etag,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// CompleteUploadSession 合并分片并校验MD5，校验通过后创建文件记录
+func (s *FileService) CompleteUploadSession(ctx context.Context, sessionID, userID uint64) (*model.File, error) {
+	session, err := s.getOpenSession(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessionRepo := s.sessionRepo.WithContext(ctx)
+
+	parts, err := sessionRepo.GetParts(session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uploaded parts: %w", err)
+	}
+	if len(parts) != session.TotalChunks {
+		return nil, ErrUploadIncomplete
+	}
+	uploadedParts := make([]storage.UploadedPart, 0, len(parts))
+	for i, part := range parts {
+		if part.PartNumber != i+1 || part.Size != session.ChunkLength(part.PartNumber) {
+			return nil, ErrUploadIncomplete
+		}
+		uploadedParts = append(uploadedParts, storage.UploadedPart{Number: part.PartNumber, ETag: part.ETag})
+	}
+
+	storageManager, _, err := s.newStorageManager(session.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先将会话标记为合并中，并发的完成或取消请求只有一个能继续
+	claimed, err := sessionRepo.UpdateStatus(session.ID, model.UploadSessionStatusUploading, model.UploadSessionStatusCompleting, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update upload session status: %w", err)
+	}
+	if !claimed {
+		return nil, ErrUploadSessionClosed
+	}
+
+	accessURL, err := storageManager.CompleteMultipart(session.FilePath, session.UploadID, uploadedParts, session.IsPublic && !storage.IsQuarantinePath(session.FilePath))
+	if err != nil {
+		// 合并失败时恢复为上传中，允许客户端重试
+		sessionRepo.UpdateStatus(session.ID, model.UploadSessionStatusCompleting, model.UploadSessionStatusUploading, 0)
+		return nil, fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	// 合并后分片已不可再用，后续失败时删除文件并结束会话，客户端需重新上传
+	discard := func() {
+		storageManager.Delete(session.FilePath)
+		sessionRepo.UpdateStatus(session.ID, model.UploadSessionStatusCompleting, model.UploadSessionStatusAborted, 0)
+		sessionRepo.DeleteParts(session.ID)
+	}
+
+	// 读取合并后的文件校验MD5，分片ETag无法得出整个文件的MD5
+	md5Hash, head, err := s.storedFileMD5(storageManager, session.FilePath)
+	if err != nil {
+		discard()
+		return nil, fmt.Errorf("failed to verify file MD5: %w", err)
+	}
+	if md5Hash != session.MD5 {
+		discard()
+		return nil, ErrUploadMD5Mismatch
+	}
+
+	mimeType, err := s.detectMimeType(head, session.OriginalName)
+	if err != nil {
+		discard()
+		return nil, err
+	}
+
+	fileModel := &model.File{
+		TenantBaseModel: sharedModel.TenantBaseModel{
+			TenantID: session.TenantID,
+		},
+		OriginalName: session.OriginalName,
+		FileName:     session.FileName,
+		FilePath:     session.FilePath,
+		FileSize:     session.FileSize,
+		FileType:     session.FileType,
+		MimeType:     mimeType,
+		MD5:          session.MD5,
+		UploadUserID: session.UploadUserID,
+		UsageType:    session.UsageType,
+		StorageType:  session.StorageType,
+		IsPublic:     session.IsPublic,
+		AccessURL:    accessURL,
+		ScanStatus:   model.FileScanStatusClean,
+	}
+	// 隔离区中的文件扫描通过后才生成访问地址
+	if storage.IsQuarantinePath(session.FilePath) {
+		fileModel.ScanStatus = model.FileScanStatusPending
+		fileModel.AccessURL = ""
+	}
+	if err := s.fileRepo.WithContext(ctx).Create(fileModel); err != nil {
+		discard()
+		return nil, fmt.Errorf("failed to save file record: %w", err)
+	}
+
+	if _, err := sessionRepo.UpdateStatus(session.ID, model.UploadSessionStatusCompleting, model.UploadSessionStatusCompleted, fileModel.ID); err != nil {
+		logger.WithField("sessionId", session.ID).Warn("Failed to update upload session status:", err)
+	}
+	sessionRepo.DeleteParts(session.ID)
+
+	s.submitScan(fileModel)
+	return fileModel, nil
+}
+
+// AbortUploadSession 取消分片上传并清理已上传的分片
+func (s *FileService) AbortUploadSession(ctx context.Context, sessionID, userID uint64) error {
+	session, err := s.getOpenSession(ctx, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	return s.abortSession(s.sessionRepo.WithContext(ctx), session)
+}
+
+// CleanupExpiredUploadSessions 清理过期未完成的上传会话（跨租户）
+func (s *FileService) CleanupExpiredUploadSessions() (int, error) {
+	sessions, err := s.sessionRepo.GetExpired(time.Now(), cleanupBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expired upload sessions: %w", err)
+	}
+
+	count := 0
+	for _, session := range sessions {
+		if err := s.abortSession(s.sessionRepo, session); err != nil {
+			logger.WithField("sessionId", session.ID).Warn("Failed to cleanup upload session:", err)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// StartUploadCleanupJob 启动后台任务，定期清理过期的上传会话
+func (s *FileService) StartUploadCleanupJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			count, err := s.CleanupExpiredUploadSessions()
+			if err != nil {
+				logger.Error("Failed to cleanup expired upload sessions:", err)
+			} else if count > 0 {
+				logger.WithField("count", count).Info("Cleaned up expired upload sessions")
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// abortSession 标记会话为已取消并取消存储端分片上传，会话已进入合并时不再取消
+func (s *FileService) abortSession(sessionRepo *repository.UploadSessionRepository, session *model.UploadSession) error {
+	storageManager, _, err := s.newStorageManager(session.TenantID)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := sessionRepo.UpdateStatus(session.ID, model.UploadSessionStatusUploading, model.UploadSessionStatusAborted, 0)
+	if err != nil {
+		return fmt.Errorf("failed to update upload session status: %w", err)
+	}
+	if !claimed {
+		return ErrUploadSessionClosed
+	}
+
+	if err := storageManager.AbortMultipart(session.FilePath, session.UploadID); err != nil {
+		return err
+	}
+	return sessionRepo.DeleteParts(session.ID)
+}
+
+// getUserSession 获取当前用户的上传会话
+func (s *FileService) getUserSession(ctx context.Context, sessionID, userID uint64) (*model.UploadSession, error) {
+	session, err := s.sessionRepo.WithContext(ctx).GetByID(sessionID)
+	if err != nil || session.UploadUserID != userID {
+		return nil, ErrUploadSessionNotFound
+	}
+	return session, nil
+}
+
+// getOpenSession 获取当前用户仍可上传的会话
+func (s *FileService) getOpenSession(ctx context.Context, sessionID, userID uint64) (*model.UploadSession, error) {
+	session, err := s.getUserSession(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != model.UploadSessionStatusUploading || session.IsExpired() {
+		return nil, ErrUploadSessionClosed
+	}
+	return session, nil
+}
+
+// storedFileMD5 读取存储中的文件计算MD5，同时返回文件头用于内容类型检测
+func (s *FileService) storedFileMD5(storageManager *storage.Manager, path string) (string, []byte, error) {
+	reader, err := storageManager.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer reader.Close()
+
+	head := &headBuffer{}
+	md5Hash, err := s.calculateMD5(io.TeeReader(reader, head))
+	return md5Hash, head.buf, err
+}
+
+// storedFileHead 读取存储中文件的文件头
+func (s *FileService) storedFileHead(storageManager *storage.Manager, path string) ([]byte, error) {
+	reader, err := storageManager.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// newStorageManager 根据租户的存储配置创建存储管理器
+func (s *FileService) newStorageManager(tenantID uint64) (*storage.Manager, *systemModel.FileStorageConfig, error) {
+	tenant, err := s.tenantService.GetTenant(tenantID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	storageConfig, err := tenant.GetFileStorageConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get storage config: %w", err)
+	}
+
+	storageManager, err := storage.NewManager(storageConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("存储管理器初始化失败: %w", err)
+	}
+	return storageManager, storageConfig, nil
+}
+
+// uploadChunkSize 计算分片大小，不小于对象存储的最小分片且分片数不超过上限
+func uploadChunkSize(fileSize int64) int64 {
+	chunkSize := int64(minChunkSize)
+	if cfg := config.Get(); cfg != nil && cfg.File.ChunkSize > chunkSize {
+		chunkSize = cfg.File.ChunkSize
+	}
+	if minSize := (fileSize + maxChunkCount - 1) / maxChunkCount; minSize > chunkSize {
+		chunkSize = minSize
+	}
+	return chunkSize
+}
+
+// uploadSessionExpire 上传会话有效期
+func uploadSessionExpire() time.Duration {
+	if cfg := config.Get(); cfg != nil && cfg.File.UploadSessionExpire > 0 {
+		return time.Duration(cfg.File.UploadSessionExpire) * time.Second
+	}
+	return 24 * time.Hour
+}
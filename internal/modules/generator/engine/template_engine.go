@@ -113,6 +113,7 @@ func (e *TemplateEngine) loadTemplate(name, path string) (*template.Template, er
 		"getHtmlInputType":   getHtmlInputType,
 		"getValidationRules": getValidationRules,
 		"getDefaultValue":    getDefaultValue,
+		"hasColumn":          hasColumn,
 	}
 
 	// 解析模板文件
@@ -370,6 +371,16 @@ func getValidationRules(field model.ColumnInfo) []string {
 	return rules
 }
 
+// hasColumn 判断表是否包含指定字段，用于生成数据权限过滤条件
+func hasColumn(fields []model.ColumnInfo, columnName string) bool {
+	for _, field := range fields {
+		if field.ColumnName == columnName {
+			return true
+		}
+	}
+	return false
+}
+
 // getDefaultValue 获取默认值
 func getDefaultValue(field model.ColumnInfo) string {
 	switch field.GoType {
@@ -10,33 +10,35 @@ func RegisterGeneratorRoutes(api *gin.RouterGroup) {
 	v1 := api.Group("/v1")
 
 	// 代码生成器路由（需要认证）
-	generator := v1.Group("/gen")
-	generator.Use(middleware.Auth())
+	group := v1.Group("/gen")
+	group.Use(middleware.Auth())
+	group.Use(middleware.OperationLog(globals.OperationLogSvc())) // 记录写操作审计日志
+	generator := middleware.Guard(group)
 	{
 		// 数据库表分析
-		generator.GET("/tables", globals.GeneratorCtrl().GetTableList)                       // 获取数据库表列表
-		generator.GET("/tables/:tableName", globals.GeneratorCtrl().GetTableInfo)            // 获取表结构信息
-		generator.GET("/tables/:tableName/columns", globals.GeneratorCtrl().GetTableColumns) // 获取表字段信息
+		generator.GET("/tables", globals.GeneratorCtrl().GetTableList, "tool:gen:list")                       // 获取数据库表列表
+		generator.GET("/tables/:tableName", globals.GeneratorCtrl().GetTableInfo, "tool:gen:list")            // 获取表结构信息
+		generator.GET("/tables/:tableName/columns", globals.GeneratorCtrl().GetTableColumns, "tool:gen:list") // 获取表字段信息
 
 		// 获取系统菜单树（用于选择父级菜单）
-		generator.GET("/menus/tree", globals.GeneratorCtrl().GetSystemMenus) // 获取系统菜单树
+		generator.GET("/menus/tree", globals.GeneratorCtrl().GetSystemMenus, "tool:gen:list") // 获取系统菜单树
 
 		// 生成配置管理
-		generator.POST("/configs", globals.GenConfigCtrl().CreateConfig)                         // 创建生成配置
-		generator.GET("/configs", globals.GenConfigCtrl().GetConfigList)                         // 获取配置列表
-		generator.GET("/configs/:id", globals.GenConfigCtrl().GetConfig)                         // 获取配置详情
-		generator.PUT("/configs/:id", globals.GenConfigCtrl().UpdateConfig)                      // 更新配置
-		generator.DELETE("/configs/:id", globals.GenConfigCtrl().DeleteConfig)                   // 删除配置
-		generator.POST("/configs/import/:tableName", globals.GenConfigCtrl().ImportTableConfig)  // 导入表配置
-		generator.GET("/configs/table/:tableName", globals.GenConfigCtrl().GetConfigByTableName) // 根据表名获取配置
+		generator.POST("/configs", globals.GenConfigCtrl().CreateConfig, "tool:gen:create")                                       // 创建生成配置
+		generator.GET("/configs", globals.GenConfigCtrl().GetConfigList, "tool:gen:list")                                         // 获取配置列表
+		generator.GET("/configs/:id", globals.GenConfigCtrl().GetConfig, "tool:gen:list")                                         // 获取配置详情
+		generator.PUT("/configs/:id", globals.GenConfigCtrl().UpdateConfig, "tool:gen:update")                                    // 更新配置
+		generator.DELETE("/configs/:id", middleware.RejectImpersonation(globals.GenConfigCtrl().DeleteConfig), "tool:gen:delete") // 删除配置
+		generator.POST("/configs/import/:tableName", globals.GenConfigCtrl().ImportTableConfig, "tool:gen:create")                // 导入表配置
+		generator.GET("/configs/table/:tableName", globals.GenConfigCtrl().GetConfigByTableName, "tool:gen:list")                 // 根据表名获取配置
 
 		// 代码生成
-		generator.GET("/preview/:configId", globals.GeneratorCtrl().PreviewCode)   // 临时预览接口
-		generator.POST("/generate", globals.GeneratorCtrl().GenerateCode)          // 生成代码
-		generator.GET("/download/:taskId", globals.GeneratorCtrl().DownloadCode)   // 下载代码包
-		generator.GET("/templates", globals.GeneratorCtrl().GetAvailableTemplates) // 获取可用模板
+		generator.GET("/preview/:configId", globals.GeneratorCtrl().PreviewCode, "tool:gen:preview")  // 临时预览接口
+		generator.POST("/generate", globals.GeneratorCtrl().GenerateCode, "tool:gen:generate")        // 生成代码
+		generator.GET("/download/:taskId", globals.GeneratorCtrl().DownloadCode, "tool:gen:generate") // 下载代码包
+		generator.GET("/templates", globals.GeneratorCtrl().GetAvailableTemplates, "tool:gen:list")   // 获取可用模板
 
 		// 生成历史
-		generator.GET("/history", globals.GeneratorCtrl().GetHistory) // 获取生成历史
+		generator.GET("/history", globals.GeneratorCtrl().GetHistory, "tool:gen:list") // 获取生成历史
 	}
 }
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
+	"github.com/LiteMove/light-stack/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// DeptController 部门控制器
+type DeptController struct {
+	deptService service.DeptService
+	validator   *validator.Validate
+}
+
+// NewDeptController 创建部门控制器
+func NewDeptController(deptService service.DeptService) *DeptController {
+	return &DeptController{
+		deptService: deptService,
+		validator:   validator.New(),
+	}
+}
+
+// DeptRequest 创建/更新部门请求
+type DeptRequest struct {
+	ParentID  uint64 `json:"parentId"`
+	Name      string `json:"name" validate:"required,min=1,max=100"`
+	LeaderID  uint64 `json:"leaderId"`
+	Phone     string `json:"phone" validate:"max=20"`
+	Email     string `json:"email" validate:"omitempty,email,max=100"`
+	SortOrder int    `json:"sortOrder"`
+	Status    int    `json:"status" validate:"required,oneof=1 2"`
+}
+
+// DeptTreeRequest 部门树请求
+type DeptTreeRequest struct {
+	Status int `form:"status" validate:"oneof=0 1 2"`
+}
+
+// toDept 转换为部门模型
+func (r *DeptRequest) toDept(tenantID uint64) *model.Dept {
+	dept := &model.Dept{
+		ParentID:  r.ParentID,
+		Name:      r.Name,
+		LeaderID:  r.LeaderID,
+		Phone:     r.Phone,
+		Email:     r.Email,
+		SortOrder: r.SortOrder,
+		Status:    r.Status,
+	}
+	dept.TenantID = tenantID
+	return dept
+}
+
+// CreateDept 创建部门
+func (c *DeptController) CreateDept(ctx *gin.Context) {
+	var req DeptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	dept := req.toDept(tenantID)
+	if err := c.deptService.CreateDept(ctx.Request.Context(), dept); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	profile, err := c.deptService.GetDept(ctx.Request.Context(), tenantID, dept.ID)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, profile)
+}
+
+// GetDeptTree 获取部门树
+func (c *DeptController) GetDeptTree(ctx *gin.Context) {
+	var req DeptTreeRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	tree, err := c.deptService.GetDeptTree(ctx.Request.Context(), tenantID, req.Status)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, tree)
+}
+
+// GetDept 获取部门详情
+func (c *DeptController) GetDept(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "部门ID格式错误")
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	profile, err := c.deptService.GetDept(ctx.Request.Context(), tenantID, id)
+	if err != nil {
+		response.NotFound(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, profile)
+}
+
+// UpdateDept 更新部门
+func (c *DeptController) UpdateDept(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "部门ID格式错误")
+		return
+	}
+
+	var req DeptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	dept := req.toDept(tenantID)
+	dept.ID = id
+	if err := c.deptService.UpdateDept(ctx.Request.Context(), dept); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	profile, err := c.deptService.GetDept(ctx.Request.Context(), tenantID, id)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, profile)
+}
+
+// DeleteDept 删除部门
+func (c *DeptController) DeleteDept(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "部门ID格式错误")
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	if err := c.deptService.DeleteDept(ctx.Request.Context(), tenantID, id); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{"message": "部门删除成功"})
+}
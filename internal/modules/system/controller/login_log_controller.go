@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
+	"github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/internal/shared/utils"
+	"github.com/LiteMove/light-stack/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// LoginLogController 登录日志控制器
+type LoginLogController struct {
+	logService service.LoginLogService
+	validator  *validator.Validate
+}
+
+// NewLoginLogController 创建登录日志控制器
+func NewLoginLogController(logService service.LoginLogService) *LoginLogController {
+	return &LoginLogController{
+		logService: logService,
+		validator:  validator.New(),
+	}
+}
+
+// LoginLogListRequest 登录日志列表请求
+type LoginLogListRequest struct {
+	Page      int    `form:"page" validate:"min=1"`
+	PageSize  int    `form:"page_size" validate:"min=1,max=100"`
+	Username  string `form:"username"`
+	IP        string `form:"ip"`
+	Status    int    `form:"status" validate:"oneof=0 1 2"`
+	StartTime string `form:"startTime"`
+	EndTime   string `form:"endTime"`
+}
+
+// GetLoginLogs 获取登录日志列表
+func (c *LoginLogController) GetLoginLogs(ctx *gin.Context) {
+	var req LoginLogListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	// 参数验证
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	query := &repository.LoginLogQuery{
+		TenantID: tenantID,
+		Username: req.Username,
+		IP:       req.IP,
+		Status:   req.Status,
+	}
+
+	if req.StartTime != "" {
+		startTime, err := utils.ParseToTime(req.StartTime)
+		if err != nil {
+			response.BadRequest(ctx, "开始时间格式错误: "+err.Error())
+			return
+		}
+		query.StartTime = startTime
+	}
+	if req.EndTime != "" {
+		endTime, err := utils.ParseToTime(req.EndTime)
+		if err != nil {
+			response.BadRequest(ctx, "结束时间格式错误: "+err.Error())
+			return
+		}
+		query.EndTime = endTime
+	}
+
+	logs, total, err := c.logService.GetLogList(query, req.Page, req.PageSize)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	list := make([]model.LoginLogProfile, 0, len(logs))
+	for _, log := range logs {
+		list = append(list, log.ToProfile())
+	}
+
+	// 返回分页数据
+	response.Success(ctx, gin.H{
+		"list":      list,
+		"total":     total,
+		"page":      req.Page,
+		"page_size": req.PageSize,
+	})
+}
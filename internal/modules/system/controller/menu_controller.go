@@ -240,9 +240,9 @@ func (mc *MenuController) GetRoleMenus(c *gin.Context) {
 		return
 	}
 
-	menus, err := mc.menuService.GetRoleMenus(roleID)
+	menus, err := mc.menuService.GetRoleMenus(roleScope(c), roleID)
 	if err != nil {
-		response.BadRequest(c, "获取角色菜单失败")
+		response.BadRequest(c, err.Error())
 		return
 	}
 
@@ -297,8 +297,8 @@ func (mc *MenuController) AssignMenusToRole(c *gin.Context) {
 		return
 	}
 
-	if err := mc.menuService.AssignMenusToRole(roleID, req.MenuIDs); err != nil {
-		response.BadRequest(c, "分配菜单失败")
+	if err := mc.menuService.AssignMenusToRole(roleScope(c), roleID, req.MenuIDs); err != nil {
+		response.BadRequest(c, err.Error())
 		return
 	}
 
@@ -340,3 +340,14 @@ func (mc *MenuController) GetUserPermissions(c *gin.Context) {
 
 	response.Success(c, gin.H{"permissions": permissions})
 }
+
+// GetPermissionManifest 获取路由权限清单
+func (mc *MenuController) GetPermissionManifest(c *gin.Context) {
+	manifest, err := mc.menuService.GetPermissionManifest()
+	if err != nil {
+		response.InternalServerError(c, "获取权限清单失败")
+		return
+	}
+
+	response.Success(c, manifest)
+}
@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
+	"github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/internal/shared/utils"
+	"github.com/LiteMove/light-stack/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// OperationLogController 操作日志控制器
+type OperationLogController struct {
+	logService service.OperationLogService
+	validator  *validator.Validate
+}
+
+// NewOperationLogController 创建操作日志控制器
+func NewOperationLogController(logService service.OperationLogService) *OperationLogController {
+	return &OperationLogController{
+		logService: logService,
+		validator:  validator.New(),
+	}
+}
+
+// OperationLogFilterRequest 操作日志筛选条件
+type OperationLogFilterRequest struct {
+	UserID       uint64 `form:"userId"`
+	Username     string `form:"username"`
+	Operation    string `form:"operation"`
+	Method       string `form:"method" validate:"omitempty,oneof=POST PUT PATCH DELETE"`
+	Status       int    `form:"status" validate:"oneof=0 1 2"`
+	StartTime    string `form:"startTime"`
+	EndTime      string `form:"endTime"`
+	Impersonated bool   `form:"impersonated"` // 仅查询模拟登录期间的操作
+}
+
+// OperationLogListRequest 操作日志列表请求
+type OperationLogListRequest struct {
+	OperationLogFilterRequest
+	Page     int `form:"page" validate:"min=1"`
+	PageSize int `form:"page_size" validate:"min=1,max=100"`
+}
+
+// GetOperationLogs 获取操作日志列表
+func (c *OperationLogController) GetOperationLogs(ctx *gin.Context) {
+	var req OperationLogListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	// 参数验证
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	query, err := c.buildQuery(ctx, &req.OperationLogFilterRequest)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	logs, total, err := c.logService.GetLogList(ctx.Request.Context(), query, req.Page, req.PageSize)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	list := make([]model.OperationLogProfile, 0, len(logs))
+	for _, log := range logs {
+		list = append(list, log.ToProfile())
+	}
+
+	// 返回分页数据
+	response.Success(ctx, gin.H{
+		"list":      list,
+		"total":     total,
+		"page":      req.Page,
+		"page_size": req.PageSize,
+	})
+}
+
+// GetOperationLog 获取操作日志详情
+func (c *OperationLogController) GetOperationLog(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "日志ID格式错误")
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	log, err := c.logService.GetLog(ctx.Request.Context(), tenantID, id)
+	if err != nil {
+		response.NotFound(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, log.ToProfile())
+}
+
+// ExportOperationLogs 导出操作日志（CSV）
+func (c *OperationLogController) ExportOperationLogs(ctx *gin.Context) {
+	var req OperationLogFilterRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	// 参数验证
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	query, err := c.buildQuery(ctx, &req)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	logs, err := c.logService.ExportLogs(ctx.Request.Context(), query)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("operation_logs_%s.csv", time.Now().Format("20060102150405"))
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	ctx.Status(http.StatusOK)
+
+	// 写入UTF-8 BOM，保证Excel正确识别中文
+	ctx.Writer.Write([]byte("\xEF\xBB\xBF"))
+
+	writer := csv.NewWriter(ctx.Writer)
+	writer.Write([]string{"ID", "用户ID", "用户名", "操作", "请求方法", "请求URL", "请求参数", "操作结果", "错误信息", "IP", "User-Agent", "耗时(ms)", "状态", "模拟操作人", "操作时间"})
+	for _, log := range logs {
+		status := "成功"
+		if !log.IsSuccess() {
+			status = "失败"
+		}
+		writer.Write([]string{
+			strconv.FormatUint(log.ID, 10),
+			strconv.FormatUint(log.UserID, 10),
+			log.Username,
+			log.Operation,
+			log.Method,
+			log.URL,
+			log.Params,
+			log.Result,
+			log.ErrorMessage,
+			log.IP,
+			log.UserAgent,
+			strconv.Itoa(log.Duration),
+			status,
+			log.ImpersonatorName,
+			log.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	writer.Flush()
+}
+
+// buildQuery 构建查询条件
+func (c *OperationLogController) buildQuery(ctx *gin.Context, req *OperationLogFilterRequest) (*repository.OperationLogQuery, error) {
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+
+	query := &repository.OperationLogQuery{
+		TenantID:     tenantID,
+		UserID:       req.UserID,
+		Username:     req.Username,
+		Operation:    req.Operation,
+		Method:       req.Method,
+		Status:       req.Status,
+		Impersonated: req.Impersonated,
+	}
+
+	if req.StartTime != "" {
+		startTime, err := utils.ParseToTime(req.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("开始时间格式错误: %w", err)
+		}
+		query.StartTime = startTime
+	}
+	if req.EndTime != "" {
+		endTime, err := utils.ParseToTime(req.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("结束时间格式错误: %w", err)
+		}
+		query.EndTime = endTime
+	}
+
+	return query, nil
+}
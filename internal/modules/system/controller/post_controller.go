@@ -0,0 +1,260 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
+	"github.com/LiteMove/light-stack/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// PostController 岗位控制器
+type PostController struct {
+	postService service.PostService
+	validator   *validator.Validate
+}
+
+// NewPostController 创建岗位控制器
+func NewPostController(postService service.PostService) *PostController {
+	return &PostController{
+		postService: postService,
+		validator:   validator.New(),
+	}
+}
+
+// PostRequest 创建/更新岗位请求
+type PostRequest struct {
+	Code      string `json:"code" validate:"required,min=1,max=64"`
+	Name      string `json:"name" validate:"required,min=1,max=100"`
+	SortOrder int    `json:"sortOrder"`
+	Status    int    `json:"status" validate:"required,oneof=1 2"`
+	Remark    string `json:"remark" validate:"max=255"`
+}
+
+// PostListRequest 岗位列表请求
+type PostListRequest struct {
+	Page     int    `form:"page" validate:"min=1"`
+	PageSize int    `form:"page_size" validate:"min=1,max=100"`
+	Keyword  string `form:"keyword"`
+	Status   int    `form:"status" validate:"oneof=0 1 2"`
+}
+
+// ImportPostsRequest 从字典导入岗位请求
+type ImportPostsRequest struct {
+	DictType string `json:"dictType" validate:"required,max=100"`
+}
+
+// AssignUserPostsRequest 分配用户岗位请求
+type AssignUserPostsRequest struct {
+	PostIDs []uint64 `json:"postIds"`
+}
+
+// toPost 转换为岗位模型
+func (r *PostRequest) toPost(tenantID uint64) *model.Post {
+	post := &model.Post{
+		Code:      r.Code,
+		Name:      r.Name,
+		SortOrder: r.SortOrder,
+		Status:    r.Status,
+		Remark:    r.Remark,
+	}
+	post.TenantID = tenantID
+	return post
+}
+
+// CreatePost 创建岗位
+func (c *PostController) CreatePost(ctx *gin.Context) {
+	var req PostRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	post := req.toPost(tenantID)
+	if err := c.postService.CreatePost(ctx.Request.Context(), post); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, post.ToProfile())
+}
+
+// GetPosts 获取岗位列表
+func (c *PostController) GetPosts(ctx *gin.Context) {
+	var req PostListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	posts, total, err := c.postService.GetPostList(ctx.Request.Context(), tenantID, req.Page, req.PageSize, req.Keyword, req.Status)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(ctx, posts, total, req.Page, req.PageSize)
+}
+
+// GetSelectList 获取下拉岗位列表
+func (c *PostController) GetSelectList(ctx *gin.Context) {
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	posts, err := c.postService.GetEnabledPosts(ctx.Request.Context(), tenantID)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, posts)
+}
+
+// GetPost 获取岗位详情
+func (c *PostController) GetPost(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "岗位ID格式错误")
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	profile, err := c.postService.GetPost(ctx.Request.Context(), tenantID, id)
+	if err != nil {
+		response.NotFound(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, profile)
+}
+
+// UpdatePost 更新岗位
+func (c *PostController) UpdatePost(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "岗位ID格式错误")
+		return
+	}
+
+	var req PostRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	post := req.toPost(tenantID)
+	post.ID = id
+	if err := c.postService.UpdatePost(ctx.Request.Context(), post); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	profile, err := c.postService.GetPost(ctx.Request.Context(), tenantID, id)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, profile)
+}
+
+// DeletePost 删除岗位
+func (c *PostController) DeletePost(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "岗位ID格式错误")
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	if err := c.postService.DeletePost(ctx.Request.Context(), tenantID, id); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{"message": "岗位删除成功"})
+}
+
+// ImportFromDict 从字典导入岗位
+func (c *PostController) ImportFromDict(ctx *gin.Context) {
+	var req ImportPostsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	result, err := c.postService.ImportFromDict(ctx.Request.Context(), tenantID, req.DictType)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, result)
+}
+
+// GetUserPosts 获取用户岗位
+func (c *PostController) GetUserPosts(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "用户ID格式错误")
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	posts, err := c.postService.GetUserPosts(ctx.Request.Context(), tenantID, userID)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, posts)
+}
+
+// AssignUserPosts 为用户分配岗位
+func (c *PostController) AssignUserPosts(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "用户ID格式错误")
+		return
+	}
+
+	var req AssignUserPostsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	tenantID, _ := middleware.GetTenantIDFromContext(ctx)
+	if err := c.postService.AssignUserPosts(ctx.Request.Context(), tenantID, userID, req.PostIDs); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{"message": "岗位分配成功"})
+}
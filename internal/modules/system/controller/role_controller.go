@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
 	"github.com/LiteMove/light-stack/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -30,12 +31,7 @@ func (c *RoleController) CreateRole(ctx *gin.Context) {
 		return
 	}
 
-	// 默认租户ID为0（系统租户）
-	if req.TenantID == 0 {
-		req.TenantID = 0
-	}
-
-	role, err := c.roleService.Create(&req)
+	role, err := c.roleService.Create(ctx.Request.Context(), roleScope(ctx), &req)
 	if err != nil {
 		response.BadRequest(ctx, err.Error())
 		return
@@ -59,7 +55,7 @@ func (c *RoleController) UpdateRole(ctx *gin.Context) {
 		return
 	}
 
-	role, err := c.roleService.Update(uint64(roleID), &req)
+	role, err := c.roleService.Update(ctx.Request.Context(), roleScope(ctx), uint64(roleID), &req)
 	if err != nil {
 		response.BadRequest(ctx, err.Error())
 		return
@@ -77,7 +73,7 @@ func (c *RoleController) DeleteRole(ctx *gin.Context) {
 		return
 	}
 
-	err = c.roleService.Delete(uint64(roleID))
+	err = c.roleService.Delete(ctx.Request.Context(), roleScope(ctx), uint64(roleID))
 	if err != nil {
 		response.BadRequest(ctx, err.Error())
 		return
@@ -95,7 +91,7 @@ func (c *RoleController) GetRole(ctx *gin.Context) {
 		return
 	}
 
-	role, err := c.roleService.GetByID(uint64(roleID))
+	role, err := c.roleService.GetByID(ctx.Request.Context(), roleScope(ctx), uint64(roleID))
 	if err != nil {
 		response.NotFound(ctx, err.Error())
 		return
@@ -106,9 +102,7 @@ func (c *RoleController) GetRole(ctx *gin.Context) {
 
 // GetEnabledRoles 获取启用的角色列表
 func (c *RoleController) GetEnabledRoles(ctx *gin.Context) {
-
-	isSuperAdmin := ctx.GetBool("is_super_admin")
-	roles, err := c.roleService.GetEnabledRoles(isSuperAdmin)
+	roles, err := c.roleService.GetEnabledRoles(ctx.Request.Context(), roleScope(ctx))
 	if err != nil {
 		response.BadRequest(ctx, err.Error())
 		return
@@ -135,7 +129,7 @@ func (c *RoleController) GetRoles(ctx *gin.Context) {
 	statusStr := ctx.DefaultQuery("status", "0")
 	status, _ := strconv.Atoi(statusStr)
 
-	roles, total, err := c.roleService.GetList(page, pageSize, status)
+	roles, total, err := c.roleService.GetList(ctx.Request.Context(), roleScope(ctx), page, pageSize, status)
 	if err != nil {
 		response.BadRequest(ctx, "获取角色列表失败")
 		return
@@ -144,6 +138,19 @@ func (c *RoleController) GetRoles(ctx *gin.Context) {
 	response.SuccessWithPage(ctx, roles, total, page, pageSize)
 }
 
+// roleScope 根据当前请求的租户和身份获取角色操作范围
+func roleScope(ctx *gin.Context) service.RoleScope {
+	tenantID, exists := middleware.GetTenantIDFromContext(ctx)
+	if !exists {
+		tenantID = uint64(1) // 默认系统租户
+	}
+
+	return service.RoleScope{
+		TenantID:     tenantID,
+		IsSuperAdmin: ctx.GetBool("is_super_admin"),
+	}
+}
+
 // 请求结构体定义
 
 // ChangePasswordRequest 修改密码请求
@@ -1,13 +1,15 @@
 package controller
 
 import (
+	"fmt"
+	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/LiteMove/light-stack/internal/shared/utils"
 
 	"github.com/LiteMove/light-stack/internal/modules/system/model"
 	"github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
 	"github.com/LiteMove/light-stack/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -31,15 +33,18 @@ func NewTenantController(tenantService service.TenantService) *TenantController
 // CreateTenantRequest 创建租户请求
 type CreateTenantRequest struct {
 	Name      string `json:"name" validate:"required,min=1,max=100"`
+	Code      string `json:"code" validate:"omitempty,max=50"`
 	Domain    string `json:"domain" validate:"omitempty,max=100"`
 	Status    int    `json:"status" validate:"required,oneof=1 2 3 4"`
 	ExpiredAt string `json:"expiredAt" validate:"omitempty"`
 	Config    string `json:"config" validate:"omitempty"`
+	PackageID uint64 `json:"packageId"`
 }
 
 // UpdateTenantRequest 更新租户请求
 type UpdateTenantRequest struct {
 	Name      string `json:"name" validate:"required,min=1,max=100"`
+	Code      string `json:"code" validate:"omitempty,max=50"`
 	Domain    string `json:"domain" validate:"omitempty,max=100"`
 	Status    int    `json:"status" validate:"required,oneof=1 2 3 4"`
 	ExpiredAt string `json:"expiredAt" validate:"omitempty"`
@@ -59,6 +64,43 @@ type UpdateTenantStatusRequest struct {
 	Status int `json:"status" validate:"required,oneof=1 2 3 4"`
 }
 
+// ProvisionTenantRequest 开通租户请求
+type ProvisionTenantRequest struct {
+	CreateTenantRequest
+	AdminUsername     string `json:"adminUsername" validate:"omitempty,min=3,max=50"`
+	AdminNickname     string `json:"adminNickname" validate:"max=100"`
+	AdminEmail        string `json:"adminEmail" validate:"omitempty,email,max=100"`
+	TemplateTenantID  uint64 `json:"templateTenantId"`
+	LocalAccessDomain string `json:"localAccessDomain" validate:"omitempty,url,max=255"`
+}
+
+// OffboardTenantRequest 下线租户请求
+type OffboardTenantRequest struct {
+	Mode string `json:"mode" validate:"required,oneof=soft purge"`
+}
+
+// toTenant 转换为租户模型
+func (r *CreateTenantRequest) toTenant() (*model.Tenant, error) {
+	tenant := &model.Tenant{
+		Name:      r.Name,
+		Code:      r.Code,
+		Domain:    r.Domain,
+		Status:    r.Status,
+		Config:    r.Config,
+		PackageID: r.PackageID,
+	}
+
+	// 处理过期时间
+	if r.ExpiredAt != "" {
+		expiredAt, err := utils.ParseToTime(r.ExpiredAt)
+		if err != nil {
+			return nil, fmt.Errorf("无法解析过期时间: %w", err)
+		}
+		tenant.ExpiredAt = expiredAt
+	}
+	return tenant, nil
+}
+
 // CreateTenant 创建租户
 func (c *TenantController) CreateTenant(ctx *gin.Context) {
 	var req CreateTenantRequest
@@ -74,21 +116,10 @@ func (c *TenantController) CreateTenant(ctx *gin.Context) {
 	}
 
 	// 创建租户对象
-	tenant := &model.Tenant{
-		Name:   req.Name,
-		Domain: req.Domain,
-		Status: req.Status,
-		Config: req.Config,
-	}
-
-	// 处理过期时间
-	if req.ExpiredAt != "" {
-		time, err := utils.ParseToTime(req.ExpiredAt)
-		if err != nil {
-			response.BadRequest(ctx, "无法解析过期时间: "+err.Error())
-			return
-		}
-		tenant.ExpiredAt = time
+	tenant, err := req.toTenant()
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
 	}
 
 	// 调用服务创建租户
@@ -96,6 +127,7 @@ func (c *TenantController) CreateTenant(ctx *gin.Context) {
 		response.BadRequest(ctx, err.Error())
 		return
 	}
+	middleware.ClearTenantResolveCache()
 
 	response.Success(ctx, gin.H{
 		"id":     tenant.ID,
@@ -104,6 +136,90 @@ func (c *TenantController) CreateTenant(ctx *gin.Context) {
 	})
 }
 
+// ProvisionTenant 开通租户，同时创建默认配置、模板角色和首个管理员
+func (c *TenantController) ProvisionTenant(ctx *gin.Context) {
+	var req ProvisionTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	// 参数验证
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	tenant, err := req.toTenant()
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	result, err := c.tenantService.ProvisionTenant(&service.TenantProvisionRequest{
+		Tenant:            tenant,
+		AdminUsername:     req.AdminUsername,
+		AdminNickname:     req.AdminNickname,
+		AdminEmail:        req.AdminEmail,
+		TemplateTenantID:  req.TemplateTenantID,
+		LocalAccessDomain: req.LocalAccessDomain,
+	})
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+	middleware.ClearTenantResolveCache()
+
+	response.Success(ctx, result)
+}
+
+// ExportTenant 导出租户数据（JSON）
+func (c *TenantController) ExportTenant(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "租户ID格式错误")
+		return
+	}
+
+	export, err := c.tenantService.ExportTenant(id)
+	if err != nil {
+		response.NotFound(ctx, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("tenant_%d_%s.json", id, export.ExportedAt.Format("20060102150405"))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	ctx.JSON(http.StatusOK, export)
+}
+
+// OffboardTenant 下线租户，先归档导出数据，再软删除或彻底删除
+func (c *TenantController) OffboardTenant(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "租户ID格式错误")
+		return
+	}
+
+	var req OffboardTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	result, err := c.tenantService.OffboardTenant(id, req.Mode)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+	middleware.ClearTenantResolveCache()
+
+	response.Success(ctx, result)
+}
+
 // GetSelectList 获取下拉租户列表
 func (c *TenantController) GetSelectList(ctx *gin.Context) {
 	tenants, err := c.tenantService.GetSelectList()
@@ -203,6 +319,7 @@ func (c *TenantController) UpdateTenant(ctx *gin.Context) {
 
 	// 更新租户信息
 	existingTenant.Name = req.Name
+	existingTenant.Code = req.Code
 	existingTenant.Domain = req.Domain
 	existingTenant.Status = req.Status
 	existingTenant.Config = req.Config
@@ -222,6 +339,7 @@ func (c *TenantController) UpdateTenant(ctx *gin.Context) {
 		response.InternalServerError(ctx, err.Error())
 		return
 	}
+	middleware.ClearTenantResolveCache()
 
 	response.Success(ctx, gin.H{
 		"id":     existingTenant.ID,
@@ -249,6 +367,7 @@ func (c *TenantController) DeleteTenant(ctx *gin.Context) {
 		response.BadRequest(ctx, err.Error())
 		return
 	}
+	middleware.ClearTenantResolveCache()
 
 	response.Success(ctx, gin.H{
 		"message": "删除成功",
@@ -338,6 +457,10 @@ func (c *TenantController) CheckName(ctx *gin.Context) {
 // TenantConfigRequest 租户配置请求
 type TenantConfigRequest struct {
 	FileStorage model.FileStorageConfig `json:"fileStorage" validate:"required"`
+	Security    *model.SecurityConfig   `json:"security"` // 为空时保持原有安全策略
+	Mail        *model.MailConfig       `json:"mail"`     // 为空时保持原有邮件配置
+	// 为空时保持原有密码策略
+	PasswordPolicy *model.PasswordPolicyConfig `json:"passwordPolicy"`
 }
 
 // GetTenantConfig 获取租户配置
@@ -384,7 +507,10 @@ func (c *TenantController) UpdateTenantConfig(ctx *gin.Context) {
 
 	// 构建租户配置
 	config := &model.TenantConfig{
-		FileStorage: req.FileStorage,
+		FileStorage:    req.FileStorage,
+		Security:       req.Security,
+		Mail:           req.Mail,
+		PasswordPolicy: req.PasswordPolicy,
 	}
 
 	// 调用服务更新租户配置
@@ -411,66 +537,20 @@ type TenantDisplayInfo struct {
 // GetTenantByDomain 根据域名获取租户展示信息（公开接口）
 func (c *TenantController) GetTenantByDomain(ctx *gin.Context) {
 	domain := ctx.Query("domain")
-	if domain == "" {
-		// 如果没有指定域名，尝试从Host头获取
-		domain = ctx.Request.Host
-		// 去掉端口号
-		if idx := strings.Index(domain, ":"); idx != -1 {
-			domain = domain[:idx]
-		}
-	}
-
-	// 对于localhost或127.0.0.1，获取系统租户配置
-	if domain == "localhost" || domain == "127.0.0.1" || domain == "" {
-		// 获取系统租户（ID=1）的配置
-		tenant, err := c.tenantService.GetTenant(model.SystemTenantId)
-		if err != nil {
-			// 如果获取失败，返回默认配置
-			displayInfo := TenantDisplayInfo{
-				ID:          1,
-				Name:        "系统租户",
-				SystemName:  "Light Stack",
-				Logo:        "",
-				Description: "轻量级管理系统",
-				Copyright:   "© 2024 Light Stack. All rights reserved.",
-			}
-			response.Success(ctx, displayInfo)
-			return
-		}
-
-		// 解析租户配置
-		config, err := tenant.GetConfig()
-		if err != nil {
-			response.InternalServerError(ctx, "获取租户配置失败")
-			return
-		}
 
-		// 构建展示信息
-		displayInfo := TenantDisplayInfo{
-			ID:          tenant.ID,
-			Name:        tenant.Name,
-			SystemName:  config.SystemName,
-			Logo:        config.Logo,
-			Description: config.Description,
-			Copyright:   config.Copyright,
-		}
-
-		// 如果没有配置系统名称，使用租户名称
-		if displayInfo.SystemName == "" {
-			displayInfo.SystemName = tenant.Name
-		}
-
-		// 如果没有配置版权信息，使用默认值
-		if displayInfo.Copyright == "" {
-			displayInfo.Copyright = "© 2024 " + displayInfo.SystemName + ". All rights reserved."
-		}
-
-		response.Success(ctx, displayInfo)
-		return
+	// 未指定域名时使用租户中间件识别到的租户
+	tenantID := model.SystemTenantId
+	if resolvedID, ok := middleware.GetTenantIDFromContext(ctx); ok {
+		tenantID = resolvedID
 	}
 
-	// 调用服务获取租户信息
-	tenant, err := c.tenantService.GetTenantByDomain(domain)
+	var tenant *model.Tenant
+	var err error
+	if domain == "" || domain == "localhost" || domain == "127.0.0.1" {
+		tenant, err = c.tenantService.GetTenant(tenantID)
+	} else {
+		tenant, err = c.tenantService.GetTenantByDomain(domain)
+	}
 	if err != nil {
 		// 如果租户不存在，返回默认配置
 		displayInfo := TenantDisplayInfo{
@@ -485,16 +565,18 @@ func (c *TenantController) GetTenantByDomain(ctx *gin.Context) {
 		return
 	}
 
-	// 检查租户状态
-	if !tenant.IsActive() {
-		response.BadRequest(ctx, "租户已被禁用")
-		return
-	}
+	if tenant.ID != model.SystemTenantId {
+		// 检查租户状态
+		if !tenant.IsActive() {
+			response.BadRequest(ctx, "租户已被禁用")
+			return
+		}
 
-	// 检查租户是否过期
-	if tenant.IsExpired() {
-		response.BadRequest(ctx, "租户已过期")
-		return
+		// 检查租户是否过期
+		if tenant.IsExpired() {
+			response.BadRequest(ctx, "租户已过期")
+			return
+		}
 	}
 
 	// 解析租户配置
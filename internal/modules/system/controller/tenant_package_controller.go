@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// TenantPackageController 租户套餐控制器
+type TenantPackageController struct {
+	packageService service.TenantPackageService
+	validator      *validator.Validate
+}
+
+// NewTenantPackageController 创建租户套餐控制器
+func NewTenantPackageController(packageService service.TenantPackageService) *TenantPackageController {
+	return &TenantPackageController{
+		packageService: packageService,
+		validator:      validator.New(),
+	}
+}
+
+// TenantPackageRequest 创建/更新套餐请求
+type TenantPackageRequest struct {
+	Name        string   `json:"name" validate:"required,min=1,max=100"`
+	Description string   `json:"description" validate:"max=255"`
+	Status      int      `json:"status" validate:"required,oneof=1 2"`
+	SortOrder   int      `json:"sortOrder"`
+	MenuIDs     []uint64 `json:"menuIds"`
+}
+
+// TenantPackageListRequest 套餐列表请求
+type TenantPackageListRequest struct {
+	Page     int    `form:"page" validate:"min=1"`
+	PageSize int    `form:"page_size" validate:"min=1,max=100"`
+	Name     string `form:"name"`
+	Status   int    `form:"status" validate:"oneof=0 1 2"`
+}
+
+// AssignTenantPackageRequest 分配租户套餐请求
+type AssignTenantPackageRequest struct {
+	PackageID uint64 `json:"packageId"` // 0表示不限制
+}
+
+// CreatePackage 创建套餐
+func (c *TenantPackageController) CreatePackage(ctx *gin.Context) {
+	var req TenantPackageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	pkg := &model.TenantPackage{
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      req.Status,
+		SortOrder:   req.SortOrder,
+	}
+	if err := c.packageService.CreatePackage(pkg, req.MenuIDs); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	profile, err := c.packageService.GetPackage(pkg.ID)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, profile)
+}
+
+// GetPackages 获取套餐列表
+func (c *TenantPackageController) GetPackages(ctx *gin.Context) {
+	var req TenantPackageListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	packages, total, err := c.packageService.GetPackageList(req.Page, req.PageSize, req.Name, req.Status)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(ctx, packages, total, req.Page, req.PageSize)
+}
+
+// GetSelectList 获取下拉套餐列表
+func (c *TenantPackageController) GetSelectList(ctx *gin.Context) {
+	packages, err := c.packageService.GetEnabledPackages()
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, packages)
+}
+
+// GetPackage 获取套餐详情
+func (c *TenantPackageController) GetPackage(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "套餐ID格式错误")
+		return
+	}
+
+	profile, err := c.packageService.GetPackage(id)
+	if err != nil {
+		response.NotFound(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, profile)
+}
+
+// UpdatePackage 更新套餐
+func (c *TenantPackageController) UpdatePackage(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "套餐ID格式错误")
+		return
+	}
+
+	var req TenantPackageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		response.BadRequest(ctx, "参数验证失败: "+err.Error())
+		return
+	}
+
+	pkg := &model.TenantPackage{
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      req.Status,
+		SortOrder:   req.SortOrder,
+	}
+	pkg.ID = id
+	if err := c.packageService.UpdatePackage(pkg, req.MenuIDs); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	profile, err := c.packageService.GetPackage(id)
+	if err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+	response.Success(ctx, profile)
+}
+
+// DeletePackage 删除套餐
+func (c *TenantPackageController) DeletePackage(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "套餐ID格式错误")
+		return
+	}
+
+	if err := c.packageService.DeletePackage(id); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{"message": "套餐删除成功"})
+}
+
+// AssignTenantPackage 升级/降级租户套餐
+func (c *TenantPackageController) AssignTenantPackage(ctx *gin.Context) {
+	tenantID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "租户ID格式错误")
+		return
+	}
+
+	var req AssignTenantPackageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(ctx, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	if err := c.packageService.AssignTenantPackage(tenantID, req.PackageID); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{"message": "租户套餐更新成功"})
+}
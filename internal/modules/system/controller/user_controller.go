@@ -33,8 +33,9 @@ type CreateUserRequest struct {
 	Email    string `json:"email" validate:"omitempty,email,max=255"`
 	Phone    string `json:"phone" validate:"omitempty,max=20"`
 	Avatar   string `json:"avatar" validate:"omitempty,max=255"`
-	Password string `json:"password" validate:"omitempty,min=6,max=50"`
+	Password string `json:"password" validate:"omitempty,max=128"` // 为空时生成临时密码
 	Status   int    `json:"status" validate:"required,oneof=1 2"`
+	DeptID   uint64 `json:"deptId"`
 }
 
 // UpdateUserRequest 更新用户请求
@@ -45,6 +46,7 @@ type UpdateUserRequest struct {
 	Phone    string `json:"phone" validate:"omitempty,max=20"`
 	Avatar   string `json:"avatar" validate:"omitempty,max=255"`
 	Status   int    `json:"status" validate:"required,oneof=1 2"`
+	DeptID   uint64 `json:"deptId"`
 }
 
 // UserListRequest 用户列表请求
@@ -54,6 +56,7 @@ type UserListRequest struct {
 	Keyword  string `form:"keyword"`
 	Status   int    `form:"status" validate:"oneof=0 1 2"`
 	RoleID   uint64 `form:"roleId"`
+	DeptID   uint64 `form:"deptId"` // 包含下级部门
 }
 
 // UpdateUserStatusRequest 更新用户状态请求
@@ -70,7 +73,7 @@ type BatchUpdateUserStatusRequest struct {
 // ChangeUserPasswordRequest 修改用户密码请求
 type ChangeUserPasswordRequest struct {
 	OldPassword string `json:"oldPassword" validate:"required,min=6,max=50"`
-	NewPassword string `json:"newPassword" validate:"required,min=6,max=50"`
+	NewPassword string `json:"newPassword" validate:"required,max=128"`
 }
 
 // AssignUserRolesRequest 分配用户角色请求
@@ -105,6 +108,7 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 		Password: req.Password,
 		Status:   req.Status,
 		IsSystem: false,
+		DeptID:   req.DeptID,
 	}
 
 	// 处理可选字段
@@ -118,18 +122,24 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 	user.Avatar = req.Avatar
 
 	user.TenantID = tenantID
+	user.CreatedBy = middleware.GetUserIDFromContext(ctx)
 
 	// 调用服务创建用户
-	if err := c.userService.CreateUser(user); err != nil {
+	temporaryPassword, err := c.userService.CreateUser(ctx.Request.Context(), user)
+	if err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
 	}
 
-	response.Success(ctx, gin.H{
+	result := gin.H{
 		"id":       user.ID,
 		"username": user.Username,
 		"nickname": user.Nickname,
-	})
+	}
+	if temporaryPassword != "" {
+		result["temporaryPassword"] = temporaryPassword
+	}
+	response.Success(ctx, result)
 }
 
 // GetUsers 获取用户列表
@@ -154,14 +164,9 @@ func (c *UserController) GetUsers(ctx *gin.Context) {
 		return
 	}
 
-	// 从上下文获取租户ID
-	tenantID, exists := middleware.GetTenantIDFromContext(ctx)
-	if !exists {
-		tenantID = uint64(1) // 默认系统租户
-	}
-
-	// 调用服务获取用户列表
-	users, total, err := c.userService.GetUserList(tenantID, req.Page, req.PageSize, req.Keyword, req.Status, req.RoleID)
+	// 按当前用户的数据权限获取用户列表
+	filter := middleware.GetDataFilterFromContext(ctx)
+	users, total, err := c.userService.GetUserList(filter, req.Page, req.PageSize, req.Keyword, req.Status, req.RoleID, req.DeptID)
 	if err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
@@ -187,7 +192,7 @@ func (c *UserController) GetUser(ctx *gin.Context) {
 	}
 
 	// 调用服务获取用户
-	user, err := c.userService.GetUserWithRoles(id)
+	user, err := c.userService.GetUserWithRoles(ctx.Request.Context(), id)
 	if err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
@@ -219,7 +224,7 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 	}
 
 	// 获取原用户信息
-	existingUser, err := c.userService.GetUser(id)
+	existingUser, err := c.userService.GetUser(ctx.Request.Context(), id)
 	if err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
@@ -235,6 +240,7 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 	existingUser.Username = req.Username
 	existingUser.Nickname = req.Nickname
 	existingUser.Status = req.Status
+	existingUser.DeptID = req.DeptID
 
 	// 处理可选字段
 	if req.Email != "" {
@@ -251,7 +257,7 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 	existingUser.Avatar = req.Avatar
 
 	// 调用服务更新用户
-	if err := c.userService.UpdateUser(existingUser); err != nil {
+	if err := c.userService.UpdateUser(ctx.Request.Context(), existingUser); err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
 	}
@@ -274,7 +280,7 @@ func (c *UserController) DeleteUser(ctx *gin.Context) {
 	}
 
 	// 调用服务删除用户
-	if err := c.userService.DeleteUser(id); err != nil {
+	if err := c.userService.DeleteUser(ctx.Request.Context(), id); err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
 	}
@@ -307,7 +313,7 @@ func (c *UserController) UpdateUserStatus(ctx *gin.Context) {
 	}
 
 	// 调用服务更新状态
-	if err := c.userService.UpdateUserStatus(id, req.Status); err != nil {
+	if err := c.userService.UpdateUserStatus(ctx.Request.Context(), id, req.Status); err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
 	}
@@ -332,7 +338,7 @@ func (c *UserController) BatchUpdateUserStatus(ctx *gin.Context) {
 	}
 
 	// 调用服务批量更新状态
-	if err := c.userService.BatchUpdateUserStatus(req.IDs, req.Status); err != nil {
+	if err := c.userService.BatchUpdateUserStatus(ctx.Request.Context(), req.IDs, req.Status); err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
 	}
@@ -353,7 +359,7 @@ func (c *UserController) ResetPassword(ctx *gin.Context) {
 	}
 
 	// 调用服务重置密码
-	newPassword, err := c.userService.ResetPassword(id)
+	newPassword, err := c.userService.ResetPassword(ctx.Request.Context(), id)
 	if err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
@@ -365,6 +371,66 @@ func (c *UserController) ResetPassword(ctx *gin.Context) {
 	})
 }
 
+// UnlockUser 解锁用户
+func (c *UserController) UnlockUser(ctx *gin.Context) {
+	// 获取用户ID
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "用户ID格式错误")
+		return
+	}
+
+	if err := c.userService.UnlockUser(ctx.Request.Context(), id); err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{
+		"message": "用户解锁成功",
+	})
+}
+
+// ResetUserMfa 重置用户二次验证
+func (c *UserController) ResetUserMfa(ctx *gin.Context) {
+	// 获取用户ID
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "用户ID格式错误")
+		return
+	}
+
+	if err := c.userService.ResetUserMfa(ctx.Request.Context(), id); err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{
+		"message": "二次验证已重置",
+	})
+}
+
+// RevokeUserSessions 强制用户下线（吊销所有会话）
+func (c *UserController) RevokeUserSessions(ctx *gin.Context) {
+	// 获取用户ID
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(ctx, "用户ID格式错误")
+		return
+	}
+
+	if err := c.userService.RevokeUserSessions(ctx.Request.Context(), id); err != nil {
+		response.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.Success(ctx, gin.H{
+		"message": "用户已强制下线",
+	})
+}
+
 // AssignUserRoles 为用户分配角色
 func (c *UserController) AssignUserRoles(ctx *gin.Context) {
 	// 获取用户ID
@@ -393,7 +459,7 @@ func (c *UserController) AssignUserRoles(ctx *gin.Context) {
 	}
 
 	// 调用服务分配角色
-	if err := c.userService.AssignUserRoles(id, req.RoleIDs); err != nil {
+	if err := c.userService.AssignUserRoles(ctx.Request.Context(), id, req.RoleIDs, ctx.GetBool("is_super_admin")); err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
 	}
@@ -414,7 +480,7 @@ func (c *UserController) GetUserRoles(ctx *gin.Context) {
 	}
 
 	// 调用服务获取用户角色
-	roles, err := c.userService.GetUserRoles(id)
+	roles, err := c.userService.GetUserRoles(ctx.Request.Context(), id)
 	if err != nil {
 		response.Error(ctx, 500, err.Error())
 		return
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/model"
+
+	"gorm.io/gorm"
+)
+
+// Dept 部门模型，按租户隔离的组织树
+type Dept struct {
+	model.TenantBaseModel
+	ParentID  uint64 `json:"parentId" gorm:"not null;default:0;index"`
+	Name      string `json:"name" gorm:"not null;size:100" validate:"required,min=1,max=100"`
+	LeaderID  uint64 `json:"leaderId" gorm:"not null;default:0;index"` // 负责人用户ID，0表示未设置
+	Phone     string `json:"phone" gorm:"size:20" validate:"max=20"`
+	Email     string `json:"email" gorm:"size:100" validate:"omitempty,email,max=100"`
+	SortOrder int    `json:"sortOrder" gorm:"not null;default:0"`
+	Status    int    `json:"status" gorm:"not null;default:1;index" validate:"required,oneof=1 2"`
+
+	// 关联关系
+	Leader *User `json:"leader,omitempty" gorm:"foreignKey:LeaderID;-:migration"` // 未设置负责人时为0，不建外键约束
+}
+
+// TableName 指定表名
+func (Dept) TableName() string {
+	return "depts"
+}
+
+// DeptProfile 部门资料
+type DeptProfile struct {
+	ID         uint64    `json:"id"`
+	TenantID   uint64    `json:"tenantId"`
+	ParentID   uint64    `json:"parentId"`
+	Name       string    `json:"name"`
+	LeaderID   uint64    `json:"leaderId"`
+	LeaderName string    `json:"leaderName"`
+	Phone      string    `json:"phone"`
+	Email      string    `json:"email"`
+	SortOrder  int       `json:"sortOrder"`
+	Status     int       `json:"status"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// ToProfile 转换为部门资料
+func (d *Dept) ToProfile() DeptProfile {
+	profile := DeptProfile{
+		ID:        d.ID,
+		TenantID:  d.TenantID,
+		ParentID:  d.ParentID,
+		Name:      d.Name,
+		LeaderID:  d.LeaderID,
+		Phone:     d.Phone,
+		Email:     d.Email,
+		SortOrder: d.SortOrder,
+		Status:    d.Status,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+	if d.Leader != nil {
+		profile.LeaderName = d.Leader.Nickname
+	}
+	return profile
+}
+
+// DeptTreeNode 部门树节点
+type DeptTreeNode struct {
+	DeptProfile
+	Children []DeptTreeNode `json:"children,omitempty"`
+}
+
+// ToTreeNode 转换为部门树节点
+func (d *Dept) ToTreeNode() DeptTreeNode {
+	return DeptTreeNode{
+		DeptProfile: d.ToProfile(),
+		Children:    make([]DeptTreeNode, 0),
+	}
+}
+
+// BeforeCreate 创建前的钩子
+func (d *Dept) BeforeCreate(tx *gorm.DB) error {
+	if d.Status == 0 {
+		d.Status = DeptStatusEnabled // 默认启用
+	}
+	return nil
+}
+
+const (
+	DeptStatusEnabled  = 1 // 启用
+	DeptStatusDisabled = 2 // 禁用
+)
+
+// DeptSubtreeIDs 获取部门及其所有下级部门的ID
+func DeptSubtreeIDs(depts []*Dept, deptID uint64) []uint64 {
+	children := make(map[uint64][]uint64, len(depts))
+	for _, dept := range depts {
+		children[dept.ParentID] = append(children[dept.ParentID], dept.ID)
+	}
+
+	ids := []uint64{deptID}
+	visited := map[uint64]bool{deptID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !visited[child] {
+				visited[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// PasswordHistory 用户历史密码模型，用于限制重复使用近期密码
+type PasswordHistory struct {
+	ID        uint64    `json:"id" gorm:"primarykey"`
+	UserID    uint64    `json:"userId" gorm:"not null;index:idx_user_id"`
+	Password  string    `json:"-" gorm:"not null;size:255"` // 密码哈希
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
+
+// MaxPasswordHistory 每个用户保留的历史密码条数上限
+const MaxPasswordHistory = 24
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/model"
+
+	"gorm.io/gorm"
+)
+
+// Post 岗位模型，按租户隔离
+type Post struct {
+	model.TenantBaseModel
+	Code      string `json:"code" gorm:"not null;size:64;index" validate:"required,min=1,max=64"`
+	Name      string `json:"name" gorm:"not null;size:100" validate:"required,min=1,max=100"`
+	SortOrder int    `json:"sortOrder" gorm:"not null;default:0"`
+	Status    int    `json:"status" gorm:"not null;default:1;index" validate:"required,oneof=1 2"`
+	Remark    string `json:"remark" gorm:"size:255" validate:"max=255"`
+}
+
+// TableName 指定表名
+func (Post) TableName() string {
+	return "posts"
+}
+
+// PostProfile 岗位资料
+type PostProfile struct {
+	ID        uint64    `json:"id"`
+	TenantID  uint64    `json:"tenantId"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	SortOrder int       `json:"sortOrder"`
+	Status    int       `json:"status"`
+	Remark    string    `json:"remark"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ToProfile 转换为岗位资料
+func (p *Post) ToProfile() PostProfile {
+	return PostProfile{
+		ID:        p.ID,
+		TenantID:  p.TenantID,
+		Code:      p.Code,
+		Name:      p.Name,
+		SortOrder: p.SortOrder,
+		Status:    p.Status,
+		Remark:    p.Remark,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+// BeforeCreate 创建前的钩子
+func (p *Post) BeforeCreate(tx *gorm.DB) error {
+	if p.Status == 0 {
+		p.Status = PostStatusEnabled // 默认启用
+	}
+	return nil
+}
+
+const (
+	PostStatusEnabled  = 1 // 启用
+	PostStatusDisabled = 2 // 禁用
+)
+
+// UserPost 用户岗位关联模型
+type UserPost struct {
+	ID        uint64    `json:"id" gorm:"primarykey"`
+	UserID    uint64    `json:"userId" gorm:"not null;uniqueIndex:uk_user_post;index:idx_user_id" validate:"required"`
+	PostID    uint64    `json:"postId" gorm:"not null;uniqueIndex:uk_user_post;index:idx_post_id" validate:"required"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (UserPost) TableName() string {
+	return "user_posts"
+}
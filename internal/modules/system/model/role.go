@@ -8,14 +8,17 @@ import (
 )
 
 // Role 角色模型
+// 系统角色（IsSystem）的租户ID为0，所有租户共享且只读；其余角色归属于创建它的租户
 type Role struct {
-	model.BaseModel
+	model.TenantBaseModel
 	Name        string `json:"name" gorm:"not null;size:100" validate:"required,min=1,max=100"`
-	Code        string `json:"code" gorm:"not null;size:50;uniqueIndex" validate:"required,min=1,max=50"`
+	Code        string `json:"code" gorm:"not null;size:50" validate:"required,min=1,max=50"` // 租户内唯一，联合唯一索引uk_tenant_code由迁移创建
 	Description string `json:"description" gorm:"size:255" validate:"max=255"`
 	Status      int    `json:"status" gorm:"not null;default:1" validate:"required,oneof=1 2"`
 	IsSystem    bool   `json:"isSystem" gorm:"not null;default:false"`
 	SortOrder   int    `json:"sortOrder" gorm:"not null;default:0"`
+	// 数据权限范围：1-全部 2-本租户 3-本部门 4-仅本人，用户拥有多个角色时取最大范围
+	DataScope model.DataScope `json:"dataScope" gorm:"not null;default:2"`
 
 	// 关联关系
 	Users []User `json:"users,omitempty" gorm:"many2many:user_roles;"`
@@ -29,27 +32,31 @@ func (Role) TableName() string {
 
 // RoleProfile 角色资料（简化版本）
 type RoleProfile struct {
-	ID          uint64    `json:"id"`
-	Name        string    `json:"name"`
-	Code        string    `json:"code"`
-	Description string    `json:"description"`
-	Status      int       `json:"status"`
-	IsSystem    bool      `json:"isSystem"`
-	SortOrder   int       `json:"sortOrder"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          uint64          `json:"id"`
+	TenantID    uint64          `json:"tenantId"`
+	Name        string          `json:"name"`
+	Code        string          `json:"code"`
+	Description string          `json:"description"`
+	Status      int             `json:"status"`
+	IsSystem    bool            `json:"isSystem"`
+	SortOrder   int             `json:"sortOrder"`
+	DataScope   model.DataScope `json:"dataScope"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
 }
 
 // ToProfile 转换为角色资料
 func (r *Role) ToProfile() RoleProfile {
 	return RoleProfile{
 		ID:          r.ID,
+		TenantID:    r.TenantID,
 		Name:        r.Name,
 		Code:        r.Code,
 		Description: r.Description,
 		Status:      r.Status,
 		IsSystem:    r.IsSystem,
 		SortOrder:   r.SortOrder,
+		DataScope:   r.DataScope,
 		CreatedAt:   r.CreatedAt,
 		UpdatedAt:   r.UpdatedAt,
 	}
@@ -60,11 +67,32 @@ func (r *Role) BeforeCreate(tx *gorm.DB) error {
 	if r.Status == 0 {
 		r.Status = 1 // 默认启用
 	}
+	if r.DataScope == 0 {
+		r.DataScope = model.DataScopeTenant // 默认本租户数据
+	}
 	return nil
 }
 
 var SuperAdminId uint64 = 1 // 超级管理员角色ID
 
+// SystemRoleTenantID 系统角色的租户ID
+const SystemRoleTenantID uint64 = 0
+
+// VisibleTo 角色是否对指定租户可见（系统角色或本租户角色）
+func (r *Role) VisibleTo(tenantID uint64) bool {
+	return r.TenantID == SystemRoleTenantID || r.TenantID == tenantID
+}
+
+// Privileged 是否为仅超级管理员可分配的角色（超级管理员角色和系统共享角色）
+func (r *Role) Privileged() bool {
+	return r.ID == SuperAdminId || r.IsSystem || r.TenantID == SystemRoleTenantID
+}
+
+// SharedAcrossTenants 系统角色由所有租户共享，租户插件按(tenant_id = 0 OR tenant_id = 当前租户)过滤
+func (Role) SharedAcrossTenants() bool {
+	return true
+}
+
 const (
 	RoleStatusEnabled  = 1 // 启用
 	RoleStatusDisabled = 2 // 禁用
@@ -4,18 +4,27 @@ import "github.com/LiteMove/light-stack/internal/shared/model"
 
 import (
 	"encoding/json"
-	"gorm.io/gorm"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/LiteMove/light-stack/internal/shared/utils"
+	"gorm.io/gorm"
 )
 
 // Tenant 租户模型
 type Tenant struct {
 	model.BaseModel
 	Name      string     `json:"name" gorm:"not null;size:100" validate:"required,min=1,max=100"`
+	Code      string     `json:"code" gorm:"size:50;index"` // 租户编码，用于泛域名和路径前缀解析租户
 	Domain    string     `json:"domain" gorm:"size:100;uniqueIndex:uk_domain" validate:"max=100"`
 	Status    int        `json:"status" gorm:"not null;default:1;index" validate:"required,oneof=1 2 3 4"`
 	ExpiredAt *time.Time `json:"expiredAt" gorm:"index"`
 	Config    string     `json:"config" gorm:"type:json"`
+	PackageID uint64     `json:"packageId" gorm:"not null;default:0;index"` // 租户套餐ID，0表示不限制
 
 	// 关联关系
 	Users []User `json:"users,omitempty" gorm:"foreignKey:TenantID"`
@@ -30,10 +39,12 @@ func (Tenant) TableName() string {
 type TenantProfile struct {
 	ID        uint64     `json:"id"`
 	Name      string     `json:"name"`
+	Code      string     `json:"code"`
 	Domain    string     `json:"domain"`
 	Status    int        `json:"status"`
 	ExpiredAt *time.Time `json:"expiredAt"`
 	Config    string     `json:"config"`
+	PackageID uint64     `json:"packageId"`
 	CreatedAt time.Time  `json:"createdAt"`
 	UpdatedAt time.Time  `json:"updatedAt"`
 }
@@ -43,10 +54,12 @@ func (t *Tenant) ToProfile() TenantProfile {
 	return TenantProfile{
 		ID:        t.ID,
 		Name:      t.Name,
+		Code:      t.Code,
 		Domain:    t.Domain,
 		Status:    t.Status,
 		ExpiredAt: t.ExpiredAt,
 		Config:    t.Config,
+		PackageID: t.PackageID,
 		CreatedAt: t.CreatedAt,
 		UpdatedAt: t.UpdatedAt,
 	}
@@ -86,27 +99,167 @@ const (
 
 // FileStorageConfig 文件存储配置
 type FileStorageConfig struct {
-	Type          string   `json:"type"`          // local/oss
-	DefaultPublic bool     `json:"defaultPublic"` // 默认是否公开
-	MaxFileSize   int64    `json:"maxFileSize"`   // 最大文件大小(字节)
-	AllowedTypes  []string `json:"allowedTypes"`  // 允许的文件类型
+	Type               string   `json:"type"`               // local/oss
+	DefaultPublic      bool     `json:"defaultPublic"`      // 默认是否公开
+	MaxFileSize        int64    `json:"maxFileSize"`        // 最大文件大小(字节)
+	MaxChunkedFileSize int64    `json:"maxChunkedFileSize"` // 分片上传最大文件大小(字节)
+	AllowedTypes       []string `json:"allowedTypes"`       // 允许的文件类型
 
 	// 本地存储配置
 	LocalAccessDomain string `json:"localAccessDomain,omitempty"` // 本地存储文件访问域名 (如: https://files.example.com)
 
 	// OSS配置 - 使用自定义域名直接访问，不需要额外的域名配置
-	OSSProvider     string `json:"ossProvider,omitempty"`     // aliyun/tencent/aws/qiniu/upyun
+	OSSProvider     string `json:"ossProvider,omitempty"`     // aliyun/tencent/aws/minio/qiniu/upyun
 	OSSEndpoint     string `json:"ossEndpoint,omitempty"`     // OSS服务端点
 	OSSRegion       string `json:"ossRegion,omitempty"`       // AWS S3等需要
 	OSSBucket       string `json:"ossBucket,omitempty"`       // OSS存储桶名称
 	OSSAccessKey    string `json:"ossAccessKey,omitempty"`    // OSS访问密钥
 	OSSSecretKey    string `json:"ossSecretKey,omitempty"`    // OSS访问密钥
 	OSSCustomDomain string `json:"ossCustomDomain,omitempty"` // OSS自定义域名，直接用于文件访问
+	OSSPathStyle    bool   `json:"ossPathStyle,omitempty"`    // S3协议使用路径风格访问（endpoint/bucket/key），MinIO默认开启
+	OSSDisableACL   bool   `json:"ossDisableAcl,omitempty"`   // 存储桶不支持对象ACL时关闭，公开访问由存储桶策略控制
+}
+
+// SecurityConfig 安全策略配置
+type SecurityConfig struct {
+	LoginMaxFailures    int `json:"loginMaxFailures"`    // 失败窗口内允许的最大登录失败次数
+	LoginFailureWindow  int `json:"loginFailureWindow"`  // 登录失败计数窗口（分钟）
+	LoginLockMinutes    int `json:"loginLockMinutes"`    // 首次锁定时长（分钟），重复锁定时翻倍
+	LoginMaxLockMinutes int `json:"loginMaxLockMinutes"` // 最长锁定时长（分钟）
+
+	RequireAdminMfa bool `json:"requireAdminMfa"` // 管理员角色是否必须启用二次验证
+}
+
+// 安全策略默认值
+const (
+	DefaultLoginMaxFailures    = 5
+	DefaultLoginFailureWindow  = 15
+	DefaultLoginLockMinutes    = 15
+	DefaultLoginMaxLockMinutes = 24 * 60
+)
+
+// Validate 验证安全策略配置
+func (c *SecurityConfig) Validate() error {
+	if c.LoginMaxFailures < 0 || c.LoginFailureWindow < 0 || c.LoginLockMinutes < 0 || c.LoginMaxLockMinutes < 0 {
+		return errors.New("安全策略配置不能为负数")
+	}
+	if c.LoginMaxLockMinutes > 0 && c.LoginLockMinutes > c.LoginMaxLockMinutes {
+		return errors.New("首次锁定时长不能大于最长锁定时长")
+	}
+	return nil
+}
+
+// PasswordPolicyConfig 密码策略配置
+type PasswordPolicyConfig struct {
+	MinLength        int      `json:"minLength"`        // 最小长度
+	RequireUppercase bool     `json:"requireUppercase"` // 必须包含大写字母
+	RequireLowercase bool     `json:"requireLowercase"` // 必须包含小写字母
+	RequireDigit     bool     `json:"requireDigit"`     // 必须包含数字
+	RequireSpecial   bool     `json:"requireSpecial"`   // 必须包含特殊字符
+	HistoryCount     int      `json:"historyCount"`     // 不允许与最近N次使用过的密码相同，0表示不限制
+	MaxAgeDays       int      `json:"maxAgeDays"`       // 密码有效期（天），过期后登录时必须修改，0表示永不过期
+	DenyList         []string `json:"denyList"`         // 禁用密码列表（不区分大小写），在内置弱密码列表之外追加
+}
+
+// 密码策略默认值
+const (
+	DefaultPasswordMinLength = 6
+	MaxPasswordLength        = 128
+)
+
+// Validate 验证密码策略配置
+func (c *PasswordPolicyConfig) Validate() error {
+	if c.MinLength < 0 || c.HistoryCount < 0 || c.MaxAgeDays < 0 {
+		return errors.New("密码策略配置不能为负数")
+	}
+	if c.MinLength > MaxPasswordLength {
+		return fmt.Errorf("密码最小长度不能超过%d", MaxPasswordLength)
+	}
+	if c.HistoryCount > MaxPasswordHistory {
+		return fmt.Errorf("历史密码限制次数不能超过%d", MaxPasswordHistory)
+	}
+	return nil
+}
+
+// CheckPassword 检查密码是否满足长度、字符类型和禁用列表要求
+func (c *PasswordPolicyConfig) CheckPassword(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < c.MinLength {
+		return fmt.Errorf("密码长度不能少于%d位", c.MinLength)
+	}
+	if length > MaxPasswordLength {
+		return fmt.Errorf("密码长度不能超过%d位", MaxPasswordLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char) || unicode.IsSpace(char):
+			hasSpecial = true
+		}
+	}
+
+	var requirements []string
+	if c.RequireUppercase && !hasUpper {
+		requirements = append(requirements, "大写字母")
+	}
+	if c.RequireLowercase && !hasLower {
+		requirements = append(requirements, "小写字母")
+	}
+	if c.RequireDigit && !hasDigit {
+		requirements = append(requirements, "数字")
+	}
+	if c.RequireSpecial && !hasSpecial {
+		requirements = append(requirements, "特殊字符")
+	}
+	if len(requirements) > 0 {
+		return fmt.Errorf("密码必须包含%s", strings.Join(requirements, "、"))
+	}
+
+	if utils.IsCommonPassword(password) {
+		return errors.New("密码过于常见，请更换")
+	}
+	for _, denied := range c.DenyList {
+		if denied != "" && strings.EqualFold(password, denied) {
+			return errors.New("该密码已被禁止使用，请更换")
+		}
+	}
+
+	return nil
+}
+
+// IsExpired 检查密码是否已超过有效期
+func (c *PasswordPolicyConfig) IsExpired(changedAt *time.Time) bool {
+	if c.MaxAgeDays <= 0 || changedAt == nil {
+		return false
+	}
+	return utils.IsPasswordExpired(changedAt.Unix(), c.MaxAgeDays)
+}
+
+// MailConfig 租户邮件发件配置
+type MailConfig struct {
+	SMTPHost     string `json:"smtpHost"`     // SMTP服务器，为空时使用系统默认发件设置
+	SMTPPort     int    `json:"smtpPort"`     // SMTP端口
+	SMTPUsername string `json:"smtpUsername"` // SMTP用户名
+	SMTPPassword string `json:"smtpPassword"` // SMTP密码
+	SMTPUseTLS   bool   `json:"smtpUseTls"`   // 是否使用隐式TLS
+	FromAddress  string `json:"fromAddress"`  // 发件地址
+	FromName     string `json:"fromName"`     // 发件人名称
 }
 
 // TenantConfig 租户配置结构
 type TenantConfig struct {
 	FileStorage FileStorageConfig `json:"fileStorage"`
+	Security    *SecurityConfig   `json:"security,omitempty"` // 安全策略，为空时使用默认值
+	Mail        *MailConfig       `json:"mail,omitempty"`     // 邮件发件配置，为空时使用系统默认
+	// 密码策略，为空时使用默认值
+	PasswordPolicy *PasswordPolicyConfig `json:"passwordPolicy,omitempty"`
 	// 系统基本信息
 	SystemName  string `json:"systemName"`  // 系统名称
 	Logo        string `json:"logo"`        // 系统Logo URL
@@ -146,21 +299,90 @@ func (t *Tenant) GetFileStorageConfig() (*FileStorageConfig, error) {
 		return nil, err
 	}
 
-	// 应用默认值
-	if config.FileStorage.Type == "" {
-		config.FileStorage.Type = "local"
+	// 本地存储的访问域名不设置默认值，未配置时需要管理员在租户配置中设置
+	config.FileStorage.ApplyDefaults()
+
+	return &config.FileStorage, nil
+}
+
+// ApplyDefaults 为未配置的文件存储项设置默认值
+func (c *FileStorageConfig) ApplyDefaults() {
+	if c.Type == "" {
+		c.Type = "local"
 	}
-	if config.FileStorage.MaxFileSize == 0 {
-		config.FileStorage.MaxFileSize = 50 << 20 // 50MB
+	if c.MaxFileSize == 0 {
+		c.MaxFileSize = 50 << 20 // 50MB
 	}
-	if len(config.FileStorage.AllowedTypes) == 0 {
-		config.FileStorage.AllowedTypes = []string{".jpg", ".jpeg", ".png", ".gif", ".pdf", ".doc", ".docx", ".xls", ".xlsx", ".txt"}
+	if c.MaxChunkedFileSize == 0 {
+		c.MaxChunkedFileSize = 5 << 30 // 5GB
 	}
-	// 为本地存储设置默认访问域名（必填）
-	if config.FileStorage.Type == "local" && config.FileStorage.LocalAccessDomain == "" {
-		// 如果未配置，需要管理员在租户配置中设置域名
-		// 这里不设置默认值，强制要求租户配置域名
+	if len(c.AllowedTypes) == 0 {
+		c.AllowedTypes = []string{".jpg", ".jpeg", ".png", ".gif", ".pdf", ".doc", ".docx", ".xls", ".xlsx", ".txt"}
 	}
+}
 
-	return &config.FileStorage, nil
+// GetSecurityConfig 获取安全策略配置（包含默认值）
+func (t *Tenant) GetSecurityConfig() (*SecurityConfig, error) {
+	config, err := t.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	security := SecurityConfig{}
+	if config.Security != nil {
+		security = *config.Security
+	}
+	security.applyDefaults()
+
+	return &security, nil
+}
+
+// DefaultSecurityConfig 获取默认安全策略配置
+func DefaultSecurityConfig() *SecurityConfig {
+	security := &SecurityConfig{}
+	security.applyDefaults()
+	return security
+}
+
+// applyDefaults 为未配置的安全策略项填充默认值
+func (c *SecurityConfig) applyDefaults() {
+	if c.LoginMaxFailures == 0 {
+		c.LoginMaxFailures = DefaultLoginMaxFailures
+	}
+	if c.LoginFailureWindow == 0 {
+		c.LoginFailureWindow = DefaultLoginFailureWindow
+	}
+	if c.LoginLockMinutes == 0 {
+		c.LoginLockMinutes = DefaultLoginLockMinutes
+	}
+	if c.LoginMaxLockMinutes == 0 {
+		c.LoginMaxLockMinutes = DefaultLoginMaxLockMinutes
+	}
+}
+
+// GetPasswordPolicyConfig 获取密码策略配置（包含默认值）
+func (t *Tenant) GetPasswordPolicyConfig() (*PasswordPolicyConfig, error) {
+	config, err := t.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.PasswordPolicy == nil {
+		return DefaultPasswordPolicyConfig(), nil
+	}
+
+	policy := *config.PasswordPolicy
+	if policy.MinLength == 0 {
+		policy.MinLength = DefaultPasswordMinLength
+	}
+	return &policy, nil
+}
+
+// DefaultPasswordPolicyConfig 获取默认密码策略配置，与未配置时的密码强度要求一致
+func DefaultPasswordPolicyConfig() *PasswordPolicyConfig {
+	return &PasswordPolicyConfig{
+		MinLength:        DefaultPasswordMinLength,
+		RequireLowercase: true,
+		RequireDigit:     true,
+	}
 }
@@ -0,0 +1,78 @@
+package model
+
+import "github.com/LiteMove/light-stack/internal/shared/model"
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// TenantPackage 租户套餐模型，限定租户可使用的菜单和权限
+type TenantPackage struct {
+	model.BaseModel
+	Name        string `json:"name" gorm:"not null;size:100;uniqueIndex:uk_name" validate:"required,min=1,max=100"`
+	Description string `json:"description" gorm:"size:255" validate:"max=255"`
+	Status      int    `json:"status" gorm:"not null;default:1;index" validate:"required,oneof=1 2"`
+	SortOrder   int    `json:"sortOrder" gorm:"not null;default:0"`
+}
+
+// TableName 指定表名
+func (TenantPackage) TableName() string {
+	return "tenant_packages"
+}
+
+// TenantPackageMenu 租户套餐菜单关联模型
+type TenantPackageMenu struct {
+	ID        uint64    `json:"id" gorm:"primarykey"`
+	PackageID uint64    `json:"packageId" gorm:"not null;uniqueIndex:uk_package_menu;index:idx_package_id"`
+	MenuID    uint64    `json:"menuId" gorm:"not null;uniqueIndex:uk_package_menu;index:idx_menu_id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (TenantPackageMenu) TableName() string {
+	return "tenant_package_menus"
+}
+
+// TenantPackageProfile 租户套餐资料
+type TenantPackageProfile struct {
+	ID          uint64    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Status      int       `json:"status"`
+	SortOrder   int       `json:"sortOrder"`
+	MenuIDs     []uint64  `json:"menuIds"`
+	TenantCount int64     `json:"tenantCount"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// ToProfile 转换为租户套餐资料
+func (p *TenantPackage) ToProfile() TenantPackageProfile {
+	return TenantPackageProfile{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Status:      p.Status,
+		SortOrder:   p.SortOrder,
+		MenuIDs:     []uint64{},
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+// BeforeCreate 创建前的钩子
+func (p *TenantPackage) BeforeCreate(tx *gorm.DB) error {
+	if p.Status == 0 {
+		p.Status = 1 // 默认启用
+	}
+	return nil
+}
+
+const (
+	TenantPackageStatusEnabled  = 1 // 启用
+	TenantPackageStatusDisabled = 2 // 禁用
+
+	// NoTenantPackage 未分配套餐，租户可使用全部菜单（系统租户及历史租户）
+	NoTenantPackage uint64 = 0
+)
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/model"
+)
+
+// TenantProvision 租户开通数据，在同一事务中写入租户、默认角色和首个管理员
+type TenantProvision struct {
+	Tenant       *Tenant
+	Roles        []ProvisionRole
+	Admin        *User
+	AdminRoleIDs []uint64 // 管理员需要分配的共享系统角色
+}
+
+// ProvisionRole 租户开通时从模板复制的角色及其菜单权限
+type ProvisionRole struct {
+	Role    *Role
+	MenuIDs []uint64
+}
+
+// TenantExport 租户数据导出，用于租户下线前归档
+type TenantExport struct {
+	ExportedAt         time.Time                `json:"exportedAt"`
+	Tenant             *Tenant                  `json:"tenant"`
+	Users              []User                   `json:"users"`
+	Roles              []Role                   `json:"roles"`
+	RoleMenus          []RoleMenus              `json:"roleMenus"`
+	UserRoles          []UserRole               `json:"userRoles"`
+	Depts              []Dept                   `json:"depts"`
+	Posts              []Post                   `json:"posts"`
+	UserPosts          []UserPost               `json:"userPosts"`
+	Files              []map[string]interface{} `json:"files"`              // 文件记录，文件模块依赖本包，按表导出
+	UploadSessions     []map[string]interface{} `json:"uploadSessions"`     // 分片上传会话，按表导出
+	UploadSessionParts []map[string]interface{} `json:"uploadSessionParts"` // 分片上传已接收的分片，按表导出
+	OperationLogs      []model.OperationLog     `json:"operationLogs"`
+	LoginLogs          []model.LoginLog         `json:"loginLogs"`
+}
+
+// 租户下线方式
+const (
+	TenantOffboardSoftDelete = "soft"  // 禁用并软删除租户及其用户，数据保留可恢复
+	TenantOffboardPurge      = "purge" // 彻底删除租户及其全部数据
+)
@@ -3,6 +3,7 @@ package model
 import "github.com/LiteMove/light-stack/internal/shared/model"
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -23,10 +24,26 @@ type User struct {
 	LastLoginIP   string     `json:"lastLoginIp" gorm:"size:45"`
 	LoginFailures int        `json:"loginFailures" gorm:"not null;default:0"`
 	LockedUntil   *time.Time `json:"lockedUntil"`
+	LockCount     int        `json:"lockCount" gorm:"not null;default:0"`
+	LastFailedAt  *time.Time `json:"lastFailedAt"`
+
+	// 二次验证（TOTP）
+	MfaEnabled       bool   `json:"mfaEnabled" gorm:"not null;default:false"`
+	MfaSecret        string `json:"-" gorm:"size:64"`
+	MfaRecoveryCodes string `json:"-" gorm:"type:text"` // 恢复码哈希列表（JSON数组）
+
+	// 密码策略
+	PasswordChangedAt  *time.Time `json:"passwordChangedAt"`
+	MustChangePassword bool       `json:"mustChangePassword" gorm:"not null;default:false"` // 下次登录必须修改密码
+
+	CreatedBy uint64 `json:"createdBy" gorm:"not null;default:0;index"` // 创建人ID，用于"仅本人"数据权限
+	DeptID    uint64 `json:"deptId" gorm:"not null;default:0;index"`    // 所属部门ID，0表示未分配
 
 	// 关联关系
 	Roles  []Role  `json:"roles,omitempty" gorm:"many2many:user_roles;"`
+	Posts  []Post  `json:"posts,omitempty" gorm:"many2many:user_posts;"`
 	Tenant *Tenant `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	Dept   *Dept   `json:"dept,omitempty" gorm:"foreignKey:DeptID;-:migration"` // 未分配部门时为0，不建外键约束
 }
 
 // TableName 指定表名
@@ -47,6 +64,10 @@ type UserProfile struct {
 	IsSystem    bool           `json:"isSystem"`
 	LastLoginAt *time.Time     `json:"lastLoginAt"`
 	LastLoginIP string         `json:"lastLoginIp"`
+	MfaEnabled  bool           `json:"mfaEnabled"`
+	DeptID      uint64         `json:"deptId"`
+	DeptName    string         `json:"deptName"`
+	PostNames   []string       `json:"postNames,omitempty"`
 	Roles       []RoleProfile  `json:"roles,omitempty"`
 	RoleCodes   []string       `json:"roleCodes,omitempty"`
 	Permissions []string       `json:"permissions,omitempty"`
@@ -69,9 +90,17 @@ func (u *User) ToProfile() UserProfile {
 		IsSystem:    u.IsSystem,
 		LastLoginAt: u.LastLoginAt,
 		LastLoginIP: u.LastLoginIP,
+		MfaEnabled:  u.MfaEnabled,
+		DeptID:      u.DeptID,
 		CreatedAt:   u.CreatedAt,
 		UpdatedAt:   u.UpdatedAt,
 	}
+	if u.Dept != nil {
+		profile.DeptName = u.Dept.Name
+	}
+	for _, post := range u.Posts {
+		profile.PostNames = append(profile.PostNames, post.Name)
+	}
 
 	// 转换角色信息
 	if len(u.Roles) > 0 {
@@ -134,6 +163,19 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// GetRecoveryCodeHashes 获取剩余恢复码哈希列表
+func (u *User) GetRecoveryCodeHashes() []string {
+	if u.MfaRecoveryCodes == "" {
+		return []string{}
+	}
+
+	var hashes []string
+	if err := json.Unmarshal([]byte(u.MfaRecoveryCodes), &hashes); err != nil {
+		return []string{}
+	}
+	return hashes
+}
+
 // IsSuperAdmin 检查用户是否为超级管理员
 func (u *User) IsSuperAdmin() bool {
 	return u.HasRole("super_admin")
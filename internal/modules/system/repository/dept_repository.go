@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+
+	"gorm.io/gorm"
+)
+
+// DeptRepository 部门数据访问接口
+type DeptRepository interface {
+	// 绑定请求上下文，按上下文中的租户自动隔离数据
+	WithContext(ctx context.Context) DeptRepository
+	// 创建部门
+	Create(dept *model.Dept) error
+	// 根据ID获取租户内的部门
+	GetByID(tenantID, id uint64) (*model.Dept, error)
+	// 更新部门
+	Update(dept *model.Dept) error
+	// 删除部门
+	Delete(id uint64) error
+	// 获取租户内的所有部门，status为0表示不限状态
+	GetAll(tenantID uint64, status int) ([]*model.Dept, error)
+	// 检查同级部门名称是否存在
+	NameExists(tenantID, parentID uint64, name string, excludeID uint64) (bool, error)
+	// 检查是否有下级部门
+	HasChildren(id uint64) (bool, error)
+	// 获取部门下的用户数量
+	GetUserCount(deptID uint64) (int64, error)
+	// 获取用户所在部门及其下级部门的ID，用户未分配部门时返回空
+	GetUserDeptSubtreeIDs(userID uint64) ([]uint64, error)
+}
+
+// deptRepository 部门数据访问实现
+type deptRepository struct {
+	db *gorm.DB
+}
+
+// NewDeptRepository 创建部门数据访问实例
+func NewDeptRepository(db *gorm.DB) DeptRepository {
+	return &deptRepository{
+		db: db,
+	}
+}
+
+// WithContext 绑定请求上下文
+func (r *deptRepository) WithContext(ctx context.Context) DeptRepository {
+	return &deptRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// Create 创建部门
+func (r *deptRepository) Create(dept *model.Dept) error {
+	return r.db.Create(dept).Error
+}
+
+// GetByID 根据ID获取租户内的部门
+func (r *deptRepository) GetByID(tenantID, id uint64) (*model.Dept, error) {
+	var dept model.Dept
+	err := r.db.Preload("Leader").Where("tenant_id = ?", tenantID).First(&dept, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("dept not found")
+		}
+		return nil, err
+	}
+	return &dept, nil
+}
+
+// Update 更新部门
+func (r *deptRepository) Update(dept *model.Dept) error {
+	return r.db.Omit("Leader").Save(dept).Error
+}
+
+// Delete 删除部门
+func (r *deptRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.Dept{}, id).Error
+}
+
+// GetAll 获取租户内的所有部门
+func (r *deptRepository) GetAll(tenantID uint64, status int) ([]*model.Dept, error) {
+	var depts []*model.Dept
+	query := r.db.Preload("Leader").Where("tenant_id = ?", tenantID)
+	if status > 0 {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("sort_order ASC, id ASC").Find(&depts).Error
+	return depts, err
+}
+
+// NameExists 检查同级部门名称是否存在
+func (r *deptRepository) NameExists(tenantID, parentID uint64, name string, excludeID uint64) (bool, error) {
+	var count int64
+	query := r.db.Model(&model.Dept{}).
+		Where("tenant_id = ? AND parent_id = ? AND name = ?", tenantID, parentID, name)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// HasChildren 检查是否有下级部门
+func (r *deptRepository) HasChildren(id uint64) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Dept{}).Where("parent_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// GetUserCount 获取部门下的用户数量
+func (r *deptRepository) GetUserCount(deptID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.User{}).Where("dept_id = ?", deptID).Count(&count).Error
+	return count, err
+}
+
+// GetUserDeptSubtreeIDs 获取用户所在部门及其下级部门的ID
+func (r *deptRepository) GetUserDeptSubtreeIDs(userID uint64) ([]uint64, error) {
+	var user model.User
+	if err := r.db.Select("id", "tenant_id", "dept_id").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if user.DeptID == 0 {
+		return nil, nil
+	}
+
+	var depts []*model.Dept
+	if err := r.db.Select("id", "parent_id").Where("tenant_id = ?", user.TenantID).Find(&depts).Error; err != nil {
+		return nil, err
+	}
+	return model.DeptSubtreeIDs(depts, user.DeptID), nil
+}
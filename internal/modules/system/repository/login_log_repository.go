@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/model"
+	"gorm.io/gorm"
+)
+
+// LoginLogQuery 登录日志查询条件
+type LoginLogQuery struct {
+	TenantID  uint64
+	Username  string
+	IP        string
+	Status    int
+	StartTime *time.Time
+	EndTime   *time.Time
+}
+
+// LoginLogRepository 登录日志数据访问接口
+type LoginLogRepository interface {
+	// 创建登录日志
+	Create(log *model.LoginLog) error
+	// 获取登录日志列表（分页）
+	GetList(query *LoginLogQuery, page, pageSize int) ([]*model.LoginLog, int64, error)
+	// 获取用户最近的登录记录
+	GetRecentByUser(userID uint64, limit int) ([]*model.LoginLog, error)
+}
+
+// loginLogRepository 登录日志数据访问实现
+type loginLogRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginLogRepository 创建登录日志数据访问实例
+func NewLoginLogRepository(db *gorm.DB) LoginLogRepository {
+	return &loginLogRepository{
+		db: db,
+	}
+}
+
+// Create 创建登录日志
+func (r *loginLogRepository) Create(log *model.LoginLog) error {
+	return r.db.Create(log).Error
+}
+
+// GetList 获取登录日志列表（分页）
+func (r *loginLogRepository) GetList(query *LoginLogQuery, page, pageSize int) ([]*model.LoginLog, int64, error) {
+	var logs []*model.LoginLog
+	var total int64
+
+	db := r.db.Model(&model.LoginLog{}).Where("tenant_id = ?", query.TenantID)
+
+	if query.Username != "" {
+		db = db.Where("username LIKE ?", "%"+query.Username+"%")
+	}
+	if query.IP != "" {
+		db = db.Where("ip LIKE ?", query.IP+"%")
+	}
+	if query.Status > 0 {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.StartTime != nil {
+		db = db.Where("login_time >= ?", query.StartTime)
+	}
+	if query.EndTime != nil {
+		db = db.Where("login_time <= ?", query.EndTime)
+	}
+
+	// 获取总数
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	err := db.Offset(offset).Limit(pageSize).
+		Order("login_time DESC").
+		Find(&logs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
+
+// GetRecentByUser 获取用户最近的登录记录
+func (r *loginLogRepository) GetRecentByUser(userID uint64, limit int) ([]*model.LoginLog, error) {
+	var logs []*model.LoginLog
+	err := r.db.Where("user_id = ?", userID).
+		Order("login_time DESC").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
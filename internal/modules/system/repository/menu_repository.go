@@ -143,7 +143,7 @@ func (r *menuRepository) GetUserMenus(userID uint64) ([]model.Menu, error) {
 	var isSuper bool
 	err := r.db.Table("user_roles").
 		Joins("JOIN roles ON user_roles.role_id = roles.id").
-		Where("user_roles.user_id = ? AND roles.code = 'super_admin' AND roles.status = 1", userID).
+		Where("user_roles.user_id = ? AND roles.code = 'super_admin' AND roles.status = 1 AND roles.tenant_id = ?", userID, model.SystemRoleTenantID).
 		Select("COUNT(*)").
 		Row().Scan(&isSuper)
 	if err != nil {
@@ -165,6 +165,7 @@ func (r *menuRepository) GetUserMenus(userID uint64) ([]model.Menu, error) {
 		Select("DISTINCT menus.*").
 		Joins("JOIN role_menus ON menus.id = role_menus.menu_id").
 		Joins("JOIN user_roles ON role_menus.role_id = user_roles.role_id").
+		Scopes(userGrantedMenus).
 		Where("user_roles.user_id = ? AND menus.status = ? AND menus.type IN ('directory', 'menu')", userID, 1).
 		Order("menus.sort_order ASC, menus.id ASC").
 		Find(&menus).Error
@@ -177,7 +178,7 @@ func (r *menuRepository) GetUserPermissions(userID uint64) ([]string, error) {
 	var isSuper bool
 	err := r.db.Table("user_roles").
 		Joins("JOIN roles ON user_roles.role_id = roles.id").
-		Where("user_roles.user_id = ? AND roles.code = 'super_admin' AND roles.status = 1", userID).
+		Where("user_roles.user_id = ? AND roles.code = 'super_admin' AND roles.status = 1 AND roles.tenant_id = ?", userID, model.SystemRoleTenantID).
 		Select("COUNT(*)").
 		Row().Scan(&isSuper)
 	if err != nil {
@@ -199,6 +200,7 @@ func (r *menuRepository) GetUserPermissions(userID uint64) ([]string, error) {
 		Select("DISTINCT menus.code").
 		Joins("JOIN role_menus ON menus.id = role_menus.menu_id").
 		Joins("JOIN user_roles ON role_menus.role_id = user_roles.role_id").
+		Scopes(userGrantedMenus).
 		Where("user_roles.user_id = ? AND menus.status = ? AND menus.code != ''", userID, 1).
 		Pluck("code", &permissions).Error
 	return permissions, err
@@ -309,3 +311,13 @@ func (r *menuRepository) ClearRoleMenus(roleID uint64) error {
 		Where("role_id = ?", roleID).
 		Delete(nil).Error
 }
+
+// userGrantedMenus 限定为用户启用的系统角色和所属租户角色授予的菜单，并按租户套餐裁剪
+// 需先关联role_menus和user_roles；未分配套餐的租户不做裁剪
+func userGrantedMenus(db *gorm.DB) *gorm.DB {
+	return db.Joins("JOIN roles ON roles.id = user_roles.role_id AND roles.status = 1 AND roles.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = user_roles.user_id").
+		Joins("LEFT JOIN tenants ON tenants.id = users.tenant_id").
+		Where("(roles.tenant_id = ? OR roles.tenant_id = users.tenant_id)", model.SystemRoleTenantID).
+		Where("(tenants.package_id IS NULL OR tenants.package_id = ? OR menus.id IN (SELECT menu_id FROM tenant_package_menus WHERE tenant_package_menus.package_id = tenants.package_id))", model.NoTenantPackage)
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/model"
+	"gorm.io/gorm"
+)
+
+// OperationLogQuery 操作日志查询条件
+type OperationLogQuery struct {
+	TenantID     uint64
+	UserID       uint64
+	Username     string
+	Operation    string
+	Method       string
+	Status       int
+	StartTime    *time.Time
+	EndTime      *time.Time
+	Impersonated bool // 仅查询模拟登录期间的操作
+}
+
+// OperationLogRepository 操作日志数据访问接口
+type OperationLogRepository interface {
+	// 绑定请求上下文，按上下文中的租户自动隔离数据
+	WithContext(ctx context.Context) OperationLogRepository
+	// 创建操作日志
+	Create(log *model.OperationLog) error
+	// 根据ID获取操作日志
+	GetByID(tenantID, id uint64) (*model.OperationLog, error)
+	// 获取操作日志列表（分页）
+	GetList(query *OperationLogQuery, page, pageSize int) ([]*model.OperationLog, int64, error)
+	// 获取导出用的操作日志列表
+	GetExportList(query *OperationLogQuery, limit int) ([]*model.OperationLog, error)
+}
+
+// operationLogRepository 操作日志数据访问实现
+type operationLogRepository struct {
+	db *gorm.DB
+}
+
+// NewOperationLogRepository 创建操作日志数据访问实例
+func NewOperationLogRepository(db *gorm.DB) OperationLogRepository {
+	return &operationLogRepository{
+		db: db,
+	}
+}
+
+// WithContext 绑定请求上下文
+func (r *operationLogRepository) WithContext(ctx context.Context) OperationLogRepository {
+	return &operationLogRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// Create 创建操作日志
+func (r *operationLogRepository) Create(log *model.OperationLog) error {
+	return r.db.Create(log).Error
+}
+
+// GetByID 根据ID获取操作日志
+func (r *operationLogRepository) GetByID(tenantID, id uint64) (*model.OperationLog, error) {
+	var log model.OperationLog
+	err := r.db.Where("tenant_id = ?", tenantID).First(&log, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("operation log not found")
+		}
+		return nil, err
+	}
+	return &log, nil
+}
+
+// GetList 获取操作日志列表（分页）
+func (r *operationLogRepository) GetList(query *OperationLogQuery, page, pageSize int) ([]*model.OperationLog, int64, error) {
+	var logs []*model.OperationLog
+	var total int64
+
+	db := r.applyQuery(r.db.Model(&model.OperationLog{}), query)
+
+	// 获取总数
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	err := db.Offset(offset).Limit(pageSize).
+		Order("created_at DESC").
+		Find(&logs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
+
+// GetExportList 获取导出用的操作日志列表
+func (r *operationLogRepository) GetExportList(query *OperationLogQuery, limit int) ([]*model.OperationLog, error) {
+	var logs []*model.OperationLog
+	err := r.applyQuery(r.db.Model(&model.OperationLog{}), query).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&logs).Error
+	return logs, err
+}
+
+// applyQuery 应用查询条件
+func (r *operationLogRepository) applyQuery(db *gorm.DB, query *OperationLogQuery) *gorm.DB {
+	db = db.Where("tenant_id = ?", query.TenantID)
+
+	if query.UserID > 0 {
+		db = db.Where("user_id = ?", query.UserID)
+	}
+	if query.Username != "" {
+		db = db.Where("username LIKE ?", "%"+query.Username+"%")
+	}
+	if query.Operation != "" {
+		db = db.Where("operation LIKE ?", "%"+query.Operation+"%")
+	}
+	if query.Method != "" {
+		db = db.Where("method = ?", query.Method)
+	}
+	if query.Status > 0 {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.StartTime != nil {
+		db = db.Where("created_at >= ?", query.StartTime)
+	}
+	if query.EndTime != nil {
+		db = db.Where("created_at <= ?", query.EndTime)
+	}
+	if query.Impersonated {
+		db = db.Where("impersonator_id > 0")
+	}
+
+	return db
+}
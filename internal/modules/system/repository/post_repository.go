@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+
+	"gorm.io/gorm"
+)
+
+// PostRepository 岗位数据访问接口
+type PostRepository interface {
+	// 绑定请求上下文，按上下文中的租户自动隔离数据
+	WithContext(ctx context.Context) PostRepository
+	// 创建岗位
+	Create(post *model.Post) error
+	// 批量创建岗位
+	BatchCreate(posts []*model.Post) error
+	// 根据ID获取租户内的岗位
+	GetByID(tenantID, id uint64) (*model.Post, error)
+	// 更新岗位
+	Update(post *model.Post) error
+	// 删除岗位
+	Delete(id uint64) error
+	// 检查岗位编码在租户内是否存在
+	CodeExists(tenantID uint64, code string, excludeID uint64) (bool, error)
+	// 获取租户内已存在的岗位编码
+	GetCodes(tenantID uint64) ([]string, error)
+	// 获取租户内的岗位列表（分页）
+	GetList(tenantID uint64, page, pageSize int, keyword string, status int) ([]*model.Post, int64, error)
+	// 获取租户内所有启用的岗位
+	GetEnabled(tenantID uint64) ([]*model.Post, error)
+	// 统计租户内存在的岗位数量
+	CountByIDs(tenantID uint64, ids []uint64) (int64, error)
+	// 获取岗位的用户数量
+	GetUserCount(postID uint64) (int64, error)
+	// 获取用户的岗位列表
+	GetUserPosts(userID uint64) ([]*model.Post, error)
+	// 更新用户岗位（先清空再分配）
+	UpdateUserPosts(userID uint64, postIDs []uint64) error
+}
+
+// postRepository 岗位数据访问实现
+type postRepository struct {
+	db *gorm.DB
+}
+
+// NewPostRepository 创建岗位数据访问实例
+func NewPostRepository(db *gorm.DB) PostRepository {
+	return &postRepository{
+		db: db,
+	}
+}
+
+// WithContext 绑定请求上下文
+func (r *postRepository) WithContext(ctx context.Context) PostRepository {
+	return &postRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// Create 创建岗位
+func (r *postRepository) Create(post *model.Post) error {
+	return r.db.Create(post).Error
+}
+
+// BatchCreate 批量创建岗位
+func (r *postRepository) BatchCreate(posts []*model.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	return r.db.Create(&posts).Error
+}
+
+// GetByID 根据ID获取租户内的岗位
+func (r *postRepository) GetByID(tenantID, id uint64) (*model.Post, error) {
+	var post model.Post
+	err := r.db.Where("tenant_id = ?", tenantID).First(&post, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("post not found")
+		}
+		return nil, err
+	}
+	return &post, nil
+}
+
+// Update 更新岗位
+func (r *postRepository) Update(post *model.Post) error {
+	return r.db.Save(post).Error
+}
+
+// Delete 删除岗位
+func (r *postRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.Post{}, id).Error
+}
+
+// CodeExists 检查岗位编码在租户内是否存在
+func (r *postRepository) CodeExists(tenantID uint64, code string, excludeID uint64) (bool, error) {
+	var count int64
+	query := r.db.Model(&model.Post{}).Where("tenant_id = ? AND code = ?", tenantID, code)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// GetCodes 获取租户内已存在的岗位编码
+func (r *postRepository) GetCodes(tenantID uint64) ([]string, error) {
+	var codes []string
+	err := r.db.Model(&model.Post{}).Where("tenant_id = ?", tenantID).Pluck("code", &codes).Error
+	return codes, err
+}
+
+// GetList 获取租户内的岗位列表（分页）
+func (r *postRepository) GetList(tenantID uint64, page, pageSize int, keyword string, status int) ([]*model.Post, int64, error) {
+	var posts []*model.Post
+	var total int64
+
+	query := r.db.Model(&model.Post{}).Where("tenant_id = ?", tenantID)
+	if keyword != "" {
+		query = query.Where("name LIKE ? OR code LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if status > 0 {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).
+		Order("sort_order ASC, id ASC").
+		Find(&posts).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return posts, total, nil
+}
+
+// GetEnabled 获取租户内所有启用的岗位
+func (r *postRepository) GetEnabled(tenantID uint64) ([]*model.Post, error) {
+	var posts []*model.Post
+	err := r.db.Where("tenant_id = ? AND status = ?", tenantID, model.PostStatusEnabled).
+		Order("sort_order ASC, id ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+// CountByIDs 统计租户内存在的岗位数量
+func (r *postRepository) CountByIDs(tenantID uint64, ids []uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Post{}).Where("tenant_id = ? AND id IN ?", tenantID, ids).Count(&count).Error
+	return count, err
+}
+
+// GetUserCount 获取岗位的用户数量
+func (r *postRepository) GetUserCount(postID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserPost{}).Where("post_id = ?", postID).Count(&count).Error
+	return count, err
+}
+
+// GetUserPosts 获取用户的岗位列表
+func (r *postRepository) GetUserPosts(userID uint64) ([]*model.Post, error) {
+	var posts []*model.Post
+	err := r.db.Joins("JOIN user_posts ON user_posts.post_id = posts.id").
+		Where("user_posts.user_id = ?", userID).
+		Order("posts.sort_order ASC, posts.id ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+// UpdateUserPosts 更新用户岗位（先清空再分配）
+func (r *postRepository) UpdateUserPosts(userID uint64, postIDs []uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserPost{}).Error; err != nil {
+			return err
+		}
+
+		if len(postIDs) == 0 {
+			return nil
+		}
+
+		userPosts := make([]model.UserPost, 0, len(postIDs))
+		for _, postID := range postIDs {
+			userPosts = append(userPosts, model.UserPost{
+				UserID: userID,
+				PostID: postID,
+			})
+		}
+		return tx.Create(&userPosts).Error
+	})
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 
 	"github.com/LiteMove/light-stack/internal/modules/system/model"
@@ -10,20 +11,22 @@ import (
 
 // RoleRepository 角色数据访问接口
 type RoleRepository interface {
+	// 绑定请求上下文，按上下文中的租户自动隔离数据
+	WithContext(ctx context.Context) RoleRepository
 	// 创建角色
 	Create(role *model.Role) error
 	// 根据ID获取角色
 	GetByID(id uint64) (*model.Role, error)
-	// 根据编码获取角色
-	GetByCode(code string) (*model.Role, error)
+	// 根据编码获取租户可见的角色（本租户角色优先于系统角色）
+	GetByCode(tenantID uint64, code string) (*model.Role, error)
 	// 更新角色
 	Update(role *model.Role) error
 	// 删除角色
 	Delete(id uint64) error
-	// 检查角色编码是否存在
-	CodeExists(code string) (bool, error)
-	// 获取角色列表（分页）
-	GetList(page, pageSize int, status int) ([]*model.Role, int64, error)
+	// 检查角色编码在租户内是否存在（含系统角色）
+	CodeExists(tenantID uint64, code string) (bool, error)
+	// 获取租户可见的角色列表（分页）
+	GetList(tenantID uint64, page, pageSize int, status int) ([]*model.Role, int64, error)
 	// 获取用户的角色列表
 	GetUserRoles(userID uint64) ([]*model.Role, error)
 	// 为用户分配角色
@@ -34,10 +37,14 @@ type RoleRepository interface {
 	UpdateUserRoles(userID uint64, roleIDs []uint64) error
 	// 获取角色的用户数量
 	GetRoleUserCount(roleID uint64) (int64, error)
+	// 获取拥有该角色的用户ID
+	GetRoleUserIDs(roleID uint64) ([]uint64, error)
 	// 获取角色及其用户信息
 	GetRoleWithUsers(roleID uint64) (*model.RoleWithUsers, error)
-	// 获取所有启用的角色
-	GetEnabledRoles(isSuper bool) ([]*model.Role, error)
+	// 获取租户可见的所有启用角色
+	GetEnabledRoles(tenantID uint64, isSuper bool) ([]*model.Role, error)
+	// 获取租户自有角色及其菜单，用于开通新租户时作为角色模板
+	GetTemplateRoles(tenantID uint64) ([]*model.Role, error)
 }
 
 // roleRepository 角色数据访问实现
@@ -52,6 +59,13 @@ func NewRoleRepository(db *gorm.DB) RoleRepository {
 	}
 }
 
+// WithContext 绑定请求上下文
+func (r *roleRepository) WithContext(ctx context.Context) RoleRepository {
+	return &roleRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
 // Create 创建角色
 func (r *roleRepository) Create(role *model.Role) error {
 	return r.db.Create(role).Error
@@ -70,10 +84,14 @@ func (r *roleRepository) GetByID(id uint64) (*model.Role, error) {
 	return &role, nil
 }
 
-// GetByCode 根据编码获取角色
-func (r *roleRepository) GetByCode(code string) (*model.Role, error) {
+// GetByCode 根据编码获取租户可见的角色（本租户角色优先于系统角色）
+func (r *roleRepository) GetByCode(tenantID uint64, code string) (*model.Role, error) {
 	var role model.Role
-	err := r.db.Where("code = ?", code).Preload("Users").First(&role).Error
+	err := r.db.Scopes(visibleRoles(tenantID)).
+		Where("code = ?", code).
+		Order("tenant_id DESC").
+		Preload("Users").
+		First(&role).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("role not found")
@@ -93,21 +111,24 @@ func (r *roleRepository) Delete(id uint64) error {
 	return r.db.Delete(&model.Role{}, id).Error
 }
 
-// CodeExists 检查角色编码是否存在
-func (r *roleRepository) CodeExists(code string) (bool, error) {
+// CodeExists 检查角色编码在租户内是否存在（含系统角色）
+// 系统角色对所有租户可见，因此新建系统角色时需检查全部租户
+func (r *roleRepository) CodeExists(tenantID uint64, code string) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.Role{}).
-		Where("code = ?", code).
-		Count(&count).Error
+	query := r.db.Model(&model.Role{}).Where("code = ?", code)
+	if tenantID != model.SystemRoleTenantID {
+		query = query.Scopes(visibleRoles(tenantID))
+	}
+	err := query.Count(&count).Error
 	return count > 0, err
 }
 
-// GetList 获取角色列表（分页）
-func (r *roleRepository) GetList(page, pageSize int, status int) ([]*model.Role, int64, error) {
+// GetList 获取租户可见的角色列表（分页）
+func (r *roleRepository) GetList(tenantID uint64, page, pageSize int, status int) ([]*model.Role, int64, error) {
 	var roles []*model.Role
 	var total int64
 
-	query := r.db.Model(&model.Role{})
+	query := r.db.Model(&model.Role{}).Scopes(visibleRoles(tenantID))
 
 	// 状态筛选
 	if status > 0 {
@@ -131,12 +152,14 @@ func (r *roleRepository) GetList(page, pageSize int, status int) ([]*model.Role,
 	return roles, total, nil
 }
 
-// GetUserRoles 获取用户的角色列表
+// GetUserRoles 获取用户的角色列表，仅包含系统角色和用户所属租户的角色
 func (r *roleRepository) GetUserRoles(userID uint64) ([]*model.Role, error) {
 	var roles []*model.Role
 	err := r.db.
 		Joins("JOIN user_roles ON roles.id = user_roles.role_id").
+		Joins("JOIN users ON users.id = user_roles.user_id").
 		Where("user_roles.user_id = ? AND roles.status = 1", userID).
+		Where("(roles.tenant_id = ? OR roles.tenant_id = users.tenant_id)", model.SystemRoleTenantID).
 		Order("roles.sort_order ASC").
 		Find(&roles).Error
 	return roles, err
@@ -201,6 +224,13 @@ func (r *roleRepository) GetRoleUserCount(roleID uint64) (int64, error) {
 	return count, err
 }
 
+// GetRoleUserIDs 获取拥有该角色的用户ID
+func (r *roleRepository) GetRoleUserIDs(roleID uint64) ([]uint64, error) {
+	var userIDs []uint64
+	err := r.db.Model(&model.UserRole{}).Where("role_id = ?", roleID).Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
 // GetRoleWithUsers 获取角色及其用户信息
 func (r *roleRepository) GetRoleWithUsers(roleID uint64) (*model.RoleWithUsers, error) {
 	var role model.Role
@@ -221,13 +251,30 @@ func (r *roleRepository) GetRoleWithUsers(roleID uint64) (*model.RoleWithUsers,
 	return roleWithUsers, nil
 }
 
-// GetEnabledRoles 获取所有启用的角色
-func (r *roleRepository) GetEnabledRoles(isSuper bool) ([]*model.Role, error) {
+// GetEnabledRoles 获取租户可见的所有启用角色
+func (r *roleRepository) GetEnabledRoles(tenantID uint64, isSuper bool) ([]*model.Role, error) {
 	var roles []*model.Role
-	query := r.db.Model(&model.Role{}).Where("status = ?", model.RoleStatusEnabled)
+	query := r.db.Model(&model.Role{}).Scopes(visibleRoles(tenantID)).Where("status = ?", model.RoleStatusEnabled)
 	if !isSuper {
 		query.Where("id != ?", model.SuperAdminId) // 非超级管理员不返回超级管理员角色
 	}
 	err := query.Order("sort_order ASC").Find(&roles).Error
 	return roles, err
 }
+
+// visibleRoles 限定为租户可见的角色（系统角色和本租户角色）
+func visibleRoles(tenantID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("roles.tenant_id IN ?", []uint64{model.SystemRoleTenantID, tenantID})
+	}
+}
+
+// GetTemplateRoles 获取租户自有角色及其菜单，用于开通新租户时作为角色模板
+func (r *roleRepository) GetTemplateRoles(tenantID uint64) ([]*model.Role, error) {
+	var roles []*model.Role
+	err := r.db.Preload("Menus").
+		Where("tenant_id = ? AND is_system = ?", tenantID, false).
+		Order("sort_order ASC, id ASC").
+		Find(&roles).Error
+	return roles, err
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+
+	"gorm.io/gorm"
+)
+
+// TenantPackageRepository 租户套餐数据访问接口
+type TenantPackageRepository interface {
+	// 创建套餐
+	Create(pkg *model.TenantPackage) error
+	// 根据ID获取套餐
+	GetByID(id uint64) (*model.TenantPackage, error)
+	// 更新套餐
+	Update(pkg *model.TenantPackage) error
+	// 删除套餐及其菜单关联
+	Delete(id uint64) error
+	// 检查套餐名称是否存在
+	NameExists(name string, excludeID uint64) (bool, error)
+	// 获取套餐列表（分页）
+	GetList(page, pageSize int, name string, status int) ([]*model.TenantPackage, int64, error)
+	// 获取所有启用的套餐
+	GetEnabled() ([]*model.TenantPackage, error)
+	// 获取套餐包含的菜单ID
+	GetMenuIDs(packageID uint64) ([]uint64, error)
+	// 更新套餐菜单（先清空再分配）
+	UpdateMenus(packageID uint64, menuIDs []uint64) error
+	// 获取使用该套餐的租户数量
+	GetTenantCount(packageID uint64) (int64, error)
+	// 获取租户可用的菜单ID，未分配套餐时restricted为false
+	GetTenantMenuIDs(tenantID uint64) (menuIDs []uint64, restricted bool, err error)
+	// 移除租户角色中超出套餐范围的菜单授权，返回移除数量
+	TrimTenantRoleMenus(tenantID uint64) (int64, error)
+	// 移除使用该套餐的所有租户角色中超出套餐范围的菜单授权，返回移除数量
+	TrimPackageRoleMenus(packageID uint64) (int64, error)
+}
+
+// tenantPackageRepository 租户套餐数据访问实现
+type tenantPackageRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantPackageRepository 创建租户套餐数据访问实例
+func NewTenantPackageRepository(db *gorm.DB) TenantPackageRepository {
+	return &tenantPackageRepository{
+		db: db,
+	}
+}
+
+// Create 创建套餐
+func (r *tenantPackageRepository) Create(pkg *model.TenantPackage) error {
+	return r.db.Create(pkg).Error
+}
+
+// GetByID 根据ID获取套餐
+func (r *tenantPackageRepository) GetByID(id uint64) (*model.TenantPackage, error) {
+	var pkg model.TenantPackage
+	err := r.db.First(&pkg, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant package not found")
+		}
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+// Update 更新套餐
+func (r *tenantPackageRepository) Update(pkg *model.TenantPackage) error {
+	return r.db.Save(pkg).Error
+}
+
+// Delete 删除套餐及其菜单关联
+func (r *tenantPackageRepository) Delete(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("package_id = ?", id).Delete(&model.TenantPackageMenu{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.TenantPackage{}, id).Error
+	})
+}
+
+// NameExists 检查套餐名称是否存在
+func (r *tenantPackageRepository) NameExists(name string, excludeID uint64) (bool, error) {
+	var count int64
+	query := r.db.Model(&model.TenantPackage{}).Where("name = ?", name)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// GetList 获取套餐列表（分页）
+func (r *tenantPackageRepository) GetList(page, pageSize int, name string, status int) ([]*model.TenantPackage, int64, error) {
+	var packages []*model.TenantPackage
+	var total int64
+
+	query := r.db.Model(&model.TenantPackage{})
+	if name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+	if status > 0 {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).
+		Order("sort_order ASC, id ASC").
+		Find(&packages).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return packages, total, nil
+}
+
+// GetEnabled 获取所有启用的套餐
+func (r *tenantPackageRepository) GetEnabled() ([]*model.TenantPackage, error) {
+	var packages []*model.TenantPackage
+	err := r.db.Where("status = ?", model.TenantPackageStatusEnabled).
+		Order("sort_order ASC, id ASC").
+		Find(&packages).Error
+	return packages, err
+}
+
+// GetMenuIDs 获取套餐包含的菜单ID
+func (r *tenantPackageRepository) GetMenuIDs(packageID uint64) ([]uint64, error) {
+	var menuIDs []uint64
+	err := r.db.Model(&model.TenantPackageMenu{}).
+		Where("package_id = ?", packageID).
+		Pluck("menu_id", &menuIDs).Error
+	return menuIDs, err
+}
+
+// UpdateMenus 更新套餐菜单（先清空再分配）
+func (r *tenantPackageRepository) UpdateMenus(packageID uint64, menuIDs []uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("package_id = ?", packageID).Delete(&model.TenantPackageMenu{}).Error; err != nil {
+			return err
+		}
+
+		if len(menuIDs) == 0 {
+			return nil
+		}
+
+		packageMenus := make([]model.TenantPackageMenu, 0, len(menuIDs))
+		for _, menuID := range menuIDs {
+			packageMenus = append(packageMenus, model.TenantPackageMenu{
+				PackageID: packageID,
+				MenuID:    menuID,
+			})
+		}
+		return tx.Create(&packageMenus).Error
+	})
+}
+
+// GetTenantCount 获取使用该套餐的租户数量
+func (r *tenantPackageRepository) GetTenantCount(packageID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Tenant{}).Where("package_id = ?", packageID).Count(&count).Error
+	return count, err
+}
+
+// GetTenantMenuIDs 获取租户可用的菜单ID
+func (r *tenantPackageRepository) GetTenantMenuIDs(tenantID uint64) ([]uint64, bool, error) {
+	var tenant model.Tenant
+	if err := r.db.Select("id", "package_id").First(&tenant, tenantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if tenant.PackageID == model.NoTenantPackage {
+		return nil, false, nil
+	}
+
+	menuIDs, err := r.GetMenuIDs(tenant.PackageID)
+	return menuIDs, true, err
+}
+
+// TrimTenantRoleMenus 移除租户角色中超出套餐范围的菜单授权
+// 系统角色由所有租户共享，不在此处裁剪，读取权限时按套餐过滤
+func (r *tenantPackageRepository) TrimTenantRoleMenus(tenantID uint64) (int64, error) {
+	result := r.db.Exec(`DELETE FROM role_menus
+		WHERE role_id IN (SELECT id FROM roles WHERE tenant_id = ?)
+		AND menu_id NOT IN (
+			SELECT tenant_package_menus.menu_id FROM tenant_package_menus
+			JOIN tenants ON tenants.package_id = tenant_package_menus.package_id
+			WHERE tenants.id = ?
+		)
+		AND EXISTS (SELECT 1 FROM tenants WHERE tenants.id = ? AND tenants.package_id != 0)`,
+		tenantID, tenantID, tenantID)
+	return result.RowsAffected, result.Error
+}
+
+// TrimPackageRoleMenus 移除使用该套餐的所有租户角色中超出套餐范围的菜单授权
+func (r *tenantPackageRepository) TrimPackageRoleMenus(packageID uint64) (int64, error) {
+	if packageID == model.NoTenantPackage {
+		return 0, nil
+	}
+
+	result := r.db.Exec(`DELETE FROM role_menus
+		WHERE role_id IN (
+			SELECT roles.id FROM roles
+			JOIN tenants ON tenants.id = roles.tenant_id
+			WHERE tenants.package_id = ?
+		)
+		AND menu_id NOT IN (SELECT menu_id FROM tenant_package_menus WHERE package_id = ?)`,
+		packageID, packageID)
+	return result.RowsAffected, result.Error
+}
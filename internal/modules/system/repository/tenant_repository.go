@@ -2,8 +2,10 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
 
 	"gorm.io/gorm"
 )
@@ -22,6 +24,12 @@ type TenantRepository interface {
 	Delete(id uint64) error
 	// 检查域名是否存在
 	DomainExists(domain string) (bool, error)
+	// 检查租户编码是否存在
+	CodeExists(code string) (bool, error)
+	// 根据域名查找租户ID，不存在时返回0
+	FindIDByDomain(domain string) (uint64, error)
+	// 根据租户编码查找租户ID，不存在时返回0
+	FindIDByCode(code string) (uint64, error)
 	// 获取租户列表（分页）
 	GetList(page, pageSize int, keyword string, status int) ([]*model.Tenant, int64, error)
 	// 更新租户状态
@@ -34,6 +42,16 @@ type TenantRepository interface {
 	GetSelectList() ([]*model.Tenant, error)
 	// 获取租户总数
 	GetTotalCount() (int64, error)
+	// 将已到期但仍为启用/试用状态的租户标记为过期，返回受影响的租户ID
+	ExpireOverdue(now time.Time) ([]uint64, error)
+	// 开通租户：在同一事务中创建租户、默认角色及菜单权限和首个管理员
+	Provision(provision *model.TenantProvision) error
+	// 导出租户数据
+	Export(id uint64) (*model.TenantExport, error)
+	// 禁用并软删除租户及其用户
+	SoftDeleteWithUsers(id uint64) error
+	// 彻底删除租户及其全部数据
+	Purge(id uint64) error
 }
 
 // tenantRepository 租户数据访问实现
@@ -98,6 +116,35 @@ func (r *tenantRepository) DomainExists(domain string) (bool, error) {
 	return count > 0, err
 }
 
+// CodeExists 检查租户编码是否存在
+func (r *tenantRepository) CodeExists(code string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Tenant{}).
+		Where("code = ?", code).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindIDByDomain 根据域名查找租户ID，不存在时返回0
+func (r *tenantRepository) FindIDByDomain(domain string) (uint64, error) {
+	var ids []uint64
+	err := r.db.Model(&model.Tenant{}).Where("domain = ?", domain).Limit(1).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// FindIDByCode 根据租户编码查找租户ID，不存在时返回0
+func (r *tenantRepository) FindIDByCode(code string) (uint64, error) {
+	var ids []uint64
+	err := r.db.Model(&model.Tenant{}).Where("code = ?", code).Limit(1).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
 // GetList 获取租户列表（分页）
 func (r *tenantRepository) GetList(page, pageSize int, keyword string, status int) ([]*model.Tenant, int64, error) {
 	var tenants []*model.Tenant
@@ -170,3 +217,163 @@ func (r *tenantRepository) GetTotalCount() (int64, error) {
 	err := r.db.Model(&model.Tenant{}).Count(&count).Error
 	return count, err
 }
+
+// ExpireOverdue 将已到期但仍为启用/试用状态的租户标记为过期，返回受影响的租户ID
+func (r *tenantRepository) ExpireOverdue(now time.Time) ([]uint64, error) {
+	var ids []uint64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		activeStatuses := []int{model.TenantStatusActive, model.TenantStatusTrial}
+		if err := tx.Model(&model.Tenant{}).
+			Where("expired_at IS NOT NULL AND expired_at < ? AND status IN ?", now, activeStatuses).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&model.Tenant{}).
+			Where("id IN ? AND status IN ?", ids, activeStatuses).
+			Update("status", model.TenantStatusExpired).Error
+	})
+	return ids, err
+}
+
+// Provision 开通租户：在同一事务中创建租户、默认角色及菜单权限和首个管理员
+func (r *tenantRepository) Provision(provision *model.TenantProvision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(provision.Tenant).Error; err != nil {
+			return err
+		}
+		tenantID := provision.Tenant.ID
+
+		// 复制模板角色及其菜单权限
+		for _, item := range provision.Roles {
+			item.Role.TenantID = tenantID
+			if err := tx.Omit("Users", "Menus").Create(item.Role).Error; err != nil {
+				return err
+			}
+			if len(item.MenuIDs) == 0 {
+				continue
+			}
+			roleMenus := make([]model.RoleMenus, 0, len(item.MenuIDs))
+			for _, menuID := range item.MenuIDs {
+				roleMenus = append(roleMenus, model.RoleMenus{RoleId: item.Role.ID, MenuId: menuID})
+			}
+			if err := tx.Create(&roleMenus).Error; err != nil {
+				return err
+			}
+		}
+
+		// 创建管理员并分配角色
+		provision.Admin.TenantID = tenantID
+		if err := tx.Omit("Roles", "Posts", "Tenant", "Dept").Create(provision.Admin).Error; err != nil {
+			return err
+		}
+		if len(provision.AdminRoleIDs) == 0 {
+			return nil
+		}
+		userRoles := make([]model.UserRole, 0, len(provision.AdminRoleIDs))
+		for _, roleID := range provision.AdminRoleIDs {
+			userRoles = append(userRoles, model.UserRole{UserID: provision.Admin.ID, RoleID: roleID})
+		}
+		return tx.Create(&userRoles).Error
+	})
+}
+
+// Export 导出租户数据
+func (r *tenantRepository) Export(id uint64) (*model.TenantExport, error) {
+	var tenant model.Tenant
+	if err := r.db.First(&tenant, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	export := &model.TenantExport{ExportedAt: time.Now(), Tenant: &tenant}
+	userIDs := r.db.Model(&model.User{}).Select("id").Where("tenant_id = ?", id)
+	roleIDs := r.db.Model(&model.Role{}).Select("id").Where("tenant_id = ?", id)
+	sessionIDs := r.db.Table("file_upload_sessions").Select("id").Where("tenant_id = ? AND deleted_at IS NULL", id)
+
+	queries := []*gorm.DB{
+		r.db.Where("tenant_id = ?", id).Order("id ASC").Find(&export.Users),
+		r.db.Where("tenant_id = ?", id).Order("id ASC").Find(&export.Roles),
+		r.db.Where("role_id IN (?)", roleIDs).Order("id ASC").Find(&export.RoleMenus),
+		r.db.Where("user_id IN (?)", userIDs).Order("id ASC").Find(&export.UserRoles),
+		r.db.Where("tenant_id = ?", id).Order("id ASC").Find(&export.Depts),
+		r.db.Where("tenant_id = ?", id).Order("id ASC").Find(&export.Posts),
+		r.db.Where("user_id IN (?)", userIDs).Order("id ASC").Find(&export.UserPosts),
+		r.db.Table("files").Where("tenant_id = ? AND deleted_at IS NULL", id).Order("id ASC").Find(&export.Files),
+		r.db.Table("file_upload_sessions").Where("tenant_id = ? AND deleted_at IS NULL", id).Order("id ASC").Find(&export.UploadSessions),
+		r.db.Table("file_upload_session_parts").Where("session_id IN (?)", sessionIDs).Order("id ASC").Find(&export.UploadSessionParts),
+		r.db.Where("tenant_id = ?", id).Order("id ASC").Find(&export.OperationLogs),
+		r.db.Where("tenant_id = ?", id).Order("id ASC").Find(&export.LoginLogs),
+	}
+	for _, query := range queries {
+		if query.Error != nil {
+			return nil, query.Error
+		}
+	}
+	return export, nil
+}
+
+// SoftDeleteWithUsers 禁用并软删除租户及其用户
+func (r *tenantRepository) SoftDeleteWithUsers(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Tenant{}).Where("id = ?", id).Update("status", model.TenantStatusDisabled).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tenant_id = ?", id).Delete(&model.User{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Tenant{}, id).Error
+	})
+}
+
+// Purge 彻底删除租户及其全部数据（包括已软删除的记录），文件存储中的对象不做删除
+func (r *tenantRepository) Purge(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		tx = tx.Unscoped()
+		userIDs := tx.Model(&model.User{}).Select("id").Where("tenant_id = ?", id)
+		roleIDs := tx.Model(&model.Role{}).Select("id").Where("tenant_id = ?", id)
+
+		// 先删除关联数据，再删除主体数据
+		if err := tx.Where("user_id IN (?) OR role_id IN (?)", userIDs, roleIDs).Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", userIDs).Delete(&model.UserPost{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", userIDs).Delete(&model.PasswordHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id IN (?)", roleIDs).Delete(&model.RoleMenus{}).Error; err != nil {
+			return err
+		}
+
+		tenantModels := []interface{}{
+			&model.User{},
+			&model.Role{},
+			&model.Dept{},
+			&model.Post{},
+			&sharedModel.OperationLog{},
+			&sharedModel.LoginLog{},
+		}
+		for _, tenantModel := range tenantModels {
+			if err := tx.Where("tenant_id = ?", id).Delete(tenantModel).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec("DELETE FROM file_upload_session_parts WHERE session_id IN (SELECT id FROM file_upload_sessions WHERE tenant_id = ?)", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM file_upload_sessions WHERE tenant_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM files WHERE tenant_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&model.Tenant{}, id).Error
+	})
+}
@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/pkg/database"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,6 +13,8 @@ import (
 
 // UserRepository 用户数据访问接口
 type UserRepository interface {
+	// 绑定请求上下文，按上下文中的租户自动隔离数据
+	WithContext(ctx context.Context) UserRepository
 	// 创建用户
 	Create(user *model.User) error
 	// 根据ID获取用户
@@ -34,18 +39,28 @@ type UserRepository interface {
 	EmailExists(tenantID uint64, email string) (bool, error)
 	// 检查手机号是否存在
 	PhoneExists(tenantID uint64, phone string) (bool, error)
-	// 获取用户列表（分页）
-	GetList(tenantID uint64, page, pageSize int, status int) ([]*model.User, int64, error)
+	// 获取用户列表（分页），deptIDs不为空时仅返回这些部门的用户
+	GetList(filter sharedModel.DataFilter, page, pageSize int, status int, deptIDs []uint64) ([]*model.User, int64, error)
 	// 更新用户状态
 	UpdateStatus(id uint64, status int) error
-	// 更新密码
+	// 更新密码，同时记录历史密码
 	UpdatePassword(id uint64, hashedPassword string) error
-	// 记录登录失败
-	RecordLoginFailure(id uint64) error
+	// 重置为临时密码，下次登录时必须修改
+	ResetPassword(id uint64, hashedPassword string) error
+	// 获取最近使用过的密码哈希
+	GetPasswordHistory(id uint64, limit int) ([]string, error)
+	// 记录登录失败，windowStart之前的失败不再累计，返回窗口内的失败次数
+	RecordLoginFailure(id uint64, windowStart time.Time) (int, error)
 	// 重置登录失败计数
 	ResetLoginFailures(id uint64) error
 	// 锁定用户
 	LockUser(id uint64, lockUntil time.Time) error
+	// 解锁用户
+	UnlockUser(id uint64) error
+	// 更新二次验证设置
+	UpdateMfa(id uint64, enabled bool, secret string, recoveryCodes string) error
+	// 更新恢复码
+	UpdateRecoveryCodes(id uint64, recoveryCodes string) error
 	// 为用户分配角色
 	AssignRole(userID uint64, roleIDs []uint64) error
 	// 移除用户角色
@@ -72,6 +87,13 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	}
 }
 
+// WithContext 绑定请求上下文
+func (r *userRepository) WithContext(ctx context.Context) UserRepository {
+	return &userRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
 // Create 创建用户
 func (r *userRepository) Create(user *model.User) error {
 	return r.db.Create(user).Error
@@ -93,13 +115,17 @@ func (r *userRepository) GetByID(id uint64) (*model.User, error) {
 // GetByIDWithRoles 根据ID获取用户（包含角色）
 func (r *userRepository) GetByIDWithRoles(id uint64) (*model.User, error) {
 	var user model.User
-	err := r.db.Preload("Roles").First(&user, id).Error
+	err := r.db.Preload("Dept").Preload("Posts").First(&user, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("user not found")
 		}
 		return nil, err
 	}
+	// 仅加载系统角色和用户所属租户的角色
+	if err := r.db.Model(&user).Association("Roles").Find(&user.Roles, "roles.tenant_id IN ?", []uint64{model.SystemRoleTenantID, user.TenantID}); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
@@ -119,7 +145,7 @@ func (r *userRepository) GetByUsername(tenantID uint64, username string) (*model
 // GetByUsernameWithRoles 根据用户名获取用户（包含角色）
 func (r *userRepository) GetByUsernameWithRoles(tenantID uint64, username string) (*model.User, error) {
 	var user model.User
-	err := r.db.Preload("Roles").
+	err := r.db.Preload("Roles", visibleRoles(tenantID)).
 		Where("tenant_id = ? AND username = ?", tenantID, username).
 		First(&user).Error
 	if err != nil {
@@ -157,6 +183,7 @@ func (r *userRepository) UpdateLoginInfo(id uint64, ip string) error {
 		"last_login_ip":  ip,
 		"login_failures": 0,
 		"locked_until":   nil,
+		"lock_count":     0,
 	}
 	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(updates).Error
 }
@@ -194,25 +221,31 @@ func (r *userRepository) PhoneExists(tenantID uint64, phone string) (bool, error
 }
 
 // GetList 获取用户列表（分页）
-func (r *userRepository) GetList(tenantID uint64, page, pageSize int, status int) ([]*model.User, int64, error) {
+func (r *userRepository) GetList(filter sharedModel.DataFilter, page, pageSize int, status int, deptIDs []uint64) ([]*model.User, int64, error) {
 	var users []*model.User
 	var total int64
 
-	query := r.db.Model(&model.User{}).Where("tenant_id = ?", tenantID)
+	query := r.db.Model(&model.User{}).Where("tenant_id = ?", filter.TenantID).
+		Scopes(database.DataScope(filter, database.DataScopeColumns{Dept: "dept_id", Owner: []string{"id", "created_by"}}))
 
 	// 状态筛选
 	if status > 0 {
 		query = query.Where("status = ?", status)
 	}
 
+	// 部门筛选
+	if len(deptIDs) > 0 {
+		query = query.Where("dept_id IN ?", deptIDs)
+	}
+
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// 分页查询，包含角色信息
+	// 分页查询，包含角色、部门和岗位信息
 	offset := (page - 1) * pageSize
-	err := query.Preload("Roles").
+	err := query.Preload("Roles", visibleRoles(filter.TenantID)).Preload("Dept").Preload("Posts").
 		Offset(offset).Limit(pageSize).
 		Order("created_at DESC").
 		Find(&users).Error
@@ -228,15 +261,76 @@ func (r *userRepository) UpdateStatus(id uint64, status int) error {
 	return r.db.Model(&model.User{}).Where("id = ?", id).Update("status", status).Error
 }
 
-// UpdatePassword 更新密码
+// UpdatePassword 更新密码，同时记录历史密码
 func (r *userRepository) UpdatePassword(id uint64, hashedPassword string) error {
-	return r.db.Model(&model.User{}).Where("id = ?", id).Update("password", hashedPassword).Error
+	return r.setPassword(id, hashedPassword, false)
+}
+
+// ResetPassword 重置为临时密码，下次登录时必须修改
+func (r *userRepository) ResetPassword(id uint64, hashedPassword string) error {
+	return r.setPassword(id, hashedPassword, true)
 }
 
-// RecordLoginFailure 记录登录失败
-func (r *userRepository) RecordLoginFailure(id uint64) error {
-	return r.db.Model(&model.User{}).Where("id = ?", id).
-		Update("login_failures", gorm.Expr("login_failures + 1")).Error
+// GetPasswordHistory 获取最近使用过的密码哈希（按时间倒序）
+func (r *userRepository) GetPasswordHistory(id uint64, limit int) ([]string, error) {
+	var hashes []string
+	err := r.db.Model(&model.PasswordHistory{}).
+		Where("user_id = ?", id).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("password", &hashes).Error
+	return hashes, err
+}
+
+// setPassword 更新密码和修改时间，记录历史密码并清理超出上限的记录
+func (r *userRepository) setPassword(id uint64, hashedPassword string, mustChange bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		updates := map[string]interface{}{
+			"password":             hashedPassword,
+			"password_changed_at":  &now,
+			"must_change_password": mustChange,
+		}
+		if err := tx.Model(&model.User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		history := &model.PasswordHistory{
+			UserID:    id,
+			Password:  hashedPassword,
+			CreatedAt: now,
+		}
+		if err := tx.Create(history).Error; err != nil {
+			return err
+		}
+
+		var keepIDs []uint64
+		if err := tx.Model(&model.PasswordHistory{}).
+			Where("user_id = ?", id).
+			Order("id DESC").
+			Limit(model.MaxPasswordHistory).
+			Pluck("id", &keepIDs).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND id NOT IN ?", id, keepIDs).Delete(&model.PasswordHistory{}).Error
+	})
+}
+
+// RecordLoginFailure 记录登录失败，windowStart之前的失败不再累计，返回窗口内的失败次数
+func (r *userRepository) RecordLoginFailure(id uint64, windowStart time.Time) (int, error) {
+	var failures int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		updates := map[string]interface{}{
+			"login_failures": gorm.Expr("CASE WHEN last_failed_at IS NOT NULL AND last_failed_at >= ? THEN login_failures + 1 ELSE 1 END", windowStart),
+			"last_failed_at": &now,
+		}
+		if err := tx.Model(&model.User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.User{}).Where("id = ?", id).Pluck("login_failures", &failures).Error
+	})
+	return failures, err
 }
 
 // ResetLoginFailures 重置登录失败计数
@@ -244,15 +338,47 @@ func (r *userRepository) ResetLoginFailures(id uint64) error {
 	return r.db.Model(&model.User{}).Where("id = ?", id).Update("login_failures", 0).Error
 }
 
-// LockUser 锁定用户
+// LockUser 锁定用户至指定时间，并累加锁定次数
 func (r *userRepository) LockUser(id uint64, lockUntil time.Time) error {
 	updates := map[string]interface{}{
-		"status":       3, // 锁定状态
-		"locked_until": &lockUntil,
+		"locked_until":   &lockUntil,
+		"login_failures": 0,
+		"lock_count":     gorm.Expr("lock_count + 1"),
+	}
+	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// UnlockUser 解锁用户，清除锁定状态和失败计数
+func (r *userRepository) UnlockUser(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"locked_until":   nil,
+			"login_failures": 0,
+			"lock_count":     0,
+		}
+		if err := tx.Model(&model.User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		// 手动锁定状态恢复为启用
+		return tx.Model(&model.User{}).Where("id = ? AND status = ?", id, 3).Update("status", 1).Error
+	})
+}
+
+// UpdateMfa 更新二次验证设置
+func (r *userRepository) UpdateMfa(id uint64, enabled bool, secret string, recoveryCodes string) error {
+	updates := map[string]interface{}{
+		"mfa_enabled":        enabled,
+		"mfa_secret":         secret,
+		"mfa_recovery_codes": recoveryCodes,
 	}
 	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(updates).Error
 }
 
+// UpdateRecoveryCodes 更新恢复码
+func (r *userRepository) UpdateRecoveryCodes(id uint64, recoveryCodes string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("mfa_recovery_codes", recoveryCodes).Error
+}
+
 // AssignRole 为用户分配角色（单个角色）
 func (r *userRepository) AssignRole(userID uint64, roleIDs []uint64) error {
 	return r.BatchAssignRoles(userID, roleIDs)
@@ -12,87 +12,162 @@ func RegisterSystemRoutes(api *gin.RouterGroup) {
 	// 需要认证的系统管理路由
 	admin := v1.Group("/admin")
 	admin.Use(middleware.Auth())
+	admin.Use(middleware.OperationLog(globals.OperationLogSvc())) // 记录写操作审计日志
 	{
 		// 用户管理
-		users := admin.Group("/users")
+		users := middleware.Guard(admin.Group("/users"))
 		{
-			users.POST("", middleware.CheckPermission("system:user:create"), globals.UserCtrl().CreateUser)                        // 创建用户
-			users.GET("", middleware.CheckPermission("system:user:list"), globals.UserCtrl().GetUsers)                             // 获取用户列表
-			users.GET("/:id", middleware.CheckPermission("system:user:detail"), globals.UserCtrl().GetUser)                        // 获取用户详情
-			users.PUT("/:id", middleware.CheckPermission("system:user:update"), globals.UserCtrl().UpdateUser)                     // 更新用户
-			users.DELETE("/:id", middleware.CheckPermission("system:user:delete"), globals.UserCtrl().DeleteUser)                  // 删除用户
-			users.PUT("/:id/status", middleware.CheckPermission("system:user:update"), globals.UserCtrl().UpdateUserStatus)        // 更新用户状态
-			users.PUT("/batch/status", middleware.CheckPermission("system:user:update"), globals.UserCtrl().BatchUpdateUserStatus) // 批量更新用户状态
-			users.POST("/:id/reset-password", middleware.CheckPermission("system:user:reset"), globals.UserCtrl().ResetPassword)   // 重置密码
-			users.PUT("/:id/roles", middleware.CheckPermission("system:user:role:assign"), globals.UserCtrl().AssignUserRoles)     // 为用户分配角色
-			users.GET("/:id/roles", middleware.CheckPermission("system:user:role:assign"), globals.UserCtrl().GetUserRoles)        // 获取用户角色
+			users.POST("", globals.UserCtrl().CreateUser, "system:user:create")                                                      // 创建用户
+			users.GET("", globals.UserCtrl().GetUsers, "system:user:list")                                                           // 获取用户列表
+			users.GET("/:id", globals.UserCtrl().GetUser, "system:user:detail")                                                      // 获取用户详情
+			users.PUT("/:id", globals.UserCtrl().UpdateUser, "system:user:update")                                                   // 更新用户
+			users.DELETE("/:id", middleware.RejectImpersonation(globals.UserCtrl().DeleteUser), "system:user:delete")                // 删除用户
+			users.PUT("/:id/status", globals.UserCtrl().UpdateUserStatus, "system:user:update")                                      // 更新用户状态
+			users.PUT("/batch/status", globals.UserCtrl().BatchUpdateUserStatus, "system:user:update")                               // 批量更新用户状态
+			users.POST("/:id/reset-password", middleware.RejectImpersonation(globals.UserCtrl().ResetPassword), "system:user:reset") // 重置密码
+			users.POST("/:id/reset-mfa", middleware.RejectImpersonation(globals.UserCtrl().ResetUserMfa), "system:user:reset")       // 重置二次验证
+			users.POST("/:id/unlock", globals.UserCtrl().UnlockUser, "system:user:update")                                           // 解锁用户
+			users.POST("/:id/logout", middleware.RejectImpersonation(globals.UserCtrl().RevokeUserSessions), "system:user:update")   // 强制下线
+			users.PUT("/:id/roles", globals.UserCtrl().AssignUserRoles, "system:user:role:assign")                                   // 为用户分配角色
+			users.GET("/:id/roles", globals.UserCtrl().GetUserRoles, "system:user:role:assign")                                      // 获取用户角色
+			users.PUT("/:id/posts", globals.PostCtrl().AssignUserPosts, "system:user:post:assign")                                   // 为用户分配岗位
+			users.GET("/:id/posts", globals.PostCtrl().GetUserPosts, "system:user:post:assign")                                      // 获取用户岗位
 		}
 
 		// 角色管理
-		roles := admin.Group("/roles")
+		roles := middleware.Guard(admin.Group("/roles"))
 		{
-			roles.POST("", globals.RoleCtrl().CreateRole)                 // 创建角色
-			roles.GET("/select-list", globals.RoleCtrl().GetEnabledRoles) // 获取下拉角色列表
-			roles.GET("", globals.RoleCtrl().GetRoles)                    // 获取角色列表
-			roles.GET("/:id", globals.RoleCtrl().GetRole)                 // 获取角色详情
-			roles.PUT("/:id", globals.RoleCtrl().UpdateRole)              // 更新角色
-			roles.DELETE("/:id", globals.RoleCtrl().DeleteRole)           // 删除角色
-			roles.GET("/:id/menus", globals.MenuCtrl().GetRoleMenus)      // 获取角色菜单
-			roles.PUT("/:id/menus", globals.MenuCtrl().AssignMenusToRole) // 为角色分配菜单
+			roles.POST("", globals.RoleCtrl().CreateRole, "system:role:create")                                          // 创建角色
+			roles.GET("/select-list", globals.RoleCtrl().GetEnabledRoles, "system:role:list", "system:user:role:assign") // 获取下拉角色列表
+			roles.GET("", globals.RoleCtrl().GetRoles, "system:role:list")                                               // 获取角色列表
+			roles.GET("/:id", globals.RoleCtrl().GetRole, "system:role:list")                                            // 获取角色详情
+			roles.PUT("/:id", globals.RoleCtrl().UpdateRole, "system:role:update")                                       // 更新角色
+			roles.DELETE("/:id", middleware.RejectImpersonation(globals.RoleCtrl().DeleteRole), "system:role:delete")    // 删除角色
+			roles.GET("/:id/menus", globals.MenuCtrl().GetRoleMenus, "system:role:menu:assign")                          // 获取角色菜单
+			roles.PUT("/:id/menus", globals.MenuCtrl().AssignMenusToRole, "system:role:menu:assign")                     // 为角色分配菜单
+		}
+
+		// 部门管理
+		depts := middleware.Guard(admin.Group("/depts"))
+		{
+			depts.POST("", globals.DeptCtrl().CreateDept, "system:dept:create")                                       // 创建部门
+			depts.GET("/tree", globals.DeptCtrl().GetDeptTree, "system:dept:list", "system:user:list")                // 获取部门树
+			depts.GET("/:id", globals.DeptCtrl().GetDept, "system:dept:list")                                         // 获取部门详情
+			depts.PUT("/:id", globals.DeptCtrl().UpdateDept, "system:dept:update")                                    // 更新部门
+			depts.DELETE("/:id", middleware.RejectImpersonation(globals.DeptCtrl().DeleteDept), "system:dept:delete") // 删除部门
+		}
+
+		// 岗位管理
+		posts := middleware.Guard(admin.Group("/posts"))
+		{
+			posts.POST("", globals.PostCtrl().CreatePost, "system:post:create")                                        // 创建岗位
+			posts.GET("", globals.PostCtrl().GetPosts, "system:post:list")                                             // 获取岗位列表
+			posts.GET("/select-list", globals.PostCtrl().GetSelectList, "system:post:list", "system:user:post:assign") // 获取下拉岗位列表
+			posts.POST("/import-dict", globals.PostCtrl().ImportFromDict, "system:post:create")                        // 从字典导入岗位
+			posts.GET("/:id", globals.PostCtrl().GetPost, "system:post:list")                                          // 获取岗位详情
+			posts.PUT("/:id", globals.PostCtrl().UpdatePost, "system:post:update")                                     // 更新岗位
+			posts.DELETE("/:id", middleware.RejectImpersonation(globals.PostCtrl().DeletePost), "system:post:delete")  // 删除岗位
 		}
 
 		// 菜单管理
-		menus := admin.Group("/menus")
+		menus := middleware.Guard(admin.Group("/menus"))
 		{
-			menus.POST("", globals.MenuCtrl().CreateMenu)                 // 创建菜单
-			menus.GET("", globals.MenuCtrl().GetMenus)                    // 获取菜单列表
-			menus.GET("/tree", globals.MenuCtrl().GetMenuTree)            // 获取菜单树
-			menus.GET("/:id", globals.MenuCtrl().GetMenu)                 // 获取菜单详情
-			menus.PUT("/:id", globals.MenuCtrl().UpdateMenu)              // 更新菜单
-			menus.DELETE("/:id", globals.MenuCtrl().DeleteMenu)           // 删除菜单
-			menus.PUT("/:id/status", globals.MenuCtrl().UpdateMenuStatus) // 更新菜单状态
+			menus.POST("", globals.MenuCtrl().CreateMenu, "system:menu:create")                                       // 创建菜单
+			menus.GET("", globals.MenuCtrl().GetMenus, "system:menu:list")                                            // 获取菜单列表
+			menus.GET("/tree", globals.MenuCtrl().GetMenuTree, "system:menu:list", "system:role:menu:assign")         // 获取菜单树
+			menus.GET("/:id", globals.MenuCtrl().GetMenu, "system:menu:list")                                         // 获取菜单详情
+			menus.PUT("/:id", globals.MenuCtrl().UpdateMenu, "system:menu:update")                                    // 更新菜单
+			menus.DELETE("/:id", middleware.RejectImpersonation(globals.MenuCtrl().DeleteMenu), "system:menu:delete") // 删除菜单
+			menus.PUT("/:id/status", globals.MenuCtrl().UpdateMenuStatus, "system:menu:update")                       // 更新菜单状态
 		}
 
-		// 租户管理
-		tenants := admin.Group("/tenants")
+		// 权限清单
+		permissions := middleware.Guard(admin.Group("/permissions"))
 		{
-			tenants.POST("", globals.TenantCtrl().CreateTenant) // 创建租户
+			permissions.GET("/manifest", globals.MenuCtrl().GetPermissionManifest, "system:menu:list") // 获取路由权限清单
+		}
+
+		// 租户管理（仅超级管理员）
+		tenants := middleware.GuardSuperAdmin(admin.Group("/tenants"))
+		{
+			tenants.POST("", globals.TenantCtrl().CreateTenant)              // 创建租户
+			tenants.POST("/provision", globals.TenantCtrl().ProvisionTenant) // 开通租户（含管理员和默认角色）
 			tenants.GET("", globals.TenantCtrl().GetTenants)
-			tenants.GET("/list", globals.TenantCtrl().GetSelectList)            // 获取租户列表
-			tenants.GET("/:id", globals.TenantCtrl().GetTenant)                 // 获取租户详情
-			tenants.PUT("/:id", globals.TenantCtrl().UpdateTenant)              // 更新租户
-			tenants.DELETE("/:id", globals.TenantCtrl().DeleteTenant)           // 删除租户
-			tenants.PUT("/:id/status", globals.TenantCtrl().UpdateTenantStatus) // 更新租户状态
-			tenants.GET("/check-domain", globals.TenantCtrl().CheckDomain)      // 检查域名可用性
-			tenants.GET("/check-name", globals.TenantCtrl().CheckName)          // 检查名称可用性
-			tenants.GET("/:id/config", globals.TenantCtrl().GetTenantConfig)    // 获取租户配置
-			tenants.PUT("/:id/config", globals.TenantCtrl().UpdateTenantConfig) // 更新租户配置
+			tenants.GET("/list", globals.TenantCtrl().GetSelectList)                     // 获取租户列表
+			tenants.GET("/:id", globals.TenantCtrl().GetTenant)                          // 获取租户详情
+			tenants.PUT("/:id", globals.TenantCtrl().UpdateTenant)                       // 更新租户
+			tenants.DELETE("/:id", globals.TenantCtrl().DeleteTenant)                    // 删除租户
+			tenants.PUT("/:id/status", globals.TenantCtrl().UpdateTenantStatus)          // 更新租户状态
+			tenants.GET("/check-domain", globals.TenantCtrl().CheckDomain)               // 检查域名可用性
+			tenants.GET("/check-name", globals.TenantCtrl().CheckName)                   // 检查名称可用性
+			tenants.GET("/:id/config", globals.TenantCtrl().GetTenantConfig)             // 获取租户配置
+			tenants.PUT("/:id/config", globals.TenantCtrl().UpdateTenantConfig)          // 更新租户配置
+			tenants.PUT("/:id/package", globals.TenantPackageCtrl().AssignTenantPackage) // 升级/降级租户套餐
+			tenants.GET("/:id/export", globals.TenantCtrl().ExportTenant)                // 导出租户数据
+			tenants.POST("/:id/offboard", globals.TenantCtrl().OffboardTenant)           // 下线租户（归档后删除）
+		}
+
+		// 模拟登录（仅超级管理员）
+		impersonation := middleware.GuardSuperAdmin(admin.Group("/impersonation"))
+		{
+			impersonation.POST("/users/:id", globals.ImpersonationCtrl().StartImpersonation) // 模拟指定用户登录
 		}
 
-		// 字典管理
+		// 租户套餐管理（仅超级管理员）
+		packages := middleware.GuardSuperAdmin(admin.Group("/tenant-packages"))
+		{
+			packages.POST("", globals.TenantPackageCtrl().CreatePackage)            // 创建套餐
+			packages.GET("", globals.TenantPackageCtrl().GetPackages)               // 获取套餐列表
+			packages.GET("/select-list", globals.TenantPackageCtrl().GetSelectList) // 获取下拉套餐列表
+			packages.GET("/:id", globals.TenantPackageCtrl().GetPackage)            // 获取套餐详情
+			packages.PUT("/:id", globals.TenantPackageCtrl().UpdatePackage)         // 更新套餐
+			packages.DELETE("/:id", globals.TenantPackageCtrl().DeletePackage)      // 删除套餐
+		}
+
+		// 字典管理（字典为全局数据，各租户可查看，仅超级管理员可维护）
 		dicts := admin.Group("/dicts")
 		{
 			// 字典类型管理
-			dictTypes := dicts.Group("/types")
+			dictTypes := middleware.Guard(dicts.Group("/types"))
+			{
+				dictTypes.GET("", globals.DictCtrl().GetTypeList, "system:dict:list") // 获取字典类型列表
+				dictTypes.GET("/:id", globals.DictCtrl().GetType, "system:dict:list") // 获取字典类型详情
+			}
+			dictTypesAdmin := middleware.GuardSuperAdmin(dicts.Group("/types"))
 			{
-				dictTypes.POST("", globals.DictCtrl().CreateType)       // 创建字典类型
-				dictTypes.GET("", globals.DictCtrl().GetTypeList)       // 获取字典类型列表
-				dictTypes.GET("/:id", globals.DictCtrl().GetType)       // 获取字典类型详情
-				dictTypes.PUT("/:id", globals.DictCtrl().UpdateType)    // 更新字典类型
-				dictTypes.DELETE("/:id", globals.DictCtrl().DeleteType) // 删除字典类型
+				dictTypesAdmin.POST("", globals.DictCtrl().CreateType, "system:dict:create")       // 创建字典类型
+				dictTypesAdmin.PUT("/:id", globals.DictCtrl().UpdateType, "system:dict:update")    // 更新字典类型
+				dictTypesAdmin.DELETE("/:id", globals.DictCtrl().DeleteType, "system:dict:delete") // 删除字典类型
 			}
 
 			// 字典数据管理
-			dictData := dicts.Group("/data")
+			dictData := middleware.Guard(dicts.Group("/data"))
+			{
+				dictData.GET("/type/:type", globals.DictCtrl().GetDataList, "system:dict:list") // 获取字典数据列表
+				dictData.GET("/:id", globals.DictCtrl().GetData, "system:dict:list")            // 获取字典数据详情
+			}
+			dictDataAdmin := middleware.GuardSuperAdmin(dicts.Group("/data"))
 			{
-				dictData.POST("", globals.DictCtrl().CreateData)                        // 创建字典数据
-				dictData.GET("/type/:type", globals.DictCtrl().GetDataList)             // 获取字典数据列表
-				dictData.GET("/:id", globals.DictCtrl().GetData)                        // 获取字典数据详情
-				dictData.PUT("/:id", globals.DictCtrl().UpdateData)                     // 更新字典数据
-				dictData.DELETE("/:id", globals.DictCtrl().DeleteData)                  // 删除字典数据
-				dictData.PUT("/batch/status", globals.DictCtrl().BatchUpdateDataStatus) // 批量更新状态
-				dictData.DELETE("/batch", globals.DictCtrl().BatchDeleteData)           // 批量删除
+				dictDataAdmin.POST("", globals.DictCtrl().CreateData, "system:dict:create")                        // 创建字典数据
+				dictDataAdmin.PUT("/:id", globals.DictCtrl().UpdateData, "system:dict:update")                     // 更新字典数据
+				dictDataAdmin.DELETE("/:id", globals.DictCtrl().DeleteData, "system:dict:delete")                  // 删除字典数据
+				dictDataAdmin.PUT("/batch/status", globals.DictCtrl().BatchUpdateDataStatus, "system:dict:update") // 批量更新状态
+				dictDataAdmin.DELETE("/batch", globals.DictCtrl().BatchDeleteData, "system:dict:delete")           // 批量删除
 			}
 		}
+
+		// 操作日志
+		operationLogs := middleware.Guard(admin.Group("/operation-logs"))
+		{
+			operationLogs.GET("", globals.OperationLogCtrl().GetOperationLogs, "system:operlog:list")             // 获取操作日志列表
+			operationLogs.GET("/export", globals.OperationLogCtrl().ExportOperationLogs, "system:operlog:export") // 导出操作日志
+			operationLogs.GET("/:id", globals.OperationLogCtrl().GetOperationLog, "system:operlog:list")          // 获取操作日志详情
+		}
+
+		// 登录日志
+		loginLogs := middleware.Guard(admin.Group("/login-logs"))
+		{
+			loginLogs.GET("", globals.LoginLogCtrl().GetLoginLogs, "system:loginlog:list") // 获取登录日志列表
+		}
 	}
 }
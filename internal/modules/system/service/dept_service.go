@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/pkg/permission"
+)
+
+// DeptService 部门服务接口
+type DeptService interface {
+	CreateDept(ctx context.Context, dept *model.Dept) error
+	UpdateDept(ctx context.Context, dept *model.Dept) error
+	DeleteDept(ctx context.Context, tenantID, id uint64) error
+	GetDept(ctx context.Context, tenantID, id uint64) (*model.DeptProfile, error)
+	GetDeptTree(ctx context.Context, tenantID uint64, status int) ([]model.DeptTreeNode, error)
+	// GetSubtreeIDs 获取部门及其所有下级部门的ID
+	GetSubtreeIDs(ctx context.Context, tenantID, id uint64) ([]uint64, error)
+}
+
+// deptService 部门服务实现
+type deptService struct {
+	deptRepo repository.DeptRepository
+	userRepo repository.UserRepository
+}
+
+// NewDeptService 创建部门服务
+func NewDeptService(deptRepo repository.DeptRepository, userRepo repository.UserRepository) DeptService {
+	return &deptService{
+		deptRepo: deptRepo,
+		userRepo: userRepo,
+	}
+}
+
+// CreateDept 创建部门
+func (s *deptService) CreateDept(ctx context.Context, dept *model.Dept) error {
+	if err := s.checkParent(ctx, dept.TenantID, dept.ParentID); err != nil {
+		return err
+	}
+	if err := s.checkName(ctx, dept, 0); err != nil {
+		return err
+	}
+	if err := s.checkLeader(ctx, dept.TenantID, dept.LeaderID); err != nil {
+		return err
+	}
+
+	if err := s.deptRepo.WithContext(ctx).Create(dept); err != nil {
+		return fmt.Errorf("创建部门失败: %w", err)
+	}
+
+	// 新部门加入上级部门的子树，影响"本部门"数据权限范围
+	permission.InvalidateAll()
+	return nil
+}
+
+// UpdateDept 更新部门
+func (s *deptService) UpdateDept(ctx context.Context, dept *model.Dept) error {
+	deptRepo := s.deptRepo.WithContext(ctx)
+
+	existing, err := deptRepo.GetByID(dept.TenantID, dept.ID)
+	if err != nil {
+		return errors.New("部门不存在")
+	}
+
+	// 检查上级部门有效性
+	moved := dept.ParentID != existing.ParentID
+	if moved && dept.ParentID != 0 {
+		// 不能将自己设为上级部门
+		if dept.ParentID == dept.ID {
+			return errors.New("不能将自己设为上级部门")
+		}
+		if err := s.checkParent(ctx, dept.TenantID, dept.ParentID); err != nil {
+			return err
+		}
+
+		// 检查是否形成循环引用
+		circular, err := s.hasCircularReference(ctx, dept.TenantID, dept.ID, dept.ParentID)
+		if err != nil {
+			return err
+		}
+		if circular {
+			return errors.New("不能形成循环引用")
+		}
+	}
+	if err := s.checkName(ctx, dept, dept.ID); err != nil {
+		return err
+	}
+	if dept.LeaderID != existing.LeaderID {
+		if err := s.checkLeader(ctx, dept.TenantID, dept.LeaderID); err != nil {
+			return err
+		}
+	}
+
+	existing.ParentID = dept.ParentID
+	existing.Name = dept.Name
+	existing.LeaderID = dept.LeaderID
+	existing.Phone = dept.Phone
+	existing.Email = dept.Email
+	existing.SortOrder = dept.SortOrder
+	existing.Status = dept.Status
+	if err := deptRepo.Update(existing); err != nil {
+		return fmt.Errorf("更新部门失败: %w", err)
+	}
+
+	// 部门层级变化影响"本部门"数据权限范围
+	if moved {
+		permission.InvalidateAll()
+	}
+	return nil
+}
+
+// DeleteDept 删除部门
+func (s *deptService) DeleteDept(ctx context.Context, tenantID, id uint64) error {
+	deptRepo := s.deptRepo.WithContext(ctx)
+
+	if _, err := deptRepo.GetByID(tenantID, id); err != nil {
+		return errors.New("部门不存在")
+	}
+
+	hasChildren, err := deptRepo.HasChildren(id)
+	if err != nil {
+		return fmt.Errorf("检查下级部门失败: %w", err)
+	}
+	if hasChildren {
+		return errors.New("存在下级部门，不能删除")
+	}
+
+	count, err := deptRepo.GetUserCount(id)
+	if err != nil {
+		return fmt.Errorf("检查部门用户失败: %w", err)
+	}
+	if count > 0 {
+		return errors.New("部门下还有用户，不能删除")
+	}
+
+	if err := deptRepo.Delete(id); err != nil {
+		return fmt.Errorf("删除部门失败: %w", err)
+	}
+
+	// 部门从子树中移除，影响"本部门"数据权限范围
+	permission.InvalidateAll()
+	return nil
+}
+
+// GetDept 获取部门详情
+func (s *deptService) GetDept(ctx context.Context, tenantID, id uint64) (*model.DeptProfile, error) {
+	dept, err := s.deptRepo.WithContext(ctx).GetByID(tenantID, id)
+	if err != nil {
+		return nil, errors.New("部门不存在")
+	}
+
+	profile := dept.ToProfile()
+	return &profile, nil
+}
+
+// GetDeptTree 获取部门树
+func (s *deptService) GetDeptTree(ctx context.Context, tenantID uint64, status int) ([]model.DeptTreeNode, error) {
+	depts, err := s.deptRepo.WithContext(ctx).GetAll(tenantID, status)
+	if err != nil {
+		return nil, fmt.Errorf("获取部门列表失败: %w", err)
+	}
+
+	return s.buildDeptTree(depts, 0), nil
+}
+
+// GetSubtreeIDs 获取部门及其所有下级部门的ID
+func (s *deptService) GetSubtreeIDs(ctx context.Context, tenantID, id uint64) ([]uint64, error) {
+	deptRepo := s.deptRepo.WithContext(ctx)
+
+	if _, err := deptRepo.GetByID(tenantID, id); err != nil {
+		return nil, errors.New("部门不存在")
+	}
+
+	depts, err := deptRepo.GetAll(tenantID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("获取部门列表失败: %w", err)
+	}
+	return model.DeptSubtreeIDs(depts, id), nil
+}
+
+// checkParent 检查上级部门是否存在且启用
+func (s *deptService) checkParent(ctx context.Context, tenantID, parentID uint64) error {
+	if parentID == 0 {
+		return nil
+	}
+
+	parent, err := s.deptRepo.WithContext(ctx).GetByID(tenantID, parentID)
+	if err != nil {
+		return errors.New("上级部门不存在")
+	}
+	if parent.Status != model.DeptStatusEnabled {
+		return errors.New("上级部门已禁用")
+	}
+	return nil
+}
+
+// checkName 检查同级部门名称是否重复
+func (s *deptService) checkName(ctx context.Context, dept *model.Dept, excludeID uint64) error {
+	exists, err := s.deptRepo.WithContext(ctx).NameExists(dept.TenantID, dept.ParentID, dept.Name, excludeID)
+	if err != nil {
+		return fmt.Errorf("检查部门名称失败: %w", err)
+	}
+	if exists {
+		return errors.New("同级部门名称已存在")
+	}
+	return nil
+}
+
+// checkLeader 检查负责人是否为本租户用户
+func (s *deptService) checkLeader(ctx context.Context, tenantID, leaderID uint64) error {
+	if leaderID == 0 {
+		return nil
+	}
+
+	leader, err := s.userRepo.WithContext(ctx).GetByID(leaderID)
+	if err != nil || leader.TenantID != tenantID {
+		return errors.New("负责人不存在")
+	}
+	return nil
+}
+
+// hasCircularReference 检查是否存在循环引用
+func (s *deptService) hasCircularReference(ctx context.Context, tenantID, deptID, parentID uint64) (bool, error) {
+	depts, err := s.deptRepo.WithContext(ctx).GetAll(tenantID, 0)
+	if err != nil {
+		return false, fmt.Errorf("获取部门列表失败: %w", err)
+	}
+
+	parents := make(map[uint64]uint64, len(depts))
+	for _, dept := range depts {
+		parents[dept.ID] = dept.ParentID
+	}
+
+	visited := make(map[uint64]bool)
+	for currentID := parentID; currentID != 0; currentID = parents[currentID] {
+		if currentID == deptID || visited[currentID] {
+			return true, nil
+		}
+		visited[currentID] = true
+	}
+	return false, nil
+}
+
+// buildDeptTree 构建部门树
+func (s *deptService) buildDeptTree(depts []*model.Dept, parentID uint64) []model.DeptTreeNode {
+	var tree []model.DeptTreeNode
+
+	for _, dept := range depts {
+		if dept.ParentID == parentID {
+			node := dept.ToTreeNode()
+			node.Children = s.buildDeptTree(depts, dept.ID)
+			tree = append(tree, node)
+		}
+	}
+
+	return tree
+}
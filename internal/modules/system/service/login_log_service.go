@@ -0,0 +1,35 @@
+package service
+
+import (
+	"fmt"
+
+	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/internal/shared/model"
+)
+
+// LoginLogService 登录日志服务接口
+type LoginLogService interface {
+	// 获取登录日志列表
+	GetLogList(query *repository2.LoginLogQuery, page, pageSize int) ([]*model.LoginLog, int64, error)
+}
+
+// loginLogService 登录日志服务实现
+type loginLogService struct {
+	logRepo repository2.LoginLogRepository
+}
+
+// NewLoginLogService 创建登录日志服务
+func NewLoginLogService(logRepo repository2.LoginLogRepository) LoginLogService {
+	return &loginLogService{
+		logRepo: logRepo,
+	}
+}
+
+// GetLogList 获取登录日志列表
+func (s *loginLogService) GetLogList(query *repository2.LoginLogQuery, page, pageSize int) ([]*model.LoginLog, int64, error) {
+	logs, total, err := s.logRepo.GetList(query, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取登录日志列表失败: %w", err)
+	}
+	return logs, total, nil
+}
@@ -5,6 +5,7 @@ import (
 	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
 
 	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/pkg/permission"
 	"gorm.io/gorm"
 )
 
@@ -20,28 +21,43 @@ type MenuService interface {
 	GetMenuList(page, pageSize int, name string, status int) ([]model.MenuProfile, int64, error)
 	GetMenuTree() ([]model.MenuTreeNode, error)
 	GetUserMenuTree(userID uint64) ([]model.MenuTreeNode, error)
-	GetRoleMenus(roleID uint64) ([]model.MenuProfile, error)
+	GetRoleMenus(scope RoleScope, roleID uint64) ([]model.MenuProfile, error)
 
 	// 状态操作
 	UpdateMenuStatus(id uint64, status int) error
 
 	// 权限相关
-	AssignMenusToRole(roleID uint64, menuIDs []uint64) error
+	AssignMenusToRole(scope RoleScope, roleID uint64, menuIDs []uint64) error
 	GetMenuPermissions(userID uint64) ([]string, error)
 	CheckMenuPermission(userID uint64, menuCode string) (bool, error)
+	GetPermissionManifest() (*PermissionManifest, error)
+}
+
+// PermissionManifest 路由权限清单
+type PermissionManifest struct {
+	Routes       []ManifestRoute `json:"routes"`
+	MissingCodes []string        `json:"missingCodes"` // 路由声明但菜单表中不存在的权限码
+}
+
+// ManifestRoute 路由权限清单项
+type ManifestRoute struct {
+	permission.RouteInfo
+	MissingCodes []string `json:"missingCodes,omitempty"`
 }
 
 // menuService 菜单服务实现
 type menuService struct {
-	menuRepo repository2.MenuRepository
-	roleRepo repository2.RoleRepository
+	menuRepo    repository2.MenuRepository
+	roleRepo    repository2.RoleRepository
+	packageRepo repository2.TenantPackageRepository
 }
 
 // NewMenuService 创建菜单服务
-func NewMenuService(menuRepo repository2.MenuRepository, roleRepo repository2.RoleRepository) MenuService {
+func NewMenuService(menuRepo repository2.MenuRepository, roleRepo repository2.RoleRepository, packageRepo repository2.TenantPackageRepository) MenuService {
 	return &menuService{
-		menuRepo: menuRepo,
-		roleRepo: roleRepo,
+		menuRepo:    menuRepo,
+		roleRepo:    roleRepo,
+		packageRepo: packageRepo,
 	}
 }
 
@@ -116,7 +132,13 @@ func (s *menuService) UpdateMenu(menu *model.Menu) error {
 		}
 	}
 
-	return s.menuRepo.Update(menu)
+	if err := s.menuRepo.Update(menu); err != nil {
+		return err
+	}
+
+	// 权限码或状态可能变化，所有用户的权限缓存失效
+	permission.InvalidateAll()
+	return nil
 }
 
 // DeleteMenu 删除菜单
@@ -136,7 +158,12 @@ func (s *menuService) DeleteMenu(id uint64) error {
 		return errors.New("存在子菜单，不能删除")
 	}
 
-	return s.menuRepo.Delete(id)
+	if err := s.menuRepo.Delete(id); err != nil {
+		return err
+	}
+
+	permission.InvalidateAll()
+	return nil
 }
 
 // GetMenuList 获取菜单列表
@@ -190,7 +217,12 @@ func (s *menuService) GetUserMenuTree(userID uint64) ([]model.MenuTreeNode, erro
 }
 
 // GetRoleMenus 获取角色菜单
-func (s *menuService) GetRoleMenus(roleID uint64) ([]model.MenuProfile, error) {
+func (s *menuService) GetRoleMenus(scope RoleScope, roleID uint64) ([]model.MenuProfile, error) {
+	role, err := s.roleRepo.GetByID(roleID)
+	if err != nil || !role.VisibleTo(scope.TenantID) {
+		return nil, errors.New("角色不存在")
+	}
+
 	menus, err := s.menuRepo.GetRoleMenus(roleID)
 	if err != nil {
 		return nil, err
@@ -212,15 +244,39 @@ func (s *menuService) UpdateMenuStatus(id uint64, status int) error {
 		return errors.New("菜单不存在")
 	}
 
-	return s.menuRepo.UpdateStatus(id, status)
+	if err := s.menuRepo.UpdateStatus(id, status); err != nil {
+		return err
+	}
+
+	permission.InvalidateAll()
+	return nil
 }
 
 // AssignMenusToRole 为角色分配菜单
-func (s *menuService) AssignMenusToRole(roleID uint64, menuIDs []uint64) error {
-	// 检查角色是否存在
-	_, err := s.roleRepo.GetByID(roleID)
+func (s *menuService) AssignMenusToRole(scope RoleScope, roleID uint64, menuIDs []uint64) error {
+	// 检查角色是否存在且可修改
+	role, err := getManageableRole(s.roleRepo, scope, roleID)
 	if err != nil {
-		return errors.New("角色不存在")
+		return err
+	}
+
+	// 租户角色只能分配租户套餐内的菜单
+	if role.TenantID != model.SystemRoleTenantID {
+		allowedIDs, restricted, err := s.packageRepo.GetTenantMenuIDs(role.TenantID)
+		if err != nil {
+			return err
+		}
+		if restricted {
+			allowed := make(map[uint64]bool, len(allowedIDs))
+			for _, id := range allowedIDs {
+				allowed[id] = true
+			}
+			for _, menuID := range menuIDs {
+				if !allowed[menuID] {
+					return errors.New("菜单不在租户套餐范围内")
+				}
+			}
+		}
 	}
 
 	// 检查菜单是否存在
@@ -232,7 +288,13 @@ func (s *menuService) AssignMenusToRole(roleID uint64, menuIDs []uint64) error {
 	}
 
 	// 调用repository层的方法
-	return s.menuRepo.AssignMenusToRole(roleID, menuIDs)
+	if err := s.menuRepo.AssignMenusToRole(roleID, menuIDs); err != nil {
+		return err
+	}
+
+	// 拥有该角色的用户权限缓存失效
+	permission.InvalidateRoleUsers(roleID)
+	return nil
 }
 
 // GetMenuPermissions 获取用户菜单权限
@@ -333,3 +395,39 @@ func (s *menuService) buildMenuTree(menus []model.Menu, parentID uint64) []model
 
 	return tree
 }
+
+// GetPermissionManifest 获取路由权限清单，并标记菜单表中尚未配置的权限码
+func (s *menuService) GetPermissionManifest() (*PermissionManifest, error) {
+	menus, err := s.menuRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(menus))
+	for _, menu := range menus {
+		if menu.Code != "" {
+			existing[menu.Code] = true
+		}
+	}
+
+	manifest := &PermissionManifest{
+		Routes:       make([]ManifestRoute, 0),
+		MissingCodes: make([]string, 0),
+	}
+	for _, route := range permission.Routes() {
+		item := ManifestRoute{RouteInfo: route}
+		for _, code := range route.Permissions {
+			if !existing[code] {
+				item.MissingCodes = append(item.MissingCodes, code)
+			}
+		}
+		manifest.Routes = append(manifest.Routes, item)
+	}
+	for _, code := range permission.RouteCodes() {
+		if !existing[code] {
+			manifest.MissingCodes = append(manifest.MissingCodes, code)
+		}
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/internal/shared/model"
+)
+
+// MaxOperationLogExport 单次导出的最大日志条数
+const MaxOperationLogExport = 10000
+
+// OperationLogService 操作日志服务接口
+type OperationLogService interface {
+	// 记录操作日志
+	CreateLog(log *model.OperationLog) error
+	// 获取操作日志详情
+	GetLog(ctx context.Context, tenantID, id uint64) (*model.OperationLog, error)
+	// 获取操作日志列表
+	GetLogList(ctx context.Context, query *repository2.OperationLogQuery, page, pageSize int) ([]*model.OperationLog, int64, error)
+	// 导出操作日志
+	ExportLogs(ctx context.Context, query *repository2.OperationLogQuery) ([]*model.OperationLog, error)
+}
+
+// operationLogService 操作日志服务实现
+type operationLogService struct {
+	logRepo repository2.OperationLogRepository
+}
+
+// NewOperationLogService 创建操作日志服务
+func NewOperationLogService(logRepo repository2.OperationLogRepository) OperationLogService {
+	return &operationLogService{
+		logRepo: logRepo,
+	}
+}
+
+// CreateLog 记录操作日志
+func (s *operationLogService) CreateLog(log *model.OperationLog) error {
+	if err := s.logRepo.Create(log); err != nil {
+		return fmt.Errorf("记录操作日志失败: %w", err)
+	}
+	return nil
+}
+
+// GetLog 获取操作日志详情
+func (s *operationLogService) GetLog(ctx context.Context, tenantID, id uint64) (*model.OperationLog, error) {
+	log, err := s.logRepo.WithContext(ctx).GetByID(tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("获取操作日志失败: %w", err)
+	}
+	return log, nil
+}
+
+// GetLogList 获取操作日志列表
+func (s *operationLogService) GetLogList(ctx context.Context, query *repository2.OperationLogQuery, page, pageSize int) ([]*model.OperationLog, int64, error) {
+	logs, total, err := s.logRepo.WithContext(ctx).GetList(query, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取操作日志列表失败: %w", err)
+	}
+	return logs, total, nil
+}
+
+// ExportLogs 导出操作日志
+func (s *operationLogService) ExportLogs(ctx context.Context, query *repository2.OperationLogQuery) ([]*model.OperationLog, error) {
+	logs, err := s.logRepo.WithContext(ctx).GetExportList(query, MaxOperationLogExport)
+	if err != nil {
+		return nil, fmt.Errorf("导出操作日志失败: %w", err)
+	}
+	return logs, nil
+}
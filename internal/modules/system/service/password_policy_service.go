@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/internal/shared/utils"
+	"github.com/LiteMove/light-stack/pkg/logger"
+)
+
+// PasswordPolicyService 密码策略服务接口，所有设置密码的地方统一通过该服务校验
+type PasswordPolicyService interface {
+	// 获取租户密码策略
+	GetPolicy(tenantID uint64) *model.PasswordPolicyConfig
+	// 校验新密码，user为nil表示新建用户（不检查历史密码）
+	ValidateNewPassword(tenantID uint64, user *model.User, password string) error
+	// 检查用户登录时是否必须修改密码（管理员重置或密码已过期）
+	IsChangeRequired(user *model.User) bool
+}
+
+// passwordPolicyService 密码策略服务实现
+type passwordPolicyService struct {
+	userRepo   repository2.UserRepository
+	tenantRepo repository2.TenantRepository
+}
+
+// NewPasswordPolicyService 创建密码策略服务
+func NewPasswordPolicyService(userRepo repository2.UserRepository, tenantRepo repository2.TenantRepository) PasswordPolicyService {
+	return &passwordPolicyService{
+		userRepo:   userRepo,
+		tenantRepo: tenantRepo,
+	}
+}
+
+// GetPolicy 获取租户密码策略，读取失败时使用默认策略
+func (s *passwordPolicyService) GetPolicy(tenantID uint64) *model.PasswordPolicyConfig {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err == nil {
+		if policy, err := tenant.GetPasswordPolicyConfig(); err == nil {
+			return policy
+		}
+	}
+	logger.WithField("tenantId", tenantID).Warn("Failed to load tenant password policy, using defaults")
+	return model.DefaultPasswordPolicyConfig()
+}
+
+// ValidateNewPassword 校验新密码是否满足租户密码策略，并检查是否与近期使用过的密码相同
+func (s *passwordPolicyService) ValidateNewPassword(tenantID uint64, user *model.User, password string) error {
+	policy := s.GetPolicy(tenantID)
+	if err := policy.CheckPassword(password); err != nil {
+		return err
+	}
+
+	if user == nil || policy.HistoryCount <= 0 {
+		return nil
+	}
+
+	// 当前密码始终视为历史密码，兼容启用策略前未记录历史的用户
+	if utils.VerifyPassword(user.Password, password) {
+		return fmt.Errorf("新密码不能与最近%d次使用过的密码相同", policy.HistoryCount)
+	}
+
+	hashes, err := s.userRepo.GetPasswordHistory(user.ID, policy.HistoryCount)
+	if err != nil {
+		logger.WithField("userId", user.ID).Error("Failed to load password history:", err)
+		return errors.New("校验历史密码失败")
+	}
+	for _, hash := range hashes {
+		if utils.VerifyPassword(hash, password) {
+			return fmt.Errorf("新密码不能与最近%d次使用过的密码相同", policy.HistoryCount)
+		}
+	}
+
+	return nil
+}
+
+// IsChangeRequired 检查用户登录时是否必须修改密码
+func (s *passwordPolicyService) IsChangeRequired(user *model.User) bool {
+	if user.MustChangePassword {
+		return true
+	}
+
+	// 未记录修改时间的用户以创建时间计算密码有效期
+	changedAt := user.PasswordChangedAt
+	if changedAt == nil {
+		changedAt = &user.CreatedAt
+	}
+	return s.GetPolicy(user.TenantID).IsExpired(changedAt)
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/internal/modules/system/repository"
+)
+
+// PostService 岗位服务接口
+type PostService interface {
+	CreatePost(ctx context.Context, post *model.Post) error
+	UpdatePost(ctx context.Context, post *model.Post) error
+	DeletePost(ctx context.Context, tenantID, id uint64) error
+	GetPost(ctx context.Context, tenantID, id uint64) (*model.PostProfile, error)
+	GetPostList(ctx context.Context, tenantID uint64, page, pageSize int, keyword string, status int) ([]model.PostProfile, int64, error)
+	GetEnabledPosts(ctx context.Context, tenantID uint64) ([]model.PostProfile, error)
+
+	// ImportFromDict 将字典数据导入为岗位（字典值为岗位编码，标签为岗位名称），已存在的编码跳过
+	ImportFromDict(ctx context.Context, tenantID uint64, dictType string) (*PostImportResult, error)
+
+	// 用户岗位
+	GetUserPosts(ctx context.Context, tenantID, userID uint64) ([]model.PostProfile, error)
+	AssignUserPosts(ctx context.Context, tenantID, userID uint64, postIDs []uint64) error
+}
+
+// PostImportResult 岗位导入结果
+type PostImportResult struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+}
+
+// postService 岗位服务实现
+type postService struct {
+	postRepo repository.PostRepository
+	userRepo repository.UserRepository
+	dictRepo repository.DictRepository
+}
+
+// NewPostService 创建岗位服务
+func NewPostService(postRepo repository.PostRepository, userRepo repository.UserRepository, dictRepo repository.DictRepository) PostService {
+	return &postService{
+		postRepo: postRepo,
+		userRepo: userRepo,
+		dictRepo: dictRepo,
+	}
+}
+
+// CreatePost 创建岗位
+func (s *postService) CreatePost(ctx context.Context, post *model.Post) error {
+	postRepo := s.postRepo.WithContext(ctx)
+
+	exists, err := postRepo.CodeExists(post.TenantID, post.Code, 0)
+	if err != nil {
+		return fmt.Errorf("检查岗位编码失败: %w", err)
+	}
+	if exists {
+		return errors.New("岗位编码已存在")
+	}
+
+	if err := postRepo.Create(post); err != nil {
+		return fmt.Errorf("创建岗位失败: %w", err)
+	}
+	return nil
+}
+
+// UpdatePost 更新岗位
+func (s *postService) UpdatePost(ctx context.Context, post *model.Post) error {
+	postRepo := s.postRepo.WithContext(ctx)
+
+	existing, err := postRepo.GetByID(post.TenantID, post.ID)
+	if err != nil {
+		return errors.New("岗位不存在")
+	}
+
+	exists, err := postRepo.CodeExists(post.TenantID, post.Code, post.ID)
+	if err != nil {
+		return fmt.Errorf("检查岗位编码失败: %w", err)
+	}
+	if exists {
+		return errors.New("岗位编码已存在")
+	}
+
+	existing.Code = post.Code
+	existing.Name = post.Name
+	existing.SortOrder = post.SortOrder
+	existing.Status = post.Status
+	existing.Remark = post.Remark
+	if err := postRepo.Update(existing); err != nil {
+		return fmt.Errorf("更新岗位失败: %w", err)
+	}
+	return nil
+}
+
+// DeletePost 删除岗位
+func (s *postService) DeletePost(ctx context.Context, tenantID, id uint64) error {
+	postRepo := s.postRepo.WithContext(ctx)
+
+	if _, err := postRepo.GetByID(tenantID, id); err != nil {
+		return errors.New("岗位不存在")
+	}
+
+	count, err := postRepo.GetUserCount(id)
+	if err != nil {
+		return fmt.Errorf("检查岗位使用情况失败: %w", err)
+	}
+	if count > 0 {
+		return errors.New("该岗位还有用户，无法删除")
+	}
+
+	if err := postRepo.Delete(id); err != nil {
+		return fmt.Errorf("删除岗位失败: %w", err)
+	}
+	return nil
+}
+
+// GetPost 获取岗位详情
+func (s *postService) GetPost(ctx context.Context, tenantID, id uint64) (*model.PostProfile, error) {
+	post, err := s.postRepo.WithContext(ctx).GetByID(tenantID, id)
+	if err != nil {
+		return nil, errors.New("岗位不存在")
+	}
+
+	profile := post.ToProfile()
+	return &profile, nil
+}
+
+// GetPostList 获取岗位列表
+func (s *postService) GetPostList(ctx context.Context, tenantID uint64, page, pageSize int, keyword string, status int) ([]model.PostProfile, int64, error) {
+	posts, total, err := s.postRepo.WithContext(ctx).GetList(tenantID, page, pageSize, keyword, status)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取岗位列表失败: %w", err)
+	}
+
+	return toPostProfiles(posts), total, nil
+}
+
+// GetEnabledPosts 获取启用的岗位
+func (s *postService) GetEnabledPosts(ctx context.Context, tenantID uint64) ([]model.PostProfile, error) {
+	posts, err := s.postRepo.WithContext(ctx).GetEnabled(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("获取岗位列表失败: %w", err)
+	}
+
+	return toPostProfiles(posts), nil
+}
+
+// ImportFromDict 将字典数据导入为岗位
+func (s *postService) ImportFromDict(ctx context.Context, tenantID uint64, dictType string) (*PostImportResult, error) {
+	postRepo := s.postRepo.WithContext(ctx)
+
+	if _, err := s.dictRepo.GetTypeByType(dictType); err != nil {
+		return nil, errors.New("字典类型不存在")
+	}
+
+	items, err := s.dictRepo.GetEnabledDataByType(dictType)
+	if err != nil {
+		return nil, fmt.Errorf("获取字典数据失败: %w", err)
+	}
+
+	codes, err := postRepo.GetCodes(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("获取岗位编码失败: %w", err)
+	}
+	existing := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		existing[code] = true
+	}
+
+	result := &PostImportResult{}
+	posts := make([]*model.Post, 0, len(items))
+	for _, item := range items {
+		if existing[item.Value] {
+			result.Skipped++
+			continue
+		}
+		existing[item.Value] = true
+
+		post := &model.Post{
+			Code:      item.Value,
+			Name:      item.Label,
+			SortOrder: item.SortOrder,
+			Status:    model.PostStatusEnabled,
+			Remark:    item.Remark,
+		}
+		post.TenantID = tenantID
+		posts = append(posts, post)
+	}
+
+	if err := postRepo.BatchCreate(posts); err != nil {
+		return nil, fmt.Errorf("导入岗位失败: %w", err)
+	}
+	result.Created = len(posts)
+	return result, nil
+}
+
+// GetUserPosts 获取用户岗位
+func (s *postService) GetUserPosts(ctx context.Context, tenantID, userID uint64) ([]model.PostProfile, error) {
+	if err := s.checkUser(ctx, tenantID, userID); err != nil {
+		return nil, err
+	}
+
+	posts, err := s.postRepo.WithContext(ctx).GetUserPosts(userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户岗位失败: %w", err)
+	}
+	return toPostProfiles(posts), nil
+}
+
+// AssignUserPosts 为用户分配岗位（覆盖原有岗位）
+func (s *postService) AssignUserPosts(ctx context.Context, tenantID, userID uint64, postIDs []uint64) error {
+	postRepo := s.postRepo.WithContext(ctx)
+
+	if err := s.checkUser(ctx, tenantID, userID); err != nil {
+		return err
+	}
+
+	// 去重并检查岗位是否属于本租户
+	seen := make(map[uint64]bool, len(postIDs))
+	uniqueIDs := make([]uint64, 0, len(postIDs))
+	for _, postID := range postIDs {
+		if !seen[postID] {
+			seen[postID] = true
+			uniqueIDs = append(uniqueIDs, postID)
+		}
+	}
+	if len(uniqueIDs) > 0 {
+		count, err := postRepo.CountByIDs(tenantID, uniqueIDs)
+		if err != nil {
+			return fmt.Errorf("检查岗位失败: %w", err)
+		}
+		if count != int64(len(uniqueIDs)) {
+			return errors.New("岗位不存在")
+		}
+	}
+
+	if err := postRepo.UpdateUserPosts(userID, uniqueIDs); err != nil {
+		return fmt.Errorf("分配用户岗位失败: %w", err)
+	}
+	return nil
+}
+
+// checkUser 检查用户是否属于本租户
+func (s *postService) checkUser(ctx context.Context, tenantID, userID uint64) error {
+	user, err := s.userRepo.WithContext(ctx).GetByID(userID)
+	if err != nil || user.TenantID != tenantID {
+		return errors.New("用户不存在")
+	}
+	return nil
+}
+
+// toPostProfiles 转换为岗位资料列表
+func toPostProfiles(posts []*model.Post) []model.PostProfile {
+	profiles := make([]model.PostProfile, len(posts))
+	for i, post := range posts {
+		profiles[i] = post.ToProfile()
+	}
+	return profiles
+}
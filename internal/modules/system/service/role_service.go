@@ -1,11 +1,16 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+
 	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
 
 	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
 	"github.com/LiteMove/light-stack/pkg/logger"
+	"github.com/LiteMove/light-stack/pkg/permission"
 )
 
 // roleService 角色服务实现
@@ -22,13 +27,60 @@ func NewRoleService(roleRepo repository2.RoleRepository, userRepo repository2.Us
 	}
 }
 
+// RoleScope 角色操作范围
+type RoleScope struct {
+	TenantID     uint64 // 当前租户ID
+	IsSuperAdmin bool   // 超级管理员可维护系统角色
+}
+
+// canManage 是否可修改角色：租户只能修改本租户角色，系统角色仅超级管理员可修改
+func (s RoleScope) canManage(role *model.Role) bool {
+	if role.IsSystem || role.TenantID == model.SystemRoleTenantID {
+		return s.IsSuperAdmin
+	}
+	return role.TenantID == s.TenantID
+}
+
+// checkDataScope 校验数据权限范围，全部数据权限仅超级管理员可设置
+func (s RoleScope) checkDataScope(dataScope sharedModel.DataScope) error {
+	if dataScope == sharedModel.DataScopeAll && !s.IsSuperAdmin {
+		return errors.New("仅超级管理员可设置全部数据权限")
+	}
+	return nil
+}
+
+// CheckAssignRoles 校验为用户分配的角色：角色须对用户所属租户可见，
+// 新增的超级管理员角色和系统角色仅超级管理员可分配，用户已拥有的角色不受限制
+func CheckAssignRoles(roleRepo repository2.RoleRepository, user *model.User, roleIDs []uint64, isSuperAdmin bool) error {
+	current, err := roleRepo.GetUserRoles(user.ID)
+	if err != nil {
+		return fmt.Errorf("获取用户角色失败: %w", err)
+	}
+	owned := make(map[uint64]bool, len(current))
+	for _, role := range current {
+		owned[role.ID] = true
+	}
+
+	for _, roleID := range roleIDs {
+		role, err := roleRepo.GetByID(roleID)
+		if err != nil || !role.VisibleTo(user.TenantID) {
+			return fmt.Errorf("角色ID %d 不存在", roleID)
+		}
+		if role.Privileged() && !owned[role.ID] && !isSuperAdmin {
+			return fmt.Errorf("无权分配角色: %s", role.Name)
+		}
+	}
+	return nil
+}
+
 // CreateRoleRequest 创建角色请求
 type CreateRoleRequest struct {
-	TenantID    uint64 `json:"tenant_id"`
+	IsSystem    bool   `json:"isSystem"` // 仅超级管理员可创建系统角色
 	Name        string `json:"name" validate:"required,min=1,max=100"`
 	Code        string `json:"code" validate:"required,min=1,max=50"`
 	Description string `json:"description" validate:"max=255"`
 	SortOrder   int    `json:"sortOrder"`
+	DataScope   int    `json:"dataScope" validate:"omitempty,oneof=1 2 3 4"` // 默认本租户数据
 }
 
 // UpdateRoleRequest 更新角色请求
@@ -37,34 +89,49 @@ type UpdateRoleRequest struct {
 	Description string `json:"description" validate:"max=255"`
 	Status      int    `json:"status" validate:"oneof=1 2"`
 	SortOrder   int    `json:"sortOrder"`
+	DataScope   int    `json:"dataScope" validate:"omitempty,oneof=1 2 3 4"` // 为空时保持不变
 }
 
 // RoleService 角色服务
 type RoleService interface {
 	// 创建角色
-	Create(req *CreateRoleRequest) (*model.RoleProfile, error)
+	Create(ctx context.Context, scope RoleScope, req *CreateRoleRequest) (*model.RoleProfile, error)
 	// 更新角色
-	Update(id uint64, req *UpdateRoleRequest) (*model.RoleProfile, error)
+	Update(ctx context.Context, scope RoleScope, id uint64, req *UpdateRoleRequest) (*model.RoleProfile, error)
 	// 删除角色
-	Delete(id uint64) error
+	Delete(ctx context.Context, scope RoleScope, id uint64) error
 	// 获取角色信息
-	GetByID(id uint64) (*model.RoleProfile, error)
+	GetByID(ctx context.Context, scope RoleScope, id uint64) (*model.RoleProfile, error)
 	// 获取角色列表
-	GetList(page, pageSize int, status int) ([]*model.Role, int64, error)
+	GetList(ctx context.Context, scope RoleScope, page, pageSize int, status int) ([]*model.Role, int64, error)
 	// 为用户分配角色
 	AssignRolesToUser(userID uint64, roleIDs []uint64) error
 	// 移除用户角色
 	RemoveUserRoles(userID uint64, roleIDs []uint64) error
 	// 获取所有启用的角色
-	GetEnabledRoles(isSuper bool) ([]*model.Role, error)
+	GetEnabledRoles(ctx context.Context, scope RoleScope) ([]*model.Role, error)
 }
 
 // 角色服务实现
 
 // Create 创建角色
-func (s *roleService) Create(req *CreateRoleRequest) (*model.RoleProfile, error) {
+func (s *roleService) Create(ctx context.Context, scope RoleScope, req *CreateRoleRequest) (*model.RoleProfile, error) {
+	roleRepo := s.roleRepo.WithContext(ctx)
+
+	if req.IsSystem && !scope.IsSuperAdmin {
+		return nil, errors.New("仅超级管理员可创建系统角色")
+	}
+	if err := scope.checkDataScope(sharedModel.DataScope(req.DataScope)); err != nil {
+		return nil, err
+	}
+
+	tenantID := scope.TenantID
+	if req.IsSystem {
+		tenantID = model.SystemRoleTenantID
+	}
+
 	// 检查角色编码是否已存在
-	exists, err := s.roleRepo.CodeExists(req.Code)
+	exists, err := roleRepo.CodeExists(tenantID, req.Code)
 	if err != nil {
 		logger.Error("Failed to check role code existence:", err)
 		return nil, errors.New("创建失败")
@@ -79,10 +146,13 @@ func (s *roleService) Create(req *CreateRoleRequest) (*model.RoleProfile, error)
 		Code:        req.Code,
 		Description: req.Description,
 		Status:      1, // 默认启用
+		IsSystem:    req.IsSystem,
 		SortOrder:   req.SortOrder,
+		DataScope:   sharedModel.DataScope(req.DataScope),
 	}
+	role.TenantID = tenantID
 
-	if err := s.roleRepo.Create(role); err != nil {
+	if err := roleRepo.Create(role); err != nil {
 		logger.Error("Failed to create role:", err)
 		return nil, errors.New("创建失败")
 	}
@@ -93,10 +163,18 @@ func (s *roleService) Create(req *CreateRoleRequest) (*model.RoleProfile, error)
 }
 
 // Update 更新角色
-func (s *roleService) Update(id uint64, req *UpdateRoleRequest) (*model.RoleProfile, error) {
-	role, err := s.roleRepo.GetByID(id)
+func (s *roleService) Update(ctx context.Context, scope RoleScope, id uint64, req *UpdateRoleRequest) (*model.RoleProfile, error) {
+	roleRepo := s.roleRepo.WithContext(ctx)
+
+	role, err := getManageableRole(roleRepo, scope, id)
 	if err != nil {
-		return nil, errors.New("角色不存在")
+		return nil, err
+	}
+	if req.DataScope != 0 && sharedModel.DataScope(req.DataScope) != role.DataScope {
+		if err := scope.checkDataScope(sharedModel.DataScope(req.DataScope)); err != nil {
+			return nil, err
+		}
+		role.DataScope = sharedModel.DataScope(req.DataScope)
 	}
 
 	// 更新角色信息
@@ -105,20 +183,29 @@ func (s *roleService) Update(id uint64, req *UpdateRoleRequest) (*model.RoleProf
 	role.Status = req.Status
 	role.SortOrder = req.SortOrder
 
-	if err := s.roleRepo.Update(role); err != nil {
+	if err := roleRepo.Update(role); err != nil {
 		logger.WithField("roleId", id).Error("Failed to update role:", err)
 		return nil, errors.New("更新失败")
 	}
 
+	// 角色状态或数据权限可能变化，拥有该角色的用户权限缓存失效
+	permission.InvalidateRoleUsers(id)
+
 	logger.WithField("roleId", id).Info("Role updated successfully")
 	profile := role.ToProfile()
 	return &profile, nil
 }
 
 // Delete 删除角色
-func (s *roleService) Delete(id uint64) error {
+func (s *roleService) Delete(ctx context.Context, scope RoleScope, id uint64) error {
+	roleRepo := s.roleRepo.WithContext(ctx)
+
+	if _, err := getManageableRole(roleRepo, scope, id); err != nil {
+		return err
+	}
+
 	// 检查角色是否还有用户在使用
-	count, err := s.roleRepo.GetRoleUserCount(id)
+	count, err := roleRepo.GetRoleUserCount(id)
 	if err != nil {
 		return errors.New("删除失败")
 	}
@@ -126,7 +213,7 @@ func (s *roleService) Delete(id uint64) error {
 		return errors.New("该角色还有用户在使用，无法删除")
 	}
 
-	if err := s.roleRepo.Delete(id); err != nil {
+	if err := roleRepo.Delete(id); err != nil {
 		logger.WithField("roleId", id).Error("Failed to delete role:", err)
 		return errors.New("删除失败")
 	}
@@ -136,9 +223,9 @@ func (s *roleService) Delete(id uint64) error {
 }
 
 // GetByID 获取角色信息
-func (s *roleService) GetByID(id uint64) (*model.RoleProfile, error) {
-	role, err := s.roleRepo.GetByID(id)
-	if err != nil {
+func (s *roleService) GetByID(ctx context.Context, scope RoleScope, id uint64) (*model.RoleProfile, error) {
+	role, err := s.roleRepo.WithContext(ctx).GetByID(id)
+	if err != nil || !role.VisibleTo(scope.TenantID) {
 		return nil, errors.New("角色不存在")
 	}
 
@@ -147,21 +234,43 @@ func (s *roleService) GetByID(id uint64) (*model.RoleProfile, error) {
 }
 
 // GetList 获取角色列表
-func (s *roleService) GetList(page, pageSize int, status int) ([]*model.Role, int64, error) {
-	return s.roleRepo.GetList(page, pageSize, status)
+func (s *roleService) GetList(ctx context.Context, scope RoleScope, page, pageSize int, status int) ([]*model.Role, int64, error) {
+	return s.roleRepo.WithContext(ctx).GetList(scope.TenantID, page, pageSize, status)
 }
 
 // AssignRolesToUser 为用户分配角色
 func (s *roleService) AssignRolesToUser(userID uint64, roleIDs []uint64) error {
-	return s.roleRepo.UpdateUserRoles(userID, roleIDs)
+	if err := s.roleRepo.UpdateUserRoles(userID, roleIDs); err != nil {
+		return err
+	}
+
+	permission.InvalidateUsers(userID)
+	return nil
 }
 
 // RemoveUserRoles 移除用户角色
 func (s *roleService) RemoveUserRoles(userID uint64, roleIDs []uint64) error {
-	return s.roleRepo.RemoveUserRoles(userID, roleIDs)
+	if err := s.roleRepo.RemoveUserRoles(userID, roleIDs); err != nil {
+		return err
+	}
+
+	permission.InvalidateUsers(userID)
+	return nil
 }
 
 // GetEnabledRoles 获取所有启用的角色
-func (s *roleService) GetEnabledRoles(isSuper bool) ([]*model.Role, error) {
-	return s.roleRepo.GetEnabledRoles(isSuper)
+func (s *roleService) GetEnabledRoles(ctx context.Context, scope RoleScope) ([]*model.Role, error) {
+	return s.roleRepo.WithContext(ctx).GetEnabledRoles(scope.TenantID, scope.IsSuperAdmin)
+}
+
+// getManageableRole 获取当前范围内可修改的角色，不可见或无权修改时返回错误
+func getManageableRole(roleRepo repository2.RoleRepository, scope RoleScope, id uint64) (*model.Role, error) {
+	role, err := roleRepo.GetByID(id)
+	if err != nil || !role.VisibleTo(scope.TenantID) {
+		return nil, errors.New("角色不存在")
+	}
+	if !scope.canManage(role) {
+		return nil, errors.New("系统角色或其他租户的角色不可修改")
+	}
+	return role, nil
 }
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+)
+
+// tenantCacheTTL 租户状态进程内缓存有效期，超时后重新从数据库加载，
+// 用于兜底其他实例修改租户状态后本实例无法感知的情况
+const tenantCacheTTL = time.Minute
+
+// tenantCache 租户进程内缓存，供每个请求的租户状态校验使用
+type tenantCache struct {
+	sync.RWMutex
+	byID     map[uint64]*tenantCacheEntry
+	byDomain map[string]uint64
+}
+
+// tenantCacheEntry 缓存的租户信息
+type tenantCacheEntry struct {
+	tenant   model.Tenant
+	loadedAt time.Time
+}
+
+// newTenantCache 创建租户缓存
+func newTenantCache() *tenantCache {
+	return &tenantCache{
+		byID:     make(map[uint64]*tenantCacheEntry),
+		byDomain: make(map[string]uint64),
+	}
+}
+
+// get 根据ID获取未过期的租户副本
+func (c *tenantCache) get(id uint64) (*model.Tenant, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	entry, exists := c.byID[id]
+	if !exists || time.Since(entry.loadedAt) >= tenantCacheTTL {
+		return nil, false
+	}
+	tenant := entry.tenant
+	return &tenant, true
+}
+
+// getByDomain 根据域名获取未过期的租户副本
+func (c *tenantCache) getByDomain(domain string) (*model.Tenant, bool) {
+	c.RLock()
+	id, exists := c.byDomain[domain]
+	c.RUnlock()
+	if !exists {
+		return nil, false
+	}
+	tenant, ok := c.get(id)
+	if !ok || tenant.Domain != domain {
+		return nil, false
+	}
+	return tenant, true
+}
+
+// set 写入租户缓存
+func (c *tenantCache) set(tenant *model.Tenant) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.byID[tenant.ID] = &tenantCacheEntry{tenant: *tenant, loadedAt: time.Now()}
+	if tenant.Domain != "" {
+		c.byDomain[tenant.Domain] = tenant.ID
+	}
+}
+
+// invalidate 清除指定租户的缓存
+func (c *tenantCache) invalidate(ids ...uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, id := range ids {
+		if entry, exists := c.byID[id]; exists {
+			delete(c.byDomain, entry.tenant.Domain)
+		}
+		delete(c.byID, id)
+	}
+}
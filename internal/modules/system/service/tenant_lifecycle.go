@@ -0,0 +1,269 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/internal/shared/config"
+	"github.com/LiteMove/light-stack/internal/shared/utils"
+	"github.com/LiteMove/light-stack/pkg/logger"
+	"github.com/LiteMove/light-stack/pkg/permission"
+)
+
+// tenantAdminRoleCode 租户管理员共享系统角色编码
+const tenantAdminRoleCode = "tenant_admin"
+
+// defaultTenantAdminUsername 未指定时租户首个管理员的用户名
+const defaultTenantAdminUsername = "admin"
+
+// TenantProvisionRequest 租户开通请求
+type TenantProvisionRequest struct {
+	Tenant            *model.Tenant
+	AdminUsername     string
+	AdminNickname     string
+	AdminEmail        string
+	TemplateTenantID  uint64 // 角色模板租户，为0时使用系统租户
+	LocalAccessDomain string // 本地存储访问域名，为空时沿用系统租户的配置
+}
+
+// TenantProvisionResult 租户开通结果，临时密码仅在此返回一次
+type TenantProvisionResult struct {
+	TenantID          uint64 `json:"tenantId"`
+	AdminUserID       uint64 `json:"adminUserId"`
+	AdminUsername     string `json:"adminUsername"`
+	TemporaryPassword string `json:"temporaryPassword"`
+	RoleCount         int    `json:"roleCount"`
+}
+
+// TenantOffboardResult 租户下线结果
+type TenantOffboardResult struct {
+	Mode       string `json:"mode"`
+	ExportFile string `json:"exportFile"` // 归档文件在服务器上的路径
+}
+
+// ProvisionTenant 开通租户：创建租户、默认配置、模板角色和首个管理员
+func (s *tenantService) ProvisionTenant(req *TenantProvisionRequest) (*TenantProvisionResult, error) {
+	tenant := req.Tenant
+	if err := s.checkNewTenant(tenant); err != nil {
+		return nil, err
+	}
+	if tenant.Status == 0 {
+		tenant.Status = model.TenantStatusActive
+	}
+
+	if err := s.applyDefaultConfig(tenant, req.LocalAccessDomain); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.buildTemplateRoles(tenant, req.TemplateTenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	adminRole, err := s.roleRepo.GetByCode(0, tenantAdminRoleCode)
+	if err != nil {
+		return nil, errors.New("租户管理员角色不存在")
+	}
+
+	// 生成满足新租户密码策略的一次性密码，首次登录时必须修改
+	policy, err := tenant.GetPasswordPolicyConfig()
+	if err != nil {
+		return nil, fmt.Errorf("解析密码策略失败: %w", err)
+	}
+	temporaryPassword, err := newTemporaryPassword(policy)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := utils.HashPassword(temporaryPassword)
+	if err != nil {
+		return nil, fmt.Errorf("密码加密失败: %w", err)
+	}
+
+	now := time.Now()
+	admin := &model.User{
+		Username:           req.AdminUsername,
+		Password:           hashedPassword,
+		Nickname:           req.AdminNickname,
+		Status:             1,
+		PasswordChangedAt:  &now,
+		MustChangePassword: true,
+	}
+	if admin.Username == "" {
+		admin.Username = defaultTenantAdminUsername
+	}
+	if admin.Nickname == "" {
+		admin.Nickname = tenant.Name + "管理员"
+	}
+	if req.AdminEmail != "" {
+		admin.Email = &req.AdminEmail
+	}
+
+	provision := &model.TenantProvision{
+		Tenant:       tenant,
+		Roles:        roles,
+		Admin:        admin,
+		AdminRoleIDs: []uint64{adminRole.ID},
+	}
+	if err := s.tenantRepo.Provision(provision); err != nil {
+		return nil, fmt.Errorf("开通租户失败: %w", err)
+	}
+
+	logger.WithField("tenantId", tenant.ID).Info("Tenant provisioned")
+	return &TenantProvisionResult{
+		TenantID:          tenant.ID,
+		AdminUserID:       admin.ID,
+		AdminUsername:     admin.Username,
+		TemporaryPassword: temporaryPassword,
+		RoleCount:         len(roles),
+	}, nil
+}
+
+// applyDefaultConfig 为新租户补全默认配置，本地存储访问域名未指定时沿用系统租户的配置
+func (s *tenantService) applyDefaultConfig(tenant *model.Tenant, localAccessDomain string) error {
+	config, err := tenant.GetConfig()
+	if err != nil {
+		return fmt.Errorf("解析租户配置失败: %w", err)
+	}
+
+	config.FileStorage.ApplyDefaults()
+	if localAccessDomain != "" {
+		config.FileStorage.LocalAccessDomain = localAccessDomain
+	}
+	if config.FileStorage.LocalAccessDomain == "" {
+		if systemTenant, err := s.tenantRepo.GetByID(model.SystemTenantId); err == nil {
+			if storage, err := systemTenant.GetFileStorageConfig(); err == nil {
+				config.FileStorage.LocalAccessDomain = storage.LocalAccessDomain
+			}
+		}
+	}
+	if config.SystemName == "" {
+		config.SystemName = tenant.Name
+	}
+
+	if err := s.validateTenantConfig(config); err != nil {
+		return fmt.Errorf("配置验证失败: %w", err)
+	}
+	if err := tenant.SetConfig(config); err != nil {
+		return fmt.Errorf("设置租户配置失败: %w", err)
+	}
+	return nil
+}
+
+// buildTemplateRoles 复制模板租户的自有角色，菜单权限限定在新租户套餐范围内
+func (s *tenantService) buildTemplateRoles(tenant *model.Tenant, templateTenantID uint64) ([]model.ProvisionRole, error) {
+	if templateTenantID == 0 {
+		templateTenantID = model.SystemTenantId
+	}
+	templates, err := s.roleRepo.GetTemplateRoles(templateTenantID)
+	if err != nil {
+		return nil, fmt.Errorf("获取模板角色失败: %w", err)
+	}
+
+	var allowed map[uint64]bool
+	if tenant.PackageID != model.NoTenantPackage {
+		menuIDs, err := s.packageRepo.GetMenuIDs(tenant.PackageID)
+		if err != nil {
+			return nil, fmt.Errorf("获取套餐菜单失败: %w", err)
+		}
+		allowed = make(map[uint64]bool, len(menuIDs))
+		for _, menuID := range menuIDs {
+			allowed[menuID] = true
+		}
+	}
+
+	roles := make([]model.ProvisionRole, 0, len(templates))
+	for _, template := range templates {
+		role := &model.Role{
+			Name:        template.Name,
+			Code:        template.Code,
+			Description: template.Description,
+			Status:      template.Status,
+			SortOrder:   template.SortOrder,
+			DataScope:   template.DataScope,
+		}
+		menuIDs := make([]uint64, 0, len(template.Menus))
+		for _, menu := range template.Menus {
+			if allowed == nil || allowed[menu.ID] {
+				menuIDs = append(menuIDs, menu.ID)
+			}
+		}
+		roles = append(roles, model.ProvisionRole{Role: role, MenuIDs: menuIDs})
+	}
+	return roles, nil
+}
+
+// ExportTenant 导出租户数据
+func (s *tenantService) ExportTenant(id uint64) (*model.TenantExport, error) {
+	export, err := s.tenantRepo.Export(id)
+	if err != nil {
+		return nil, fmt.Errorf("导出租户数据失败: %w", err)
+	}
+	return export, nil
+}
+
+// OffboardTenant 下线租户：先将数据归档到服务器本地，再软删除或彻底删除租户
+func (s *tenantService) OffboardTenant(id uint64, mode string) (*TenantOffboardResult, error) {
+	if id == model.SystemTenantId {
+		return nil, errors.New("禁止下线系统租户")
+	}
+	if mode != model.TenantOffboardSoftDelete && mode != model.TenantOffboardPurge {
+		return nil, errors.New("不支持的下线方式")
+	}
+
+	export, err := s.ExportTenant(id)
+	if err != nil {
+		return nil, err
+	}
+	exportFile, err := writeTenantExport(export)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uint64, 0, len(export.Users))
+	for _, user := range export.Users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	if mode == model.TenantOffboardPurge {
+		err = s.tenantRepo.Purge(id)
+	} else {
+		err = s.tenantRepo.SoftDeleteWithUsers(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("下线租户失败: %w", err)
+	}
+	s.cache.invalidate(id)
+	permission.InvalidateUsers(userIDs...)
+
+	logger.WithField("tenantId", id).WithField("mode", mode).Info("Tenant offboarded")
+	return &TenantOffboardResult{Mode: mode, ExportFile: exportFile}, nil
+}
+
+// writeTenantExport 将租户导出数据写入本地存储的非公开目录
+func writeTenantExport(export *model.TenantExport) (string, error) {
+	localPath := "uploads"
+	if cfg := config.Get(); cfg != nil && cfg.File.LocalPath != "" {
+		localPath = cfg.File.LocalPath
+	}
+	dir := filepath.Join(localPath, "exports")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("创建导出目录失败: %w", err)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("序列化导出数据失败: %w", err)
+	}
+
+	fileName := fmt.Sprintf("tenant_%d_%s.json", export.Tenant.ID, export.ExportedAt.Format("20060102150405"))
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, data, 0o640); err != nil {
+		return "", fmt.Errorf("写入导出文件失败: %w", err)
+	}
+	return path, nil
+}
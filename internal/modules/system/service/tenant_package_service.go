@@ -0,0 +1,241 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	"github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/pkg/logger"
+	"github.com/LiteMove/light-stack/pkg/permission"
+)
+
+// TenantPackageService 租户套餐服务接口
+type TenantPackageService interface {
+	CreatePackage(pkg *model.TenantPackage, menuIDs []uint64) error
+	UpdatePackage(pkg *model.TenantPackage, menuIDs []uint64) error
+	DeletePackage(id uint64) error
+	GetPackage(id uint64) (*model.TenantPackageProfile, error)
+	GetPackageList(page, pageSize int, name string, status int) ([]model.TenantPackageProfile, int64, error)
+	GetEnabledPackages() ([]*model.TenantPackage, error)
+
+	// AssignTenantPackage 为租户分配套餐（packageID为0表示不限制），并裁剪租户角色中超出套餐的授权
+	AssignTenantPackage(tenantID, packageID uint64) error
+}
+
+// tenantPackageService 租户套餐服务实现
+type tenantPackageService struct {
+	packageRepo repository.TenantPackageRepository
+	tenantRepo  repository.TenantRepository
+	menuRepo    repository.MenuRepository
+}
+
+// NewTenantPackageService 创建租户套餐服务
+func NewTenantPackageService(packageRepo repository.TenantPackageRepository, tenantRepo repository.TenantRepository, menuRepo repository.MenuRepository) TenantPackageService {
+	return &tenantPackageService{
+		packageRepo: packageRepo,
+		tenantRepo:  tenantRepo,
+		menuRepo:    menuRepo,
+	}
+}
+
+// CreatePackage 创建套餐
+func (s *tenantPackageService) CreatePackage(pkg *model.TenantPackage, menuIDs []uint64) error {
+	exists, err := s.packageRepo.NameExists(pkg.Name, 0)
+	if err != nil {
+		return fmt.Errorf("检查套餐名称失败: %w", err)
+	}
+	if exists {
+		return errors.New("套餐名称已存在")
+	}
+
+	menuIDs, err = s.expandMenuIDs(menuIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := s.packageRepo.Create(pkg); err != nil {
+		return fmt.Errorf("创建套餐失败: %w", err)
+	}
+	if err := s.packageRepo.UpdateMenus(pkg.ID, menuIDs); err != nil {
+		return fmt.Errorf("保存套餐菜单失败: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePackage 更新套餐，菜单缩减时裁剪使用该套餐的租户角色授权
+func (s *tenantPackageService) UpdatePackage(pkg *model.TenantPackage, menuIDs []uint64) error {
+	exists, err := s.packageRepo.NameExists(pkg.Name, pkg.ID)
+	if err != nil {
+		return fmt.Errorf("检查套餐名称失败: %w", err)
+	}
+	if exists {
+		return errors.New("套餐名称已存在")
+	}
+
+	existing, err := s.packageRepo.GetByID(pkg.ID)
+	if err != nil {
+		return errors.New("套餐不存在")
+	}
+
+	menuIDs, err = s.expandMenuIDs(menuIDs)
+	if err != nil {
+		return err
+	}
+
+	existing.Name = pkg.Name
+	existing.Description = pkg.Description
+	existing.Status = pkg.Status
+	existing.SortOrder = pkg.SortOrder
+	if err := s.packageRepo.Update(existing); err != nil {
+		return fmt.Errorf("更新套餐失败: %w", err)
+	}
+	if err := s.packageRepo.UpdateMenus(pkg.ID, menuIDs); err != nil {
+		return fmt.Errorf("保存套餐菜单失败: %w", err)
+	}
+
+	trimmed, err := s.packageRepo.TrimPackageRoleMenus(pkg.ID)
+	if err != nil {
+		return fmt.Errorf("裁剪角色授权失败: %w", err)
+	}
+	logger.WithField("packageId", pkg.ID).Info("Tenant package updated, trimmed role menus: ", trimmed)
+
+	// 系统角色在读取时按套餐过滤，套餐变化影响所有使用该套餐的租户用户
+	permission.InvalidateAll()
+	return nil
+}
+
+// DeletePackage 删除套餐
+func (s *tenantPackageService) DeletePackage(id uint64) error {
+	if _, err := s.packageRepo.GetByID(id); err != nil {
+		return errors.New("套餐不存在")
+	}
+
+	count, err := s.packageRepo.GetTenantCount(id)
+	if err != nil {
+		return fmt.Errorf("检查套餐使用情况失败: %w", err)
+	}
+	if count > 0 {
+		return errors.New("该套餐还有租户在使用，无法删除")
+	}
+
+	if err := s.packageRepo.Delete(id); err != nil {
+		return fmt.Errorf("删除套餐失败: %w", err)
+	}
+	return nil
+}
+
+// GetPackage 获取套餐详情
+func (s *tenantPackageService) GetPackage(id uint64) (*model.TenantPackageProfile, error) {
+	pkg, err := s.packageRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("套餐不存在")
+	}
+
+	profile, err := s.toProfile(pkg)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetPackageList 获取套餐列表
+func (s *tenantPackageService) GetPackageList(page, pageSize int, name string, status int) ([]model.TenantPackageProfile, int64, error) {
+	packages, total, err := s.packageRepo.GetList(page, pageSize, name, status)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取套餐列表失败: %w", err)
+	}
+
+	profiles := make([]model.TenantPackageProfile, 0, len(packages))
+	for _, pkg := range packages {
+		profile, err := s.toProfile(pkg)
+		if err != nil {
+			return nil, 0, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, total, nil
+}
+
+// GetEnabledPackages 获取所有启用的套餐
+func (s *tenantPackageService) GetEnabledPackages() ([]*model.TenantPackage, error) {
+	return s.packageRepo.GetEnabled()
+}
+
+// AssignTenantPackage 为租户分配套餐
+func (s *tenantPackageService) AssignTenantPackage(tenantID, packageID uint64) error {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		return errors.New("租户不存在")
+	}
+
+	if packageID != model.NoTenantPackage {
+		pkg, err := s.packageRepo.GetByID(packageID)
+		if err != nil {
+			return errors.New("套餐不存在")
+		}
+		if pkg.Status != model.TenantPackageStatusEnabled {
+			return errors.New("套餐已禁用")
+		}
+	}
+
+	tenant.PackageID = packageID
+	if err := s.tenantRepo.Update(tenant); err != nil {
+		return fmt.Errorf("更新租户套餐失败: %w", err)
+	}
+
+	trimmed, err := s.packageRepo.TrimTenantRoleMenus(tenantID)
+	if err != nil {
+		return fmt.Errorf("裁剪角色授权失败: %w", err)
+	}
+	logger.WithField("tenantId", tenantID).Info("Tenant package changed, trimmed role menus: ", trimmed)
+
+	permission.InvalidateAll()
+	return nil
+}
+
+// expandMenuIDs 校验菜单并补全上级菜单，保证套餐内的菜单树完整
+func (s *tenantPackageService) expandMenuIDs(menuIDs []uint64) ([]uint64, error) {
+	menus, err := s.menuRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("获取菜单失败: %w", err)
+	}
+
+	parents := make(map[uint64]uint64, len(menus))
+	for _, menu := range menus {
+		parents[menu.ID] = menu.ParentID
+	}
+
+	selected := make(map[uint64]bool, len(menuIDs))
+	result := make([]uint64, 0, len(menuIDs))
+	for _, menuID := range menuIDs {
+		if _, ok := parents[menuID]; !ok {
+			return nil, fmt.Errorf("菜单ID %d 不存在", menuID)
+		}
+		for id := menuID; id != 0 && !selected[id]; id = parents[id] {
+			selected[id] = true
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// toProfile 转换为套餐资料，包含菜单和租户数量
+func (s *tenantPackageService) toProfile(pkg *model.TenantPackage) (model.TenantPackageProfile, error) {
+	profile := pkg.ToProfile()
+
+	menuIDs, err := s.packageRepo.GetMenuIDs(pkg.ID)
+	if err != nil {
+		return profile, fmt.Errorf("获取套餐菜单失败: %w", err)
+	}
+	if menuIDs != nil {
+		profile.MenuIDs = menuIDs
+	}
+
+	profile.TenantCount, err = s.packageRepo.GetTenantCount(pkg.ID)
+	if err != nil {
+		return profile, fmt.Errorf("获取套餐租户数量失败: %w", err)
+	}
+	return profile, nil
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/pkg/logger"
+	"regexp"
 	"time"
 
 	"github.com/LiteMove/light-stack/internal/modules/system/model"
@@ -13,10 +15,16 @@ import (
 type TenantService interface {
 	// 基础CRUD操作
 	CreateTenant(tenant *model.Tenant) error
+	// ProvisionTenant 开通租户：创建租户、默认配置、模板角色和首个管理员
+	ProvisionTenant(req *TenantProvisionRequest) (*TenantProvisionResult, error)
 	GetTenant(id uint64) (*model.Tenant, error)
 	GetTenantByDomain(domain string) (*model.Tenant, error)
 	UpdateTenant(tenant *model.Tenant) error
 	DeleteTenant(id uint64) error
+	// ExportTenant 导出租户数据
+	ExportTenant(id uint64) (*model.TenantExport, error)
+	// OffboardTenant 下线租户：先归档导出数据，再软删除或彻底删除租户
+	OffboardTenant(id uint64, mode string) (*TenantOffboardResult, error)
 
 	// 查询操作
 	GetTenantList(page, pageSize int, keyword string, status int) ([]*model.Tenant, int64, error)
@@ -29,6 +37,16 @@ type TenantService interface {
 
 	// 租户验证
 	ValidateTenant(domain string) (*model.Tenant, error)
+	// FindTenantIDByDomain 根据域名查找租户ID，不存在时返回0
+	FindTenantIDByDomain(domain string) (uint64, error)
+	// FindTenantIDByCode 根据租户编码查找租户ID，不存在时返回0
+	FindTenantIDByCode(code string) (uint64, error)
+	// CheckTenantStatus 校验租户是否可用（带进程内缓存），租户存在时即使校验失败也返回租户
+	CheckTenantStatus(id uint64) (*model.Tenant, error)
+	// ExpireTenants 将已到期的租户标记为过期，返回处理的租户数量
+	ExpireTenants() (int, error)
+	// StartExpiryJob 启动后台任务，定期将到期租户标记为过期
+	StartExpiryJob(interval time.Duration)
 	GetSelectList() ([]*model.Tenant, error)
 
 	// 配置操作
@@ -38,24 +56,56 @@ type TenantService interface {
 
 // tenantService 租户服务实现
 type tenantService struct {
-	tenantRepo repository2.TenantRepository
-	userRepo   repository2.UserRepository
+	tenantRepo  repository2.TenantRepository
+	userRepo    repository2.UserRepository
+	roleRepo    repository2.RoleRepository
+	packageRepo repository2.TenantPackageRepository
+	cache       *tenantCache
 }
 
+// 租户校验错误，供中间件映射为具体的业务错误码
+var (
+	ErrTenantNotFound = errors.New("租户不存在")
+	ErrTenantDisabled = errors.New("租户已被禁用")
+	ErrTenantExpired  = errors.New("租户已过期")
+)
+
 func (s *tenantService) GetSelectList() ([]*model.Tenant, error) {
 	return s.tenantRepo.GetSelectList()
 }
 
 // NewTenantService 创建租户服务
-func NewTenantService(tenantRepo repository2.TenantRepository, userRepo repository2.UserRepository) TenantService {
+func NewTenantService(tenantRepo repository2.TenantRepository, userRepo repository2.UserRepository, roleRepo repository2.RoleRepository, packageRepo repository2.TenantPackageRepository) TenantService {
 	return &tenantService{
-		tenantRepo: tenantRepo,
-		userRepo:   userRepo,
+		tenantRepo:  tenantRepo,
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		packageRepo: packageRepo,
+		cache:       newTenantCache(),
 	}
 }
 
 // CreateTenant 创建租户
 func (s *tenantService) CreateTenant(tenant *model.Tenant) error {
+	if err := s.checkNewTenant(tenant); err != nil {
+		return err
+	}
+
+	// 设置默认值
+	if tenant.Status == 0 {
+		tenant.Status = 1 // 默认启用
+	}
+
+	// 创建租户
+	if err := s.tenantRepo.Create(tenant); err != nil {
+		return fmt.Errorf("创建租户失败: %w", err)
+	}
+
+	return nil
+}
+
+// checkNewTenant 检查新租户的名称、域名和套餐是否可用
+func (s *tenantService) checkNewTenant(tenant *model.Tenant) error {
 	// 检查租户名称是否已存在
 	if tenant.Name != "" {
 		exists, err := s.tenantRepo.NameExists(tenant.Name)
@@ -78,19 +128,45 @@ func (s *tenantService) CreateTenant(tenant *model.Tenant) error {
 		}
 	}
 
-	// 设置默认值
-	if tenant.Status == 0 {
-		tenant.Status = 1 // 默认启用
+	// 检查租户编码是否已存在（如果提供了编码）
+	if tenant.Code != "" {
+		if err := s.checkCode(tenant.Code); err != nil {
+			return err
+		}
 	}
 
-	// 创建租户
-	if err := s.tenantRepo.Create(tenant); err != nil {
-		return fmt.Errorf("创建租户失败: %w", err)
+	// 检查套餐是否可用（如果指定了套餐）
+	if tenant.PackageID != model.NoTenantPackage {
+		pkg, err := s.packageRepo.GetByID(tenant.PackageID)
+		if err != nil {
+			return errors.New("套餐不存在")
+		}
+		if pkg.Status != model.TenantPackageStatusEnabled {
+			return errors.New("套餐已禁用")
+		}
 	}
 
 	return nil
 }
 
+// tenantCodePattern 租户编码格式，需可作为子域名和路径片段使用
+var tenantCodePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,48}[a-z0-9])?$`)
+
+// checkCode 检查租户编码格式及是否已被使用
+func (s *tenantService) checkCode(code string) error {
+	if !tenantCodePattern.MatchString(code) {
+		return errors.New("租户编码只能包含小写字母、数字和中划线，且不能以中划线开头或结尾")
+	}
+	exists, err := s.tenantRepo.CodeExists(code)
+	if err != nil {
+		return fmt.Errorf("检查租户编码是否存在失败: %w", err)
+	}
+	if exists {
+		return errors.New("租户编码已存在")
+	}
+	return nil
+}
+
 // GetTenant 获取租户
 func (s *tenantService) GetTenant(id uint64) (*model.Tenant, error) {
 	tenant, err := s.tenantRepo.GetByID(id)
@@ -139,10 +215,18 @@ func (s *tenantService) UpdateTenant(tenant *model.Tenant) error {
 		}
 	}
 
+	// 如果租户编码发生变化，检查新编码是否已存在
+	if tenant.Code != "" && tenant.Code != existingTenant.Code {
+		if err := s.checkCode(tenant.Code); err != nil {
+			return err
+		}
+	}
+
 	// 更新租户
 	if err := s.tenantRepo.Update(tenant); err != nil {
 		return fmt.Errorf("更新租户失败: %w", err)
 	}
+	s.cache.invalidate(tenant.ID)
 
 	return nil
 }
@@ -173,6 +257,7 @@ func (s *tenantService) DeleteTenant(id uint64) error {
 	if err := s.tenantRepo.Delete(id); err != nil {
 		return fmt.Errorf("删除租户失败: %w", err)
 	}
+	s.cache.invalidate(id)
 
 	return nil
 }
@@ -222,6 +307,7 @@ func (s *tenantService) UpdateTenantStatus(id uint64, status int) error {
 	if err := s.tenantRepo.UpdateStatus(id, status); err != nil {
 		return fmt.Errorf("更新租户状态失败: %w", err)
 	}
+	s.cache.invalidate(id)
 
 	return nil
 }
@@ -234,25 +320,91 @@ func (s *tenantService) CheckTenantExpired(tenant *model.Tenant) bool {
 	return tenant.ExpiredAt.Before(time.Now())
 }
 
-// ValidateTenant 验证租户
+// ValidateTenant 根据域名验证租户，租户存在时即使校验失败也返回租户
 func (s *tenantService) ValidateTenant(domain string) (*model.Tenant, error) {
-	// 根据域名获取租户
-	tenant, err := s.tenantRepo.GetByDomain(domain)
+	tenant, ok := s.cache.getByDomain(domain)
+	if !ok {
+		var err error
+		tenant, err = s.tenantRepo.GetByDomain(domain)
+		if err != nil {
+			return nil, ErrTenantNotFound
+		}
+		s.cache.set(tenant)
+	}
+
+	return tenant, checkTenantStatus(tenant)
+}
+
+// FindTenantIDByDomain 根据域名查找租户ID，不存在时返回0
+func (s *tenantService) FindTenantIDByDomain(domain string) (uint64, error) {
+	id, err := s.tenantRepo.FindIDByDomain(domain)
 	if err != nil {
-		return nil, errors.New("租户不存在")
+		return 0, fmt.Errorf("根据域名查找租户失败: %w", err)
 	}
+	return id, nil
+}
 
-	// 检查租户状态
-	if !tenant.IsActive() {
-		return nil, errors.New("租户已被禁用")
+// FindTenantIDByCode 根据租户编码查找租户ID，不存在时返回0
+func (s *tenantService) FindTenantIDByCode(code string) (uint64, error) {
+	id, err := s.tenantRepo.FindIDByCode(code)
+	if err != nil {
+		return 0, fmt.Errorf("根据编码查找租户失败: %w", err)
 	}
+	return id, nil
+}
 
-	// 检查租户是否过期
-	if tenant.IsExpired() {
-		return nil, errors.New("租户已过期")
+// CheckTenantStatus 校验租户是否可用
+func (s *tenantService) CheckTenantStatus(id uint64) (*model.Tenant, error) {
+	tenant, ok := s.cache.get(id)
+	if !ok {
+		var err error
+		tenant, err = s.tenantRepo.GetByID(id)
+		if err != nil {
+			return nil, ErrTenantNotFound
+		}
+		s.cache.set(tenant)
 	}
 
-	return tenant, nil
+	return tenant, checkTenantStatus(tenant)
+}
+
+// checkTenantStatus 检查租户状态，启用和试用状态的租户在到期前可用
+func checkTenantStatus(tenant *model.Tenant) error {
+	switch {
+	case tenant.Status == model.TenantStatusDisabled:
+		return ErrTenantDisabled
+	case tenant.Status == model.TenantStatusExpired || tenant.IsExpired():
+		return ErrTenantExpired
+	}
+	return nil
+}
+
+// ExpireTenants 将已到期的租户标记为过期
+func (s *tenantService) ExpireTenants() (int, error) {
+	ids, err := s.tenantRepo.ExpireOverdue(time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("更新过期租户失败: %w", err)
+	}
+	s.cache.invalidate(ids...)
+	return len(ids), nil
+}
+
+// StartExpiryJob 启动后台任务，定期将到期租户标记为过期
+func (s *tenantService) StartExpiryJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			count, err := s.ExpireTenants()
+			if err != nil {
+				logger.Error("Failed to expire overdue tenants:", err)
+			} else if count > 0 {
+				logger.WithField("count", count).Info("Expired overdue tenants")
+			}
+			<-ticker.C
+		}
+	}()
 }
 
 // GetTenantConfig 获取租户配置
@@ -285,6 +437,19 @@ func (s *tenantService) UpdateTenantConfig(id uint64, config *model.TenantConfig
 		return fmt.Errorf("配置验证失败: %w", err)
 	}
 
+	// 未提交安全策略和邮件配置时保持原有配置
+	if existing, err := tenant.GetConfig(); err == nil {
+		if config.Security == nil {
+			config.Security = existing.Security
+		}
+		if config.Mail == nil {
+			config.Mail = existing.Mail
+		}
+		if config.PasswordPolicy == nil {
+			config.PasswordPolicy = existing.PasswordPolicy
+		}
+	}
+
 	// 设置配置
 	if err := tenant.SetConfig(config); err != nil {
 		return fmt.Errorf("设置租户配置失败: %w", err)
@@ -294,6 +459,7 @@ func (s *tenantService) UpdateTenantConfig(id uint64, config *model.TenantConfig
 	if err := s.tenantRepo.Update(tenant); err != nil {
 		return fmt.Errorf("更新租户失败: %w", err)
 	}
+	s.cache.invalidate(id)
 
 	return nil
 }
@@ -312,12 +478,18 @@ func (s *tenantService) validateTenantConfig(config *model.TenantConfig) error {
 	if fileStorage.MaxFileSize <= 0 {
 		return errors.New("文件大小限制必须大于0")
 	}
+	if fileStorage.MaxChunkedFileSize < 0 {
+		return errors.New("分片上传文件大小限制不能小于0")
+	}
 
 	// 如果是OSS存储，验证OSS配置
 	if fileStorage.Type == "oss" {
 		if fileStorage.OSSProvider == "" {
 			return errors.New("OSS提供商不能为空")
 		}
+		if fileStorage.OSSEndpoint == "" && fileStorage.OSSProvider != "aws" {
+			return errors.New("OSS服务端点不能为空")
+		}
 		if fileStorage.OSSBucket == "" {
 			return errors.New("OSS存储桶不能为空")
 		}
@@ -329,5 +501,24 @@ func (s *tenantService) validateTenantConfig(config *model.TenantConfig) error {
 		}
 	}
 
+	// 验证安全策略配置
+	if config.Security != nil {
+		if err := config.Security.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// 验证密码策略配置
+	if config.PasswordPolicy != nil {
+		if err := config.PasswordPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// 验证邮件配置
+	if config.Mail != nil && config.Mail.SMTPHost != "" && config.Mail.FromAddress == "" && config.Mail.SMTPUsername == "" {
+		return errors.New("发件地址不能为空")
+	}
+
 	return nil
 }
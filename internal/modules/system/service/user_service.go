@@ -1,36 +1,50 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
 	"strings"
+	"time"
 
 	"github.com/LiteMove/light-stack/internal/modules/system/model"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
 	"github.com/LiteMove/light-stack/internal/shared/utils"
+	"github.com/LiteMove/light-stack/pkg/jwt"
+	"github.com/LiteMove/light-stack/pkg/logger"
+	"github.com/LiteMove/light-stack/pkg/permission"
 )
 
 // UserService 用户服务接口
 type UserService interface {
 	// 基础CRUD操作
-	CreateUser(user *model.User) error
-	GetUser(id uint64) (*model.User, error)
-	GetUserWithRoles(id uint64) (*model.User, error)
-	UpdateUser(user *model.User) error
-	DeleteUser(id uint64) error
+	// 创建用户，未设置密码时生成临时密码并返回
+	CreateUser(ctx context.Context, user *model.User) (string, error)
+	GetUser(ctx context.Context, id uint64) (*model.User, error)
+	GetUserWithRoles(ctx context.Context, id uint64) (*model.User, error)
+	UpdateUser(ctx context.Context, user *model.User) error
+	DeleteUser(ctx context.Context, id uint64) error
 
 	// 查询操作
-	GetUserList(tenantID uint64, page, pageSize int, keyword string, status int, roleID uint64) ([]*model.User, int64, error)
+	GetUserList(filter sharedModel.DataFilter, page, pageSize int, keyword string, status int, roleID uint64, deptID uint64) ([]*model.User, int64, error)
 	GetUserByUsername(tenantID uint64, username string) (*model.User, error)
 	GetUserByEmail(tenantID uint64, email string) (*model.User, error)
 
 	// 状态操作
-	UpdateUserStatus(id uint64, status int) error
-	BatchUpdateUserStatus(ids []uint64, status int) error
+	UpdateUserStatus(ctx context.Context, id uint64, status int) error
+	BatchUpdateUserStatus(ctx context.Context, ids []uint64, status int) error
+
+	// 会话管理
+	RevokeUserSessions(ctx context.Context, id uint64) error
+	// 解锁用户
+	UnlockUser(ctx context.Context, id uint64) error
+	// 重置用户二次验证
+	ResetUserMfa(ctx context.Context, id uint64) error
 
 	// 密码相关
 	ChangePassword(id uint64, oldPassword, newPassword string) error
-	ResetPassword(id uint64) (string, error)
+	ResetPassword(ctx context.Context, id uint64) (string, error)
 
 	// 用户验证
 	ValidateUser(tenantID uint64, username, password string) (*model.User, error)
@@ -38,69 +52,90 @@ type UserService interface {
 	CheckEmailExists(tenantID uint64, email string) (bool, error)
 
 	// 角色管理
-	AssignUserRoles(userID uint64, roleIDs []uint64) error
-	RemoveUserRoles(userID uint64, roleIDs []uint64) error
-	GetUserRoles(userID uint64) ([]*model.Role, error)
+	AssignUserRoles(ctx context.Context, userID uint64, roleIDs []uint64, isSuperAdmin bool) error
+	RemoveUserRoles(ctx context.Context, userID uint64, roleIDs []uint64) error
+	GetUserRoles(ctx context.Context, userID uint64) ([]*model.Role, error)
 }
 
 // userService 用户服务实现
 type userService struct {
-	userRepo repository2.UserRepository
-	roleRepo repository2.RoleRepository
+	userRepo       repository2.UserRepository
+	roleRepo       repository2.RoleRepository
+	deptRepo       repository2.DeptRepository
+	passwordPolicy PasswordPolicyService
 }
 
 // NewUserService 创建用户服务
-func NewUserService(userRepo repository2.UserRepository, roleRepo repository2.RoleRepository) UserService {
+func NewUserService(userRepo repository2.UserRepository, roleRepo repository2.RoleRepository, deptRepo repository2.DeptRepository, passwordPolicy PasswordPolicyService) UserService {
 	return &userService{
-		userRepo: userRepo,
-		roleRepo: roleRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		deptRepo:       deptRepo,
+		passwordPolicy: passwordPolicy,
 	}
 }
 
-// CreateUser 创建用户
-func (s *userService) CreateUser(user *model.User) error {
+// CreateUser 创建用户，未设置密码时生成临时密码并返回，用户首次登录时必须修改
+func (s *userService) CreateUser(ctx context.Context, user *model.User) (string, error) {
+	userRepo := s.userRepo.WithContext(ctx)
+
 	// 检查用户名是否已存在
-	exists, err := s.userRepo.UsernameExists(user.TenantID, user.Username)
+	exists, err := userRepo.UsernameExists(user.TenantID, user.Username)
 	if err != nil {
-		return fmt.Errorf("检查用户名是否存在失败: %w", err)
+		return "", fmt.Errorf("检查用户名是否存在失败: %w", err)
 	}
 	if exists {
-		return errors.New("用户名已存在")
+		return "", errors.New("用户名已存在")
 	}
 
 	// 检查邮箱是否已存在（如果提供了邮箱）
 	if user.Email != nil && *user.Email != "" {
-		exists, err := s.userRepo.EmailExists(user.TenantID, *user.Email)
+		exists, err := userRepo.EmailExists(user.TenantID, *user.Email)
 		if err != nil {
-			return fmt.Errorf("检查邮箱是否存在失败: %w", err)
+			return "", fmt.Errorf("检查邮箱是否存在失败: %w", err)
 		}
 		if exists {
-			return errors.New("邮箱已存在")
+			return "", errors.New("邮箱已存在")
 		}
 	}
 
 	// 检查手机号是否已存在（如果提供了手机号）
 	if user.Phone != nil && *user.Phone != "" {
-		exists, err := s.userRepo.PhoneExists(user.TenantID, *user.Phone)
+		exists, err := userRepo.PhoneExists(user.TenantID, *user.Phone)
 		if err != nil {
-			return fmt.Errorf("检查手机号是否存在失败: %w", err)
+			return "", fmt.Errorf("检查手机号是否存在失败: %w", err)
 		}
 		if exists {
-			return errors.New("手机号已存在")
+			return "", errors.New("手机号已存在")
 		}
 	}
 
-	// 如果没有设置密码，生成默认密码
+	// 检查部门是否属于本租户
+	if err := s.checkDept(ctx, user.TenantID, user.DeptID); err != nil {
+		return "", err
+	}
+
+	// 如果没有设置密码，生成临时密码
+	var temporaryPassword string
 	if user.Password == "" {
-		user.Password = "123456" // 默认密码
+		temporaryPassword, err = s.generateTemporaryPassword(user.TenantID)
+		if err != nil {
+			return "", err
+		}
+		user.Password = temporaryPassword
+		user.MustChangePassword = true
+	} else if err := s.passwordPolicy.ValidateNewPassword(user.TenantID, nil, user.Password); err != nil {
+		return "", err
 	}
 
 	// 加密密码
 	hashedPassword, err := utils.HashPassword(user.Password)
 	if err != nil {
-		return fmt.Errorf("密码加密失败: %w", err)
+		return "", fmt.Errorf("密码加密失败: %w", err)
 	}
 	user.Password = hashedPassword
+	now := time.Now()
+	user.PasswordChangedAt = &now
 
 	// 设置默认值
 	if user.Status == 0 {
@@ -108,16 +143,16 @@ func (s *userService) CreateUser(user *model.User) error {
 	}
 
 	// 创建用户
-	if err := s.userRepo.Create(user); err != nil {
-		return fmt.Errorf("创建用户失败: %w", err)
+	if err := userRepo.Create(user); err != nil {
+		return "", fmt.Errorf("创建用户失败: %w", err)
 	}
 
-	return nil
+	return temporaryPassword, nil
 }
 
 // GetUser 获取用户
-func (s *userService) GetUser(id uint64) (*model.User, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *userService) GetUser(ctx context.Context, id uint64) (*model.User, error) {
+	user, err := s.userRepo.WithContext(ctx).GetByID(id)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户失败: %w", err)
 	}
@@ -125,8 +160,8 @@ func (s *userService) GetUser(id uint64) (*model.User, error) {
 }
 
 // GetUserWithRoles 获取用户（包含角色）
-func (s *userService) GetUserWithRoles(id uint64) (*model.User, error) {
-	user, err := s.userRepo.GetByIDWithRoles(id)
+func (s *userService) GetUserWithRoles(ctx context.Context, id uint64) (*model.User, error) {
+	user, err := s.userRepo.WithContext(ctx).GetByIDWithRoles(id)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户失败: %w", err)
 	}
@@ -134,16 +169,18 @@ func (s *userService) GetUserWithRoles(id uint64) (*model.User, error) {
 }
 
 // UpdateUser 更新用户
-func (s *userService) UpdateUser(user *model.User) error {
+func (s *userService) UpdateUser(ctx context.Context, user *model.User) error {
+	userRepo := s.userRepo.WithContext(ctx)
+
 	// 获取原用户信息
-	existingUser, err := s.userRepo.GetByID(user.ID)
+	existingUser, err := userRepo.GetByID(user.ID)
 	if err != nil {
 		return fmt.Errorf("获取原用户信息失败: %w", err)
 	}
 
 	// 如果用户名发生变化，检查新用户名是否已存在
 	if user.Username != existingUser.Username {
-		exists, err := s.userRepo.UsernameExists(user.TenantID, user.Username)
+		exists, err := userRepo.UsernameExists(user.TenantID, user.Username)
 		if err != nil {
 			return fmt.Errorf("检查用户名是否存在失败: %w", err)
 		}
@@ -160,7 +197,7 @@ func (s *userService) UpdateUser(user *model.User) error {
 			existingEmailValue = *existingUser.Email
 		}
 		if *user.Email != existingEmailValue {
-			exists, err := s.userRepo.EmailExists(user.TenantID, *user.Email)
+			exists, err := userRepo.EmailExists(user.TenantID, *user.Email)
 			if err != nil {
 				return fmt.Errorf("检查邮箱是否存在失败: %w", err)
 			}
@@ -178,7 +215,7 @@ func (s *userService) UpdateUser(user *model.User) error {
 			existingPhoneValue = *existingUser.Phone
 		}
 		if *user.Phone != existingPhoneValue {
-			exists, err := s.userRepo.PhoneExists(user.TenantID, *user.Phone)
+			exists, err := userRepo.PhoneExists(user.TenantID, *user.Phone)
 			if err != nil {
 				return fmt.Errorf("检查手机号是否存在失败: %w", err)
 			}
@@ -188,21 +225,53 @@ func (s *userService) UpdateUser(user *model.User) error {
 		}
 	}
 
+	// 如果部门发生变化，检查新部门是否属于本租户
+	if user.DeptID != existingUser.DeptID {
+		if err := s.checkDept(ctx, user.TenantID, user.DeptID); err != nil {
+			return err
+		}
+	}
+
 	// 不允许修改密码（使用专门的修改密码方法）
 	user.Password = existingUser.Password
 
 	// 更新用户
-	if err := s.userRepo.Update(user); err != nil {
+	if err := userRepo.Update(user); err != nil {
 		return fmt.Errorf("更新用户失败: %w", err)
 	}
 
+	// 部门变化影响"本部门"数据权限范围
+	if user.DeptID != existingUser.DeptID {
+		permission.InvalidateUsers(user.ID)
+	}
+
+	// 禁用用户时强制下线
+	if existingUser.Status != 2 && user.Status == 2 {
+		if err := s.RevokeUserSessions(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkDept 检查部门是否属于本租户，0表示不分配部门
+func (s *userService) checkDept(ctx context.Context, tenantID, deptID uint64) error {
+	if deptID == 0 {
+		return nil
+	}
+	if _, err := s.deptRepo.WithContext(ctx).GetByID(tenantID, deptID); err != nil {
+		return errors.New("部门不存在")
+	}
 	return nil
 }
 
 // DeleteUser 删除用户
-func (s *userService) DeleteUser(id uint64) error {
+func (s *userService) DeleteUser(ctx context.Context, id uint64) error {
+	userRepo := s.userRepo.WithContext(ctx)
+
 	// 检查用户是否存在
-	user, err := s.userRepo.GetByID(id)
+	user, err := userRepo.GetByID(id)
 	if err != nil {
 		return fmt.Errorf("获取用户信息失败: %w", err)
 	}
@@ -213,18 +282,29 @@ func (s *userService) DeleteUser(id uint64) error {
 	}
 
 	// 删除用户
-	if err := s.userRepo.Delete(id); err != nil {
+	if err := userRepo.Delete(id); err != nil {
 		return fmt.Errorf("删除用户失败: %w", err)
 	}
+	permission.InvalidateUsers(id)
 
 	return nil
 }
 
 // GetUserList 获取用户列表
-func (s *userService) GetUserList(tenantID uint64, page, pageSize int, keyword string, status int, roleID uint64) ([]*model.User, int64, error) {
+func (s *userService) GetUserList(filter sharedModel.DataFilter, page, pageSize int, keyword string, status int, roleID uint64, deptID uint64) ([]*model.User, int64, error) {
+	// 按部门筛选时包含所有下级部门
+	var deptIDs []uint64
+	if deptID > 0 {
+		depts, err := s.deptRepo.GetAll(filter.TenantID, 0)
+		if err != nil {
+			return nil, 0, fmt.Errorf("获取部门信息失败: %w", err)
+		}
+		deptIDs = model.DeptSubtreeIDs(depts, deptID)
+	}
+
 	// TODO: 目前repository层的GetList方法不支持关键词和角色筛选
 	// 这里先使用基础的分页查询，后续需要扩展repository方法
-	users, total, err := s.userRepo.GetList(tenantID, page, pageSize, status)
+	users, total, err := s.userRepo.GetList(filter, page, pageSize, status, deptIDs)
 	if err != nil {
 		return nil, 0, fmt.Errorf("获取用户列表失败: %w", err)
 	}
@@ -270,9 +350,11 @@ func (s *userService) GetUserByEmail(tenantID uint64, email string) (*model.User
 }
 
 // UpdateUserStatus 更新用户状态
-func (s *userService) UpdateUserStatus(id uint64, status int) error {
+func (s *userService) UpdateUserStatus(ctx context.Context, id uint64, status int) error {
+	userRepo := s.userRepo.WithContext(ctx)
+
 	// 检查用户是否存在
-	user, err := s.userRepo.GetByID(id)
+	user, err := userRepo.GetByID(id)
 	if err != nil {
 		return fmt.Errorf("获取用户信息失败: %w", err)
 	}
@@ -288,18 +370,26 @@ func (s *userService) UpdateUserStatus(id uint64, status int) error {
 	}
 
 	// 更新状态
-	if err := s.userRepo.UpdateStatus(id, status); err != nil {
+	if err := userRepo.UpdateStatus(id, status); err != nil {
 		return fmt.Errorf("更新用户状态失败: %w", err)
 	}
 
+	// 禁用用户时强制下线
+	if status == 2 {
+		if err := s.RevokeUserSessions(ctx, id); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // BatchUpdateUserStatus 批量更新用户状态
-func (s *userService) BatchUpdateUserStatus(ids []uint64, status int) error {
+func (s *userService) BatchUpdateUserStatus(ctx context.Context, ids []uint64, status int) error {
+	userRepo := s.userRepo.WithContext(ctx)
 	for _, id := range ids {
 		// 检查每个用户
-		user, err := s.userRepo.GetByID(id)
+		user, err := userRepo.GetByID(id)
 		if err != nil {
 			continue // 跳过不存在的用户
 		}
@@ -315,15 +405,69 @@ func (s *userService) BatchUpdateUserStatus(ids []uint64, status int) error {
 		}
 
 		// 更新状态
-		if err := s.userRepo.UpdateStatus(id, status); err != nil {
+		if err := userRepo.UpdateStatus(id, status); err != nil {
 			// 记录错误但继续处理其他用户
 			continue
 		}
+
+		// 禁用用户时强制下线
+		if status == 2 {
+			if err := s.RevokeUserSessions(ctx, id); err != nil {
+				logger.WithField("userId", id).Error("Failed to revoke user sessions:", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// UnlockUser 解锁因登录失败被锁定的用户
+func (s *userService) UnlockUser(ctx context.Context, id uint64) error {
+	userRepo := s.userRepo.WithContext(ctx)
+
+	// 检查用户是否存在
+	if _, err := userRepo.GetByID(id); err != nil {
+		return fmt.Errorf("获取用户信息失败: %w", err)
+	}
+
+	if err := userRepo.UnlockUser(id); err != nil {
+		return fmt.Errorf("解锁用户失败: %w", err)
 	}
 
 	return nil
 }
 
+// ResetUserMfa 重置用户二次验证（如用户丢失身份验证器）
+func (s *userService) ResetUserMfa(ctx context.Context, id uint64) error {
+	userRepo := s.userRepo.WithContext(ctx)
+
+	// 检查用户是否存在
+	if _, err := userRepo.GetByID(id); err != nil {
+		return fmt.Errorf("获取用户信息失败: %w", err)
+	}
+
+	if err := userRepo.UpdateMfa(id, false, "", ""); err != nil {
+		return fmt.Errorf("重置二次验证失败: %w", err)
+	}
+
+	logger.WithField("userId", id).Info("User MFA reset by admin")
+	return nil
+}
+
+// RevokeUserSessions 吊销用户所有已登录会话
+func (s *userService) RevokeUserSessions(ctx context.Context, id uint64) error {
+	// 检查用户是否存在（且属于当前租户）
+	if _, err := s.userRepo.WithContext(ctx).GetByID(id); err != nil {
+		return fmt.Errorf("获取用户信息失败: %w", err)
+	}
+
+	if err := jwt.RevokeUserTokens(id); err != nil {
+		return fmt.Errorf("吊销用户会话失败: %w", err)
+	}
+	permission.ClearUserPermissions(id)
+	return nil
+}
+
 // ChangePassword 修改密码
 func (s *userService) ChangePassword(id uint64, oldPassword, newPassword string) error {
 	// 获取用户信息
@@ -337,6 +481,11 @@ func (s *userService) ChangePassword(id uint64, oldPassword, newPassword string)
 		return errors.New("原密码不正确")
 	}
 
+	// 校验密码策略
+	if err := s.passwordPolicy.ValidateNewPassword(user.TenantID, user, newPassword); err != nil {
+		return err
+	}
+
 	// 加密新密码
 	hashedPassword, err := utils.HashPassword(newPassword)
 	if err != nil {
@@ -351,10 +500,18 @@ func (s *userService) ChangePassword(id uint64, oldPassword, newPassword string)
 	return nil
 }
 
-// ResetPassword 重置密码
-func (s *userService) ResetPassword(id uint64) (string, error) {
-	// 生成新密码（6位随机数字）
-	newPassword := "123456" // 简单示例，生产环境应该生成随机密码
+// ResetPassword 重置为临时密码，用户下次登录时必须修改
+func (s *userService) ResetPassword(ctx context.Context, id uint64) (string, error) {
+	userRepo := s.userRepo.WithContext(ctx)
+	user, err := userRepo.GetByID(id)
+	if err != nil {
+		return "", fmt.Errorf("获取用户信息失败: %w", err)
+	}
+
+	newPassword, err := s.generateTemporaryPassword(user.TenantID)
+	if err != nil {
+		return "", err
+	}
 
 	// 加密密码
 	hashedPassword, err := utils.HashPassword(newPassword)
@@ -363,13 +520,37 @@ func (s *userService) ResetPassword(id uint64) (string, error) {
 	}
 
 	// 更新密码
-	if err := s.userRepo.UpdatePassword(id, hashedPassword); err != nil {
+	if err := userRepo.ResetPassword(id, hashedPassword); err != nil {
 		return "", fmt.Errorf("重置密码失败: %w", err)
 	}
 
 	return newPassword, nil
 }
 
+// generateTemporaryPassword 生成满足租户密码策略的临时密码
+func (s *userService) generateTemporaryPassword(tenantID uint64) (string, error) {
+	return newTemporaryPassword(s.passwordPolicy.GetPolicy(tenantID))
+}
+
+// newTemporaryPassword 生成满足指定密码策略的临时密码
+func newTemporaryPassword(policy *model.PasswordPolicyConfig) (string, error) {
+	length := 12
+	if policy.MinLength > length {
+		length = policy.MinLength
+	}
+
+	for i := 0; i < 3; i++ {
+		password, err := utils.GenerateTemporaryPassword(length)
+		if err != nil {
+			return "", fmt.Errorf("生成临时密码失败: %w", err)
+		}
+		if policy.CheckPassword(password) == nil {
+			return password, nil
+		}
+	}
+	return "", errors.New("生成临时密码失败")
+}
+
 // ValidateUser 验证用户
 func (s *userService) ValidateUser(tenantID uint64, username, password string) (*model.User, error) {
 	// 获取用户
@@ -409,58 +590,61 @@ func (s *userService) CheckEmailExists(tenantID uint64, email string) (bool, err
 	return exists, nil
 }
 
-// AssignUserRoles 为用户分配角色
-func (s *userService) AssignUserRoles(userID uint64, roleIDs []uint64) error {
+// AssignUserRoles 为用户分配角色，超级管理员角色和系统角色仅超级管理员可分配
+func (s *userService) AssignUserRoles(ctx context.Context, userID uint64, roleIDs []uint64, isSuperAdmin bool) error {
+	userRepo := s.userRepo.WithContext(ctx)
+
 	// 获取用户信息
-	_, err := s.userRepo.GetByID(userID)
+	user, err := userRepo.GetByID(userID)
 	if err != nil {
 		return fmt.Errorf("获取用户信息失败: %w", err)
 	}
 
-	// 验证角色是否存在
-	for _, roleID := range roleIDs {
-		_, err := s.roleRepo.GetByID(roleID)
-		if err != nil {
-			return fmt.Errorf("角色ID %d 不存在", roleID)
-		}
+	// 验证角色是否存在、对用户所属租户可见且操作者有权分配
+	if err := CheckAssignRoles(s.roleRepo.WithContext(ctx), user, roleIDs, isSuperAdmin); err != nil {
+		return err
 	}
 
 	// 分配角色
-	if err := s.userRepo.BatchAssignRoles(userID, roleIDs); err != nil {
+	if err := userRepo.BatchAssignRoles(userID, roleIDs); err != nil {
 		return fmt.Errorf("分配角色失败: %w", err)
 	}
+	permission.InvalidateUsers(userID)
 
 	return nil
 }
 
 // RemoveUserRoles 移除用户角色
-func (s *userService) RemoveUserRoles(userID uint64, roleIDs []uint64) error {
+func (s *userService) RemoveUserRoles(ctx context.Context, userID uint64, roleIDs []uint64) error {
+	userRepo := s.userRepo.WithContext(ctx)
+
 	// 获取用户信息
-	_, err := s.userRepo.GetByID(userID)
+	user, err := userRepo.GetByID(userID)
 	if err != nil {
 		return fmt.Errorf("获取用户信息失败: %w", err)
 	}
 
-	// 验证角色是否存在
+	// 验证角色是否存在且对用户所属租户可见
 	for _, roleID := range roleIDs {
-		_, err := s.roleRepo.GetByID(roleID)
-		if err != nil {
+		role, err := s.roleRepo.WithContext(ctx).GetByID(roleID)
+		if err != nil || !role.VisibleTo(user.TenantID) {
 			return fmt.Errorf("角色ID %d 不存在", roleID)
 		}
 	}
 
 	// 移除角色
-	if err := s.userRepo.BatchRemoveRoles(userID, roleIDs); err != nil {
+	if err := userRepo.BatchRemoveRoles(userID, roleIDs); err != nil {
 		return fmt.Errorf("移除角色失败: %w", err)
 	}
+	permission.InvalidateUsers(userID)
 
 	return nil
 }
 
 // GetUserRoles 获取用户角色
-func (s *userService) GetUserRoles(userID uint64) ([]*model.Role, error) {
+func (s *userService) GetUserRoles(ctx context.Context, userID uint64) ([]*model.Role, error) {
 	// 获取带角色信息的用户
-	user, err := s.userRepo.GetByIDWithRoles(userID)
+	user, err := s.userRepo.WithContext(ctx).GetByIDWithRoles(userID)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户角色失败: %w", err)
 	}
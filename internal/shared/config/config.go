@@ -15,13 +15,17 @@ type Config struct {
 	JWT      JWTConfig      `mapstructure:"jwt"`
 	Log      LogConfig      `mapstructure:"log"`
 	File     FileConfig     `mapstructure:"file"`
+	Mail     MailConfig     `mapstructure:"mail"`
+	Tenant   TenantConfig   `mapstructure:"tenant"`
+	Scanner  ScannerConfig  `mapstructure:"scanner"`
 }
 
 // AppConfig 应用配置
 type AppConfig struct {
-	Name     string `mapstructure:"name"`
-	Mode     string `mapstructure:"mode"`
-	TimeZone string `mapstructure:"time_zone"`
+	Name        string `mapstructure:"name"`
+	Mode        string `mapstructure:"mode"`
+	TimeZone    string `mapstructure:"time_zone"`
+	FrontendURL string `mapstructure:"frontend_url"` // 前端访问地址，用于生成邮件中的链接
 }
 
 // ServerConfig 服务器配置
@@ -53,8 +57,10 @@ type RedisConfig struct {
 
 // JWTConfig JWT配置
 type JWTConfig struct {
-	Secret    string `mapstructure:"secret"`
-	ExpiresIn int    `mapstructure:"expires_in"`
+	Secret                 string `mapstructure:"secret"`
+	ExpiresIn              int    `mapstructure:"expires_in"`               // 访问token有效期（秒）
+	RefreshExpiresIn       int    `mapstructure:"refresh_expires_in"`       // 刷新token有效期（秒）
+	ImpersonationExpiresIn int    `mapstructure:"impersonation_expires_in"` // 模拟登录token有效期（秒）
 }
 
 // LogConfig 日志配置
@@ -69,6 +75,50 @@ type FileConfig struct {
 	LocalPath   string `mapstructure:"local_path"`    // 本地存储路径
 	BaseURL     string `mapstructure:"base_url"`      // 文件访问基础URL
 	MaxFileSize int64  `mapstructure:"max_file_size"` // 默认最大文件大小(字节)
+
+	ChunkSize           int64 `mapstructure:"chunk_size"`            // 分片上传的分片大小(字节)，对象存储要求不小于5MB
+	UploadSessionExpire int   `mapstructure:"upload_session_expire"` // 分片上传会话有效期(秒)
+}
+
+// MailConfig 邮件配置（租户未配置发件设置时使用）
+type MailConfig struct {
+	Driver   string `mapstructure:"driver"`    // smtp/file
+	Host     string `mapstructure:"host"`      // SMTP服务器
+	Port     int    `mapstructure:"port"`      // SMTP端口
+	Username string `mapstructure:"username"`  // SMTP用户名
+	Password string `mapstructure:"password"`  // SMTP密码
+	UseTLS   bool   `mapstructure:"use_tls"`   // 是否使用隐式TLS
+	From     string `mapstructure:"from"`      // 发件地址
+	FromName string `mapstructure:"from_name"` // 发件人名称
+	FileDir  string `mapstructure:"file_dir"`  // file驱动的邮件保存目录，为空时仅输出日志
+}
+
+// ScannerConfig 上传文件病毒扫描配置
+type ScannerConfig struct {
+	Driver  string `mapstructure:"driver"`   // none/clamav
+	Address string `mapstructure:"address"`  // clamd地址，如 127.0.0.1:3310 或 unix:/var/run/clamav/clamd.ctl
+	Timeout int    `mapstructure:"timeout"`  // 连接及每个数据块收发的超时（秒）
+	MaxSize int64  `mapstructure:"max_size"` // 可扫描的最大文件字节数，与 clamd 的 StreamMaxLength 保持一致
+}
+
+// TenantConfig 租户解析配置
+type TenantConfig struct {
+	Resolvers        []TenantResolverConfig `mapstructure:"resolvers"`          // 解析器链，按顺序尝试，命中即停止
+	CacheTTL         int                    `mapstructure:"cache_ttl"`          // 解析结果缓存时间（秒）
+	NegativeCacheTTL int                    `mapstructure:"negative_cache_ttl"` // 未命中结果缓存时间（秒）
+	RegisterRoleCode string                 `mapstructure:"register_role_code"` // 自助注册用户分配的默认角色编码，为空时不分配
+}
+
+// TenantResolverConfig 租户解析器配置
+type TenantResolverConfig struct {
+	Type     string   `mapstructure:"type"`      // static/domain/subdomain/path/header
+	Hosts    []string `mapstructure:"hosts"`     // static：固定映射到TenantID的主机名
+	TenantID uint64   `mapstructure:"tenant_id"` // static：映射的租户ID
+	Suffix   string   `mapstructure:"suffix"`    // subdomain：泛域名后缀，如 .example.com
+	Prefix   string   `mapstructure:"prefix"`    // path：路径前缀，如 /t/
+	Header   string   `mapstructure:"header"`    // header：租户ID请求头
+	Secret   string   `mapstructure:"secret"`    // header：网关签名密钥
+	MaxSkew  int      `mapstructure:"max_skew"`  // header：签名时间戳允许的偏差（秒）
 }
 
 var config *Config
@@ -111,6 +161,7 @@ func setDefaults() {
 	viper.SetDefault("app.name", "light-stack")
 	viper.SetDefault("app.mode", "development")
 	viper.SetDefault("app.time_zone", "Asia/Shanghai")
+	viper.SetDefault("app.frontend_url", "http://localhost:5173")
 
 	// 服务器配置
 	viper.SetDefault("server.port", "8080")
@@ -136,6 +187,8 @@ func setDefaults() {
 	// JWT配置
 	viper.SetDefault("jwt.secret", "your-secret-key")
 	viper.SetDefault("jwt.expires_in", 3600)
+	viper.SetDefault("jwt.refresh_expires_in", 604800)
+	viper.SetDefault("jwt.impersonation_expires_in", 1800)
 
 	// 日志配置
 	viper.SetDefault("log.level", "info")
@@ -146,6 +199,26 @@ func setDefaults() {
 	viper.SetDefault("file.local_path", "uploads")
 	viper.SetDefault("file.base_url", "/static")
 	viper.SetDefault("file.max_file_size", 50*1024*1024) // 50MB
+	viper.SetDefault("file.chunk_size", 5*1024*1024)     // 5MB
+	viper.SetDefault("file.upload_session_expire", 86400)
+
+	// 邮件配置
+	viper.SetDefault("mail.driver", "file")
+	viper.SetDefault("mail.port", 25)
+	viper.SetDefault("mail.from", "noreply@lightstack.local")
+	viper.SetDefault("mail.from_name", "light-stack")
+	viper.SetDefault("mail.file_dir", "logs/mails")
+
+	// 病毒扫描配置
+	viper.SetDefault("scanner.driver", "none")
+	viper.SetDefault("scanner.address", "127.0.0.1:3310")
+	viper.SetDefault("scanner.timeout", 60)
+	viper.SetDefault("scanner.max_size", 25*1024*1024) // clamd StreamMaxLength 默认值
+
+	// 租户解析配置
+	viper.SetDefault("tenant.cache_ttl", 300)
+	viper.SetDefault("tenant.negative_cache_ttl", 60)
+	viper.SetDefault("tenant.register_role_code", "user")
 }
 
 // Get 获取配置
@@ -1,6 +1,9 @@
 package globals
 
 import (
+	"context"
+	"time"
+
 	analyticsController "github.com/LiteMove/light-stack/internal/modules/analytics/controller"
 	analyticsService "github.com/LiteMove/light-stack/internal/modules/analytics/service"
 	authController "github.com/LiteMove/light-stack/internal/modules/auth/controller"
@@ -16,7 +19,11 @@ import (
 	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
 	systemService "github.com/LiteMove/light-stack/internal/modules/system/service"
 	"github.com/LiteMove/light-stack/internal/repository"
+	"github.com/LiteMove/light-stack/internal/shared/config"
 	"github.com/LiteMove/light-stack/pkg/database"
+	"github.com/LiteMove/light-stack/pkg/mailer"
+	"github.com/LiteMove/light-stack/pkg/permission"
+	"github.com/LiteMove/light-stack/pkg/scanner"
 	"gorm.io/gorm"
 )
 
@@ -28,9 +35,15 @@ var (
 	menuRepo       repository2.MenuRepository
 	tenantRepo     repository2.TenantRepository
 	fileRepo       *repository3.FileRepository
+	uploadRepo     *repository3.UploadSessionRepository
 	dictRepo       repository2.DictRepository
 	dbAnalyzerRepo *repository.DBAnalyzerRepository
 	genConfigRepo  *repository4.GenConfigRepository
+	operLogRepo    repository2.OperationLogRepository
+	loginLogRepo   repository2.LoginLogRepository
+	packageRepo    repository2.TenantPackageRepository
+	deptRepo       repository2.DeptRepository
+	postRepo       repository2.PostRepository
 
 	// Generator 层
 	templateEngine *generatorEngine.TemplateEngine
@@ -45,10 +58,19 @@ var (
 	tenantSvc     systemService.TenantService
 	fileSvc       *fileService.FileService
 	profileSvc    authService.ProfileService
+	mfaSvc        authService.MfaService
+	pwdResetSvc   authService.PasswordResetService
+	impersonSvc   authService.ImpersonationService
+	pwdPolicySvc  systemService.PasswordPolicyService
 	dashboardSvc  analyticsService.DashboardService
 	dictSvc       systemService.DictService
 	dbAnalyzerSvc *generatorService.DBAnalyzerService
 	genConfigSvc  *generatorService.GenConfigService
+	operLogSvc    systemService.OperationLogService
+	loginLogSvc   systemService.LoginLogService
+	packageSvc    systemService.TenantPackageService
+	deptSvc       systemService.DeptService
+	postSvc       systemService.PostService
 
 	// Controller 层
 	authCtrl      *authController.AuthController
@@ -58,10 +80,18 @@ var (
 	tenantCtrl    *systemController.TenantController
 	fileCtrl      *fileController.FileController
 	profileCtrl   *authController.ProfileController
+	mfaCtrl       *authController.MfaController
+	pwdResetCtrl  *authController.PasswordResetController
+	impersonCtrl  *authController.ImpersonationController
 	dashboardCtrl *analyticsController.DashboardController
 	dictCtrl      *systemController.DictController
 	generatorCtrl *generatorController.GeneratorController
 	genConfigCtrl *generatorController.GenConfigController
+	operLogCtrl   *systemController.OperationLogController
+	loginLogCtrl  *systemController.LoginLogController
+	packageCtrl   *systemController.TenantPackageController
+	deptCtrl      *systemController.DeptController
+	postCtrl      *systemController.PostController
 )
 
 // Init 初始化所有服务
@@ -72,9 +102,25 @@ func Init() {
 	}
 
 	initRepositories(db)
+	permission.Init(menuRepo, roleRepo, deptRepo)
 	initGenerators()
 	initServices()
 	initControllers()
+	startJobs()
+}
+
+// 后台任务执行间隔
+const (
+	tenantExpiryInterval  = 10 * time.Minute // 租户到期检查间隔
+	uploadCleanupInterval = time.Hour        // 过期上传会话清理间隔
+	scanRetryInterval     = 5 * time.Minute  // 隔离文件扫描重试间隔
+)
+
+// startJobs 启动后台定时任务
+func startJobs() {
+	tenantSvc.StartExpiryJob(tenantExpiryInterval)
+	fileSvc.StartUploadCleanupJob(uploadCleanupInterval)
+	fileSvc.StartScanRetryJob(scanRetryInterval)
 }
 
 func initRepositories(db *gorm.DB) {
@@ -83,9 +129,15 @@ func initRepositories(db *gorm.DB) {
 	menuRepo = repository2.NewMenuRepository(db)
 	tenantRepo = repository2.NewTenantRepository(db)
 	fileRepo = repository3.NewFileRepository(db)
+	uploadRepo = repository3.NewUploadSessionRepository(db)
 	dictRepo = repository2.NewDictRepository(db)
 	dbAnalyzerRepo = repository.NewDBAnalyzerRepository(db)
 	genConfigRepo = repository4.NewGenConfigRepository(db)
+	operLogRepo = repository2.NewOperationLogRepository(db)
+	loginLogRepo = repository2.NewLoginLogRepository(db)
+	packageRepo = repository2.NewTenantPackageRepository(db)
+	deptRepo = repository2.NewDeptRepository(db)
+	postRepo = repository2.NewPostRepository(db)
 }
 
 func initGenerators() {
@@ -98,17 +150,26 @@ func initGenerators() {
 }
 
 func initServices() {
-	authSvc = authService.NewAuthService(userRepo, roleRepo, menuRepo)
-	userSvc = systemService.NewUserService(userRepo, roleRepo)
+	mfaSvc = authService.NewMfaService(userRepo, tenantRepo)
+	pwdPolicySvc = systemService.NewPasswordPolicyService(userRepo, tenantRepo)
+	authSvc = authService.NewAuthService(userRepo, roleRepo, menuRepo, tenantRepo, loginLogRepo, mfaSvc, pwdPolicySvc)
+	userSvc = systemService.NewUserService(userRepo, roleRepo, deptRepo, pwdPolicySvc)
 	roleSvc = systemService.NewRoleService(roleRepo, userRepo)
-	menuSvc = systemService.NewMenuService(menuRepo, roleRepo)
-	tenantSvc = systemService.NewTenantService(tenantRepo, userRepo)
-	profileSvc = authService.NewProfileService(userRepo, roleRepo, tenantRepo)
-	fileSvc = fileService.NewFileService(fileRepo, tenantSvc)
+	menuSvc = systemService.NewMenuService(menuRepo, roleRepo, packageRepo)
+	tenantSvc = systemService.NewTenantService(tenantRepo, userRepo, roleRepo, packageRepo)
+	profileSvc = authService.NewProfileService(userRepo, roleRepo, tenantRepo, loginLogRepo, pwdPolicySvc)
+	pwdResetSvc = authService.NewPasswordResetService(userRepo, tenantRepo, mailer.NewFromConfig(config.Get().Mail), pwdPolicySvc)
+	impersonSvc = authService.NewImpersonationService(userRepo)
+	fileSvc = fileService.NewFileService(fileRepo, uploadRepo, tenantSvc, scanner.NewFromConfig(config.Get().Scanner))
 	dashboardSvc = analyticsService.NewDashboardService(userRepo, tenantRepo, fileRepo)
 	dictSvc = systemService.NewDictService(dictRepo)
 	dbAnalyzerSvc = generatorService.NewDBAnalyzerService(dbAnalyzerRepo, database.GetDB())
 	genConfigSvc = generatorService.NewGenConfigService(genConfigRepo, dbAnalyzerSvc)
+	operLogSvc = systemService.NewOperationLogService(operLogRepo)
+	loginLogSvc = systemService.NewLoginLogService(loginLogRepo)
+	packageSvc = systemService.NewTenantPackageService(packageRepo, tenantRepo, menuRepo)
+	deptSvc = systemService.NewDeptService(deptRepo, userRepo)
+	postSvc = systemService.NewPostService(postRepo, userRepo, dictRepo)
 
 }
 
@@ -120,22 +181,38 @@ func initControllers() {
 	tenantCtrl = systemController.NewTenantController(tenantSvc)
 	fileCtrl = fileController.NewFileController(fileSvc)
 	profileCtrl = authController.NewProfileController(profileSvc)
+	mfaCtrl = authController.NewMfaController(mfaSvc)
+	pwdResetCtrl = authController.NewPasswordResetController(pwdResetSvc)
+	impersonCtrl = authController.NewImpersonationController(impersonSvc)
 	dashboardCtrl = analyticsController.NewDashboardController(dashboardSvc)
 	dictCtrl = systemController.NewDictController(dictSvc)
 	generatorCtrl = generatorController.NewGeneratorController(dbAnalyzerSvc, genConfigSvc, codeGenerator, filePackager, menuSvc)
 	genConfigCtrl = generatorController.NewGenConfigController(genConfigSvc)
+	operLogCtrl = systemController.NewOperationLogController(operLogSvc)
+	loginLogCtrl = systemController.NewLoginLogController(loginLogSvc)
+	packageCtrl = systemController.NewTenantPackageController(packageSvc)
+	deptCtrl = systemController.NewDeptController(deptSvc)
+	postCtrl = systemController.NewPostController(postSvc)
 }
 
 // === Service 获取函数 ===
-func AuthSvc() authService.AuthService                { return authSvc }
-func UserSvc() systemService.UserService              { return userSvc }
-func RoleSvc() systemService.RoleService              { return roleSvc }
-func MenuSvc() systemService.MenuService              { return menuSvc }
-func TenantSvc() systemService.TenantService          { return tenantSvc }
-func FileSvc() *fileService.FileService               { return fileSvc }
-func ProfileSvc() authService.ProfileService          { return profileSvc }
-func DashboardSvc() analyticsService.DashboardService { return dashboardSvc }
-func DictSvc() systemService.DictService              { return dictSvc }
+func AuthSvc() authService.AuthService                     { return authSvc }
+func UserSvc() systemService.UserService                   { return userSvc }
+func RoleSvc() systemService.RoleService                   { return roleSvc }
+func MenuSvc() systemService.MenuService                   { return menuSvc }
+func TenantSvc() systemService.TenantService               { return tenantSvc }
+func FileSvc() *fileService.FileService                    { return fileSvc }
+func ProfileSvc() authService.ProfileService               { return profileSvc }
+func MfaSvc() authService.MfaService                       { return mfaSvc }
+func PasswordResetSvc() authService.PasswordResetService   { return pwdResetSvc }
+func ImpersonationSvc() authService.ImpersonationService   { return impersonSvc }
+func DashboardSvc() analyticsService.DashboardService      { return dashboardSvc }
+func DictSvc() systemService.DictService                   { return dictSvc }
+func OperationLogSvc() systemService.OperationLogService   { return operLogSvc }
+func LoginLogSvc() systemService.LoginLogService           { return loginLogSvc }
+func TenantPackageSvc() systemService.TenantPackageService { return packageSvc }
+func DeptSvc() systemService.DeptService                   { return deptSvc }
+func PostSvc() systemService.PostService                   { return postSvc }
 
 // Generator 获取函数
 func TemplateEngine() *generatorEngine.TemplateEngine { return templateEngine }
@@ -143,23 +220,31 @@ func CodeGenerator() *generatorEngine.CodeGenerator   { return codeGenerator }
 func FilePackager() *generatorEngine.FilePackager     { return filePackager }
 
 // === Controller 获取函数 ===
-func AuthCtrl() *authController.AuthController                { return authCtrl }
-func UserCtrl() *systemController.UserController              { return userCtrl }
-func RoleCtrl() *systemController.RoleController              { return roleCtrl }
-func MenuCtrl() *systemController.MenuController              { return menuCtrl }
-func TenantCtrl() *systemController.TenantController          { return tenantCtrl }
-func FileCtrl() *fileController.FileController                { return fileCtrl }
-func ProfileCtrl() *authController.ProfileController          { return profileCtrl }
-func DashboardCtrl() *analyticsController.DashboardController { return dashboardCtrl }
-func DictCtrl() *systemController.DictController              { return dictCtrl }
-func GeneratorCtrl() *generatorController.GeneratorController { return generatorCtrl }
-func GenConfigCtrl() *generatorController.GenConfigController { return genConfigCtrl }
+func AuthCtrl() *authController.AuthController                     { return authCtrl }
+func UserCtrl() *systemController.UserController                   { return userCtrl }
+func RoleCtrl() *systemController.RoleController                   { return roleCtrl }
+func MenuCtrl() *systemController.MenuController                   { return menuCtrl }
+func TenantCtrl() *systemController.TenantController               { return tenantCtrl }
+func FileCtrl() *fileController.FileController                     { return fileCtrl }
+func ProfileCtrl() *authController.ProfileController               { return profileCtrl }
+func MfaCtrl() *authController.MfaController                       { return mfaCtrl }
+func PasswordResetCtrl() *authController.PasswordResetController   { return pwdResetCtrl }
+func ImpersonationCtrl() *authController.ImpersonationController   { return impersonCtrl }
+func DashboardCtrl() *analyticsController.DashboardController      { return dashboardCtrl }
+func DictCtrl() *systemController.DictController                   { return dictCtrl }
+func GeneratorCtrl() *generatorController.GeneratorController      { return generatorCtrl }
+func GenConfigCtrl() *generatorController.GenConfigController      { return genConfigCtrl }
+func OperationLogCtrl() *systemController.OperationLogController   { return operLogCtrl }
+func LoginLogCtrl() *systemController.LoginLogController           { return loginLogCtrl }
+func TenantPackageCtrl() *systemController.TenantPackageController { return packageCtrl }
+func DeptCtrl() *systemController.DeptController                   { return deptCtrl }
+func PostCtrl() *systemController.PostController                   { return postCtrl }
 
 // === 权限检查函数 ===
 func CheckUserRole(userID uint64, roleCode string) bool {
 	// 超级管理员拥有所有权限
 	if roleCode == "super_admin" {
-		roles, err := userSvc.GetUserRoles(userID)
+		roles, err := userSvc.GetUserRoles(context.Background(), userID)
 		if err != nil {
 			return false
 		}
@@ -172,7 +257,7 @@ func CheckUserRole(userID uint64, roleCode string) bool {
 	}
 
 	// 检查是否有指定角色或超级管理员权限
-	roles, err := userSvc.GetUserRoles(userID)
+	roles, err := userSvc.GetUserRoles(context.Background(), userID)
 	if err != nil {
 		return false
 	}
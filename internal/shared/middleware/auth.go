@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/LiteMove/light-stack/pkg/jwt"
+	"github.com/LiteMove/light-stack/pkg/logger"
 	"github.com/LiteMove/light-stack/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -43,7 +44,22 @@ func Auth() gin.HandlerFunc {
 			return
 		}
 
+		// 检查token是否已被吊销
+		revoked, err := jwt.IsRevoked(claims)
+		if err != nil {
+			logger.WithField("userId", claims.UserID).Error("Failed to check token revocation:", err)
+			response.InternalServerError(c, "认证服务暂不可用")
+			c.Abort()
+			return
+		}
+		if revoked {
+			response.Unauthorized(c, "未登录或登陆已过期!")
+			c.Abort()
+			return
+		}
+
 		// 将用户信息存储到上下文中
+		c.Set("token_claims", claims)
 		c.Set("userId", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("user_roles", claims.Roles)
@@ -90,7 +106,18 @@ func OptionalAuth() gin.HandlerFunc {
 			return
 		}
 
+		// 已吊销的token按未登录处理
+		revoked, err := jwt.IsRevoked(claims)
+		if err != nil {
+			logger.WithField("userId", claims.UserID).Error("Failed to check token revocation:", err)
+		}
+		if err != nil || revoked {
+			c.Next()
+			return
+		}
+
 		// 将用户信息存储到上下文中
+		c.Set("token_claims", claims)
 		c.Set("userId", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("user_roles", claims.Roles)
@@ -109,3 +136,32 @@ func GetUserIDFromContext(c *gin.Context) uint64 {
 	userID := c.GetUint64("userId")
 	return userID
 }
+
+// GetClaimsFromContext 从上下文获取当前token声明的辅助函数
+func GetClaimsFromContext(c *gin.Context) *jwt.Claims {
+	if claims, exists := c.Get("token_claims"); exists {
+		return claims.(*jwt.Claims)
+	}
+	return nil
+}
+
+// GetImpersonatorFromContext 获取模拟登录的实际操作人，非模拟登录时返回nil
+func GetImpersonatorFromContext(c *gin.Context) *jwt.Impersonator {
+	if claims := GetClaimsFromContext(c); claims != nil {
+		return claims.Impersonator
+	}
+	return nil
+}
+
+// RejectImpersonation 包装处理器，模拟登录会话禁止调用（用于删除、改密等敏感操作）
+// 用法: users.DELETE("/:id", middleware.RejectImpersonation(ctrl.DeleteUser), "system:user:delete")
+func RejectImpersonation(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetImpersonatorFromContext(c) != nil {
+			response.ForbiddenWithCode(c, response.CodeImpersonationForbidden, "模拟登录状态下禁止执行此操作")
+			c.Abort()
+			return
+		}
+		handler(c)
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/LiteMove/light-stack/pkg/permission"
+	"github.com/gin-gonic/gin"
+)
+
+// GuardedGroup 带权限声明的路由组，注册路由时同时挂载权限校验并登记到权限清单
+type GuardedGroup struct {
+	group      *gin.RouterGroup
+	superAdmin bool
+}
+
+// Guard 包装路由组，组内路由按声明的权限码校验
+// 用法: roles := middleware.Guard(admin.Group("/roles"))
+//
+//	roles.POST("", ctrl.CreateRole, "system:role:create")
+func Guard(group *gin.RouterGroup) *GuardedGroup {
+	return &GuardedGroup{group: group}
+}
+
+// GuardSuperAdmin 包装路由组，组内路由仅超级管理员可访问
+func GuardSuperAdmin(group *gin.RouterGroup) *GuardedGroup {
+	group.Use(SuperAdmin())
+	return &GuardedGroup{group: group, superAdmin: true}
+}
+
+// GET 注册GET路由
+func (g *GuardedGroup) GET(relativePath string, handler gin.HandlerFunc, codes ...string) {
+	g.handle(http.MethodGet, relativePath, handler, codes)
+}
+
+// POST 注册POST路由
+func (g *GuardedGroup) POST(relativePath string, handler gin.HandlerFunc, codes ...string) {
+	g.handle(http.MethodPost, relativePath, handler, codes)
+}
+
+// PUT 注册PUT路由
+func (g *GuardedGroup) PUT(relativePath string, handler gin.HandlerFunc, codes ...string) {
+	g.handle(http.MethodPut, relativePath, handler, codes)
+}
+
+// DELETE 注册DELETE路由
+func (g *GuardedGroup) DELETE(relativePath string, handler gin.HandlerFunc, codes ...string) {
+	g.handle(http.MethodDelete, relativePath, handler, codes)
+}
+
+// handle 挂载权限校验并登记路由
+// 普通路由组必须声明权限码，遗漏时启动即失败，避免出现仅需登录即可访问的管理接口
+func (g *GuardedGroup) handle(method, relativePath string, handler gin.HandlerFunc, codes []string) {
+	fullPath := path.Join(g.group.BasePath(), relativePath)
+	if !g.superAdmin && len(codes) == 0 {
+		panic("route " + method + " " + fullPath + " has no permission code")
+	}
+
+	handlers := []gin.HandlerFunc{handler}
+	if len(codes) > 0 {
+		handlers = append([]gin.HandlerFunc{CheckPermission(codes...)}, handlers...)
+	}
+	g.group.Handle(method, relativePath, handlers...)
+
+	permission.RegisterRoute(permission.RouteInfo{
+		Method:      method,
+		Path:        fullPath,
+		Permissions: codes,
+		SuperAdmin:  g.superAdmin,
+	})
+}
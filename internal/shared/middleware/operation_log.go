@@ -0,0 +1,255 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxLogBodySize   = 64 << 10 // 记录请求/响应体的最大字节数
+	maxLogResultSize = 2000     // 操作结果保存的最大长度
+	redactedValue    = "******"
+)
+
+// sensitiveKeys 需要脱敏的请求/响应字段（小写，包含匹配）
+var sensitiveKeys = []string{"password", "secret", "token", "accesskey", "privatekey", "recoverycode", "otp"}
+
+// bodyLogWriter 记录响应体的ResponseWriter
+type bodyLogWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write 同时写入响应和缓冲区
+func (w *bodyLogWriter) Write(b []byte) (int, error) {
+	if remain := maxLogBodySize - w.body.Len(); remain > 0 {
+		if len(b) > remain {
+			w.body.Write(b[:remain])
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString 同时写入响应和缓冲区
+func (w *bodyLogWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// OperationLog 操作日志中间件 - 记录写操作（POST/PUT/PATCH/DELETE）的审计日志
+// 用法: admin.Use(middleware.OperationLog(globals.OperationLogSvc()))
+func OperationLog(logService service.OperationLogService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		requestBody := readRequestBody(c)
+
+		writer := &bodyLogWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		code, message := parseResponseCode(writer.Status(), writer.body.Bytes())
+		status := model.LogStatusSuccess
+		errorMessage := ""
+		if code != http.StatusOK {
+			status = model.LogStatusFailed
+			errorMessage = message
+		}
+
+		tenantID, _ := GetTenantIDFromContext(c)
+		log := &model.OperationLog{
+			UserID:       GetUserIDFromContext(c),
+			Username:     c.GetString("username"),
+			Operation:    truncate(operationName(c), 50),
+			Method:       c.Request.Method,
+			URL:          truncate(c.Request.URL.RequestURI(), 500),
+			Params:       buildLogParams(c, requestBody),
+			Result:       buildLogResult(writer.body.Bytes()),
+			ErrorMessage: errorMessage,
+			IP:           c.ClientIP(),
+			UserAgent:    truncate(c.Request.UserAgent(), 500),
+			Duration:     int(time.Since(start).Milliseconds()),
+			Status:       status,
+		}
+		log.TenantID = tenantID
+		if impersonator := GetImpersonatorFromContext(c); impersonator != nil {
+			log.ImpersonatorID = impersonator.UserID
+			log.ImpersonatorName = impersonator.Username
+		}
+
+		// 异步写入，避免影响接口响应
+		go func() {
+			if err := logService.CreateLog(log); err != nil {
+				logger.WithFields(map[string]interface{}{
+					"userId": log.UserID,
+					"url":    log.URL,
+				}).Error("Failed to save operation log:", err)
+			}
+		}()
+	}
+}
+
+// isMutatingMethod 是否为写操作请求
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// readRequestBody 读取JSON请求体并回填，供后续处理器继续读取
+func readRequestBody(c *gin.Context) []byte {
+	if c.Request.Body == nil || !strings.Contains(c.ContentType(), "json") {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxLogBodySize))
+	if err != nil {
+		return nil
+	}
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	return body
+}
+
+// operationName 根据路由生成操作名称，如 PUT /api/v1/admin/users/:id/status -> users:status:update
+func operationName(c *gin.Context) string {
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+	if idx := strings.Index(path, "/admin/"); idx != -1 {
+		path = path[idx+len("/admin/"):]
+	}
+
+	var parts []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			continue
+		}
+		parts = append(parts, segment)
+	}
+
+	action := "update"
+	switch c.Request.Method {
+	case http.MethodPost:
+		action = "create"
+	case http.MethodDelete:
+		action = "delete"
+	}
+
+	return strings.Join(append(parts, action), ":")
+}
+
+// buildLogParams 组装请求参数（查询参数+脱敏后的请求体），结果必须为合法JSON
+func buildLogParams(c *gin.Context, body []byte) string {
+	params := make(map[string]interface{})
+
+	if query := c.Request.URL.Query(); len(query) > 0 {
+		params["query"] = query
+	}
+
+	if len(body) > 0 {
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err == nil {
+			params["body"] = redactSensitive(data)
+		} else {
+			params["body"] = "[invalid json]"
+		}
+	}
+
+	paramBytes, err := json.Marshal(params)
+	if err != nil {
+		return "{}"
+	}
+	return string(paramBytes)
+}
+
+// buildLogResult 组装脱敏后的操作结果，响应中的临时密码、令牌等一次性凭据不写入日志
+// 无法解析的响应体（如超出记录长度被截断）可能包含未脱敏的内容，不予记录
+func buildLogResult(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "[invalid json]"
+	}
+
+	resultBytes, err := json.Marshal(redactSensitive(data))
+	if err != nil {
+		return "[invalid json]"
+	}
+	return truncate(string(resultBytes), maxLogResultSize)
+}
+
+// redactSensitive 递归脱敏敏感字段
+func redactSensitive(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSensitiveKey(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactSensitive(value)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactSensitive(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isSensitiveKey 判断字段是否为敏感字段
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseResponseCode 从统一响应结构中解析业务码和提示信息
+func parseResponseCode(httpStatus int, body []byte) (int, string) {
+	var resp struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Code == 0 {
+		return httpStatus, http.StatusText(httpStatus)
+	}
+	return resp.Code, resp.Message
+}
+
+// truncate 按字符截断字符串
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/LiteMove/light-stack/internal/shared/model"
 	"github.com/LiteMove/light-stack/pkg/permission"
 	"github.com/LiteMove/light-stack/pkg/response"
 	"github.com/gin-gonic/gin"
@@ -297,3 +298,28 @@ func OwnerOrRole(paramKey string, roleCodes ...string) gin.HandlerFunc {
 		c.Abort()
 	}
 }
+
+// GetDataFilterFromContext 获取当前用户的数据权限过滤条件，超级管理员拥有全部数据权限
+// 无法确定当前租户时返回不匹配任何数据的过滤条件
+func GetDataFilterFromContext(c *gin.Context) model.DataFilter {
+	userID := c.GetUint64("userId")
+	tenantID, exists := GetTenantIDFromContext(c)
+	if !exists {
+		return model.DataFilter{Scope: model.DataScopeNone, UserID: userID}
+	}
+
+	filter := model.DataFilter{
+		Scope:    model.DataScopeSelf,
+		UserID:   userID,
+		TenantID: tenantID,
+	}
+	if c.GetBool("is_super_admin") {
+		filter.Scope = model.DataScopeAll
+	} else if userID != 0 {
+		filter.Scope = permission.Cache.GetUserDataScope(userID)
+		if filter.Scope == model.DataScopeDept {
+			filter.DeptIDs = permission.Cache.GetUserDeptIDs(userID)
+		}
+	}
+	return filter
+}
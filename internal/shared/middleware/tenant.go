@@ -1,18 +1,19 @@
 package middleware
 
 import (
-	"net"
+	"errors"
 	"strconv"
-	"strings"
 
 	"github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/pkg/database"
 	"github.com/LiteMove/light-stack/pkg/response"
 
 	"github.com/gin-gonic/gin"
 )
 
-// TenantMiddleware 租户中间件 - 根据请求域名判断租户
+// TenantMiddleware 租户中间件 - 按配置的解析器链（域名、泛域名、路径前缀、网关请求头）判断租户
 func TenantMiddleware(tenantService service.TenantService) gin.HandlerFunc {
+	resolvers := newTenantResolvers(tenantService)
 	return func(c *gin.Context) {
 		// 判断是否登录，获取用户信息，如果有超级管理员的身份，则获取请求头中的X-Tenant-Id
 		if isSuperAdmin := c.GetBool("is_super_admin"); isSuperAdmin {
@@ -24,40 +25,48 @@ func TenantMiddleware(tenantService service.TenantService) gin.HandlerFunc {
 					c.Abort()
 					return
 				}
-				c.Set("tenant_id", tenantIDUint)
+				setTenantID(c, tenantIDUint)
 				c.Next()
 				return
 			}
 		}
 
-		// 获取请求的Host
-		host := c.Request.Host
-
-		// 解析Host，去掉端口号
-		if strings.Contains(host, ":") {
-			if hostWithoutPort, _, err := net.SplitHostPort(host); err == nil {
-				host = hostWithoutPort
+		// 模拟登录会话固定在目标用户所属租户，不受访问域名影响；其余请求按配置的解析器链识别租户
+		impersonator := GetImpersonatorFromContext(c)
+		var tenantID uint64
+		if impersonator != nil {
+			tenantID = GetClaimsFromContext(c).TenantID
+		} else {
+			var err error
+			tenantID, err = resolveTenantID(c, resolvers)
+			if err != nil {
+				abortTenantError(c, err)
+				return
 			}
 		}
+		if tenantID == 0 {
+			response.BadRequest(c, "无法识别当前租户")
+			c.Abort()
+			return
+		}
+		tenant, err := tenantService.CheckTenantStatus(tenantID)
 
-		// 系统管理域名，不需要租户验证
-		if host == "localhost" || host == "127.0.0.1" {
-			c.Set("tenant_id", uint64(1)) // 系统租户ID为1
-			c.Set("tenant_domain", "system")
-			c.Next()
+		// 超级管理员（含其发起的模拟登录）需要处理已禁用或过期的租户，仅在租户不存在时拦截
+		isSuperAdmin := c.GetBool("is_super_admin")
+		if err != nil && (tenant == nil || (!isSuperAdmin && impersonator == nil)) {
+			abortTenantError(c, err)
 			return
 		}
 
-		// 根据域名获取租户信息
-		tenant, err := tenantService.ValidateTenant(host)
-		if err != nil {
-			response.BadRequest(c, "无效的租户域名: "+err.Error())
+		// 登录凭证必须属于当前租户，防止携带其他租户的token访问
+		if claims := GetClaimsFromContext(c); claims != nil && !isSuperAdmin && claims.TenantID != tenant.ID {
+			response.ForbiddenWithCode(c, response.CodeTenantMismatch, "登录凭证与当前租户不匹配，请重新登录")
 			c.Abort()
 			return
 		}
 
 		// 将租户信息存储到上下文中
-		c.Set("tenant_id", tenant.ID)
+		setTenantID(c, tenant.ID)
 		c.Set("tenant_domain", tenant.Domain)
 		c.Set("tenant", tenant)
 
@@ -65,6 +74,29 @@ func TenantMiddleware(tenantService service.TenantService) gin.HandlerFunc {
 	}
 }
 
+// abortTenantError 根据租户校验错误返回对应的错误码并终止请求
+func abortTenantError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTenantDisabled):
+		response.ForbiddenWithCode(c, response.CodeTenantDisabled, err.Error())
+	case errors.Is(err, service.ErrTenantExpired):
+		response.ForbiddenWithCode(c, response.CodeTenantExpired, err.Error())
+	case errors.Is(err, ErrInvalidTenantSignature):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrTenantNotFound):
+		response.BadRequest(c, "无效的租户: "+err.Error())
+	default:
+		response.InternalServerError(c, "租户解析失败")
+	}
+	c.Abort()
+}
+
+// setTenantID 设置当前请求的租户，同时写入请求上下文供数据库租户插件使用
+func setTenantID(c *gin.Context, tenantID uint64) {
+	c.Set("tenant_id", tenantID)
+	c.Request = c.Request.WithContext(database.WithTenant(c.Request.Context(), tenantID))
+}
+
 // RequireTenantMiddleware 要求租户信息的中间件
 func RequireTenantMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,356 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/modules/system/service"
+	"github.com/LiteMove/light-stack/internal/shared/config"
+	"github.com/LiteMove/light-stack/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrInvalidTenantSignature 网关租户请求头签名无效或已过期
+var ErrInvalidTenantSignature = errors.New("租户签名无效或已过期")
+
+// 租户解析器类型
+const (
+	TenantResolverStatic    = "static"    // 固定主机映射
+	TenantResolverDomain    = "domain"    // 租户域名精确匹配
+	TenantResolverSubdomain = "subdomain" // 泛域名子域名作为租户编码
+	TenantResolverPath      = "path"      // 路径前缀中的租户编码
+	TenantResolverHeader    = "header"    // 网关签名请求头
+)
+
+// 网关签名请求头
+const (
+	tenantTimestampHeader = "X-Tenant-Timestamp"
+	tenantSignatureHeader = "X-Tenant-Signature"
+)
+
+// maxTenantResolveEntries 单个解析器缓存的最大条目数，超出时整体清空，防止大量随机主机名占用内存
+const maxTenantResolveEntries = 10000
+
+// TenantResolver 租户解析器，从请求中识别租户
+type TenantResolver interface {
+	// Resolve 返回解析到的租户ID，该解析器无法识别时返回0
+	Resolve(c *gin.Context) (uint64, error)
+}
+
+// tenantResolveCache 解析结果缓存，未命中的结果同样缓存，避免未知主机每次请求都查询数据库
+type tenantResolveCache struct {
+	sync.RWMutex
+	entries     map[string]tenantResolveEntry
+	ttl         time.Duration
+	negativeTTL time.Duration
+}
+
+// tenantResolveEntry 缓存的解析结果，租户ID为0表示未命中
+type tenantResolveEntry struct {
+	tenantID  uint64
+	expiresAt time.Time
+}
+
+// tenantResolveCaches 所有解析器缓存，租户域名或编码变更时统一清空
+var tenantResolveCaches struct {
+	sync.Mutex
+	caches []*tenantResolveCache
+}
+
+// newTenantResolveCache 创建解析结果缓存并登记
+func newTenantResolveCache(cfg config.TenantConfig) *tenantResolveCache {
+	cache := &tenantResolveCache{
+		entries:     make(map[string]tenantResolveEntry),
+		ttl:         time.Duration(cfg.CacheTTL) * time.Second,
+		negativeTTL: time.Duration(cfg.NegativeCacheTTL) * time.Second,
+	}
+
+	tenantResolveCaches.Lock()
+	tenantResolveCaches.caches = append(tenantResolveCaches.caches, cache)
+	tenantResolveCaches.Unlock()
+	return cache
+}
+
+// lookup 读取缓存，未命中时调用load加载，加载出错时不缓存
+func (c *tenantResolveCache) lookup(key string, load func(string) (uint64, error)) (uint64, error) {
+	now := time.Now()
+	c.RLock()
+	entry, exists := c.entries[key]
+	c.RUnlock()
+	if exists && now.Before(entry.expiresAt) {
+		return entry.tenantID, nil
+	}
+
+	tenantID, err := load(key)
+	if err != nil {
+		return 0, err
+	}
+
+	ttl := c.ttl
+	if tenantID == 0 {
+		ttl = c.negativeTTL
+	}
+	if ttl > 0 {
+		c.Lock()
+		if len(c.entries) >= maxTenantResolveEntries {
+			c.entries = make(map[string]tenantResolveEntry)
+		}
+		c.entries[key] = tenantResolveEntry{tenantID: tenantID, expiresAt: now.Add(ttl)}
+		c.Unlock()
+	}
+	return tenantID, nil
+}
+
+// clear 清空缓存
+func (c *tenantResolveCache) clear() {
+	c.Lock()
+	c.entries = make(map[string]tenantResolveEntry)
+	c.Unlock()
+}
+
+// ClearTenantResolveCache 清空所有租户解析缓存，租户域名或编码变更后调用
+func ClearTenantResolveCache() {
+	tenantResolveCaches.Lock()
+	defer tenantResolveCaches.Unlock()
+
+	for _, cache := range tenantResolveCaches.caches {
+		cache.clear()
+	}
+}
+
+// staticTenantResolver 将固定主机映射到指定租户
+type staticTenantResolver struct {
+	hosts    map[string]bool
+	tenantID uint64
+}
+
+// Resolve 解析租户
+func (r *staticTenantResolver) Resolve(c *gin.Context) (uint64, error) {
+	if r.hosts[requestHost(c)] {
+		return r.tenantID, nil
+	}
+	return 0, nil
+}
+
+// domainTenantResolver 按租户域名精确匹配
+type domainTenantResolver struct {
+	tenantService service.TenantService
+	cache         *tenantResolveCache
+}
+
+// Resolve 解析租户
+func (r *domainTenantResolver) Resolve(c *gin.Context) (uint64, error) {
+	host := requestHost(c)
+	if host == "" {
+		return 0, nil
+	}
+	return r.cache.lookup(host, r.tenantService.FindTenantIDByDomain)
+}
+
+// subdomainTenantResolver 将泛域名的子域名作为租户编码，如 acme.example.com -> acme
+type subdomainTenantResolver struct {
+	suffix        string
+	tenantService service.TenantService
+	cache         *tenantResolveCache
+}
+
+// Resolve 解析租户
+func (r *subdomainTenantResolver) Resolve(c *gin.Context) (uint64, error) {
+	host := requestHost(c)
+	if !strings.HasSuffix(host, r.suffix) {
+		return 0, nil
+	}
+	code := strings.TrimSuffix(host, r.suffix)
+	if code == "" || strings.Contains(code, ".") {
+		return 0, nil
+	}
+	return r.cache.lookup(code, r.tenantService.FindTenantIDByCode)
+}
+
+// pathTenantResolver 使用路径前缀中的租户编码，如 /t/acme/api/... -> acme
+// 路径前缀需由 TenantPathHandler 在路由匹配前剥离
+type pathTenantResolver struct {
+	tenantService service.TenantService
+	cache         *tenantResolveCache
+}
+
+// Resolve 解析租户
+func (r *pathTenantResolver) Resolve(c *gin.Context) (uint64, error) {
+	code, _ := c.Request.Context().Value(tenantPathCodeKey{}).(string)
+	if code == "" {
+		return 0, nil
+	}
+	return r.cache.lookup(code, r.tenantService.FindTenantIDByCode)
+}
+
+// headerTenantResolver 使用网关传入的租户ID请求头，需校验网关签名
+// 签名为 hex(HMAC-SHA256(secret, 租户ID + "\n" + 时间戳))
+type headerTenantResolver struct {
+	header  string
+	secret  []byte
+	maxSkew time.Duration
+}
+
+// Resolve 解析租户
+func (r *headerTenantResolver) Resolve(c *gin.Context) (uint64, error) {
+	value := c.GetHeader(r.header)
+	if value == "" {
+		return 0, nil
+	}
+	tenantID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || tenantID == 0 {
+		return 0, ErrInvalidTenantSignature
+	}
+
+	timestamp := c.GetHeader(tenantTimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidTenantSignature
+	}
+	skew := time.Since(time.Unix(unix, 0))
+	if skew > r.maxSkew || skew < -r.maxSkew {
+		return 0, ErrInvalidTenantSignature
+	}
+
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write([]byte(value + "\n" + timestamp))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(c.GetHeader(tenantSignatureHeader)))) {
+		return 0, ErrInvalidTenantSignature
+	}
+	return tenantID, nil
+}
+
+// newTenantResolvers 根据配置创建租户解析器链，未配置时将localhost映射到系统租户并按域名精确匹配
+func newTenantResolvers(tenantService service.TenantService) []TenantResolver {
+	cfg := tenantConfig()
+	resolverConfigs := cfg.Resolvers
+	if len(resolverConfigs) == 0 {
+		resolverConfigs = []config.TenantResolverConfig{
+			{Type: TenantResolverStatic, Hosts: []string{"localhost", "127.0.0.1"}, TenantID: 1},
+			{Type: TenantResolverDomain},
+		}
+	}
+
+	resolvers := make([]TenantResolver, 0, len(resolverConfigs))
+	for _, rc := range resolverConfigs {
+		switch rc.Type {
+		case TenantResolverStatic:
+			hosts := make(map[string]bool, len(rc.Hosts))
+			for _, host := range rc.Hosts {
+				hosts[strings.ToLower(host)] = true
+			}
+			resolvers = append(resolvers, &staticTenantResolver{hosts: hosts, tenantID: rc.TenantID})
+		case TenantResolverDomain:
+			resolvers = append(resolvers, &domainTenantResolver{tenantService: tenantService, cache: newTenantResolveCache(cfg)})
+		case TenantResolverSubdomain:
+			if rc.Suffix == "" {
+				logger.Warn("Tenant subdomain resolver requires suffix, skipped")
+				continue
+			}
+			suffix := strings.TrimPrefix(strings.ToLower(rc.Suffix), "*")
+			if !strings.HasPrefix(suffix, ".") {
+				suffix = "." + suffix
+			}
+			resolvers = append(resolvers, &subdomainTenantResolver{suffix: suffix, tenantService: tenantService, cache: newTenantResolveCache(cfg)})
+		case TenantResolverPath:
+			resolvers = append(resolvers, &pathTenantResolver{tenantService: tenantService, cache: newTenantResolveCache(cfg)})
+		case TenantResolverHeader:
+			if rc.Secret == "" {
+				logger.Warn("Tenant header resolver requires secret, skipped")
+				continue
+			}
+			header := rc.Header
+			if header == "" {
+				header = "X-Tenant-Id"
+			}
+			maxSkew := time.Duration(rc.MaxSkew) * time.Second
+			if maxSkew <= 0 {
+				maxSkew = 5 * time.Minute
+			}
+			resolvers = append(resolvers, &headerTenantResolver{header: header, secret: []byte(rc.Secret), maxSkew: maxSkew})
+		default:
+			logger.Warn("Unknown tenant resolver type, skipped: " + rc.Type)
+		}
+	}
+	return resolvers
+}
+
+// resolveTenantID 依次尝试解析器链，返回第一个解析到的租户ID
+func resolveTenantID(c *gin.Context, resolvers []TenantResolver) (uint64, error) {
+	for _, resolver := range resolvers {
+		tenantID, err := resolver.Resolve(c)
+		if err != nil || tenantID != 0 {
+			return tenantID, err
+		}
+	}
+	return 0, nil
+}
+
+// tenantPathCodeKey 请求上下文中路径前缀租户编码的键
+type tenantPathCodeKey struct{}
+
+// TenantPathHandler 在路由匹配前剥离租户路径前缀（如 /t/acme/api/... -> /api/...），
+// 并将租户编码写入请求上下文供路径解析器使用；未配置路径解析器时原样返回
+func TenantPathHandler(next http.Handler) http.Handler {
+	prefix := ""
+	for _, rc := range tenantConfig().Resolvers {
+		if rc.Type == TenantResolverPath {
+			prefix = rc.Prefix
+			break
+		}
+	}
+	if prefix == "" {
+		return next
+	}
+	prefix = "/" + strings.Trim(prefix, "/") + "/"
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		code, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		if code == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), tenantPathCodeKey{}, code))
+		url := *r.URL
+		url.Path = "/" + rest
+		url.RawPath = ""
+		r.URL = &url
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tenantConfig 获取租户解析配置
+func tenantConfig() config.TenantConfig {
+	if cfg := config.Get(); cfg != nil {
+		return cfg.Tenant
+	}
+	return config.TenantConfig{}
+}
+
+// requestHost 获取去掉端口号的请求主机名
+func requestHost(c *gin.Context) string {
+	host := c.Request.Host
+	if strings.Contains(host, ":") {
+		if hostWithoutPort, _, err := net.SplitHostPort(host); err == nil {
+			host = hostWithoutPort
+		}
+	}
+	return strings.ToLower(host)
+}
@@ -35,3 +35,20 @@ func (b *BaseModel) BeforeUpdate(tx *gorm.DB) error {
 	b.UpdatedAt = time.Now()
 	return nil
 }
+
+// TenantScoped 租户隔离数据接口，嵌入TenantBaseModel的模型自动实现，
+// 查询、更新、删除时由数据库租户插件自动追加租户条件
+type TenantScoped interface {
+	GetTenantID() uint64
+}
+
+// TenantShared 租户共享数据接口，tenant_id为0的记录对所有租户可见（如系统角色）
+type TenantShared interface {
+	TenantScoped
+	SharedAcrossTenants() bool
+}
+
+// GetTenantID 获取租户ID
+func (b TenantBaseModel) GetTenantID() uint64 {
+	return b.TenantID
+}
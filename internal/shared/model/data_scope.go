@@ -0,0 +1,36 @@
+package model
+
+// DataScope 数据权限范围，数值越小范围越大
+type DataScope int
+
+const (
+	DataScopeNone   DataScope = 0 // 无数据权限（如无法确定租户），不返回任何数据
+	DataScopeAll    DataScope = 1 // 全部数据（仍限于当前请求租户，超级管理员通过 X-Tenant-Id 切换租户）
+	DataScopeTenant DataScope = 2 // 本租户数据
+	DataScopeDept   DataScope = 3 // 本部门数据
+	DataScopeSelf   DataScope = 4 // 仅本人数据
+)
+
+// Valid 是否为有效的数据权限范围
+func (s DataScope) Valid() bool {
+	return s >= DataScopeAll && s <= DataScopeSelf
+}
+
+// Wider 返回两个数据权限范围中较大的一个
+func (s DataScope) Wider(other DataScope) DataScope {
+	if !s.Valid() {
+		return other
+	}
+	if other.Valid() && other < s {
+		return other
+	}
+	return s
+}
+
+// DataFilter 列表查询的数据权限过滤条件，由调用者所有角色中最大的数据范围决定
+type DataFilter struct {
+	Scope    DataScope
+	UserID   uint64
+	TenantID uint64
+	DeptIDs  []uint64 // 调用者所在部门及其下级部门，仅"本部门"范围使用
+}
@@ -20,6 +20,10 @@ type OperationLog struct {
 	Duration     int    `json:"duration"` // 执行时长（毫秒）
 	Status       int    `json:"status" gorm:"not null;index:idx_status" validate:"required,oneof=1 2"`
 
+	// 模拟登录时的实际操作人（超级管理员），非模拟登录为空
+	ImpersonatorID   uint64 `json:"impersonatorId" gorm:"not null;default:0;index:idx_impersonator_id"`
+	ImpersonatorName string `json:"impersonatorName" gorm:"size:50"`
+
 	// 关联关系 - User 在 system 模块中
 }
 
@@ -53,41 +57,45 @@ func (LoginLog) TableName() string {
 
 // OperationLogProfile 操作日志资料（简化版本）
 type OperationLogProfile struct {
-	ID           uint64    `json:"id"`
-	TenantID     uint64    `json:"tenantId"`
-	UserID       uint64    `json:"userId"`
-	Username     string    `json:"username"`
-	Operation    string    `json:"operation"`
-	Method       string    `json:"method"`
-	URL          string    `json:"url"`
-	Params       string    `json:"params"`
-	Result       string    `json:"result"`
-	ErrorMessage string    `json:"errorMessage"`
-	IP           string    `json:"ip"`
-	UserAgent    string    `json:"userAgent"`
-	Duration     int       `json:"duration"`
-	Status       int       `json:"status"`
-	CreatedAt    time.Time `json:"createdAt"`
+	ID               uint64    `json:"id"`
+	TenantID         uint64    `json:"tenantId"`
+	UserID           uint64    `json:"userId"`
+	Username         string    `json:"username"`
+	Operation        string    `json:"operation"`
+	Method           string    `json:"method"`
+	URL              string    `json:"url"`
+	Params           string    `json:"params"`
+	Result           string    `json:"result"`
+	ErrorMessage     string    `json:"errorMessage"`
+	IP               string    `json:"ip"`
+	UserAgent        string    `json:"userAgent"`
+	Duration         int       `json:"duration"`
+	Status           int       `json:"status"`
+	ImpersonatorID   uint64    `json:"impersonatorId"`
+	ImpersonatorName string    `json:"impersonatorName"`
+	CreatedAt        time.Time `json:"createdAt"`
 }
 
 // ToProfile 转换为操作日志资料
 func (ol *OperationLog) ToProfile() OperationLogProfile {
 	return OperationLogProfile{
-		ID:           ol.ID,
-		TenantID:     ol.TenantID,
-		UserID:       ol.UserID,
-		Username:     ol.Username,
-		Operation:    ol.Operation,
-		Method:       ol.Method,
-		URL:          ol.URL,
-		Params:       ol.Params,
-		Result:       ol.Result,
-		ErrorMessage: ol.ErrorMessage,
-		IP:           ol.IP,
-		UserAgent:    ol.UserAgent,
-		Duration:     ol.Duration,
-		Status:       ol.Status,
-		CreatedAt:    ol.CreatedAt,
+		ID:               ol.ID,
+		TenantID:         ol.TenantID,
+		UserID:           ol.UserID,
+		Username:         ol.Username,
+		Operation:        ol.Operation,
+		Method:           ol.Method,
+		URL:              ol.URL,
+		Params:           ol.Params,
+		Result:           ol.Result,
+		ErrorMessage:     ol.ErrorMessage,
+		IP:               ol.IP,
+		UserAgent:        ol.UserAgent,
+		Duration:         ol.Duration,
+		Status:           ol.Status,
+		ImpersonatorID:   ol.ImpersonatorID,
+		ImpersonatorName: ol.ImpersonatorName,
+		CreatedAt:        ol.CreatedAt,
 	}
 }
 
@@ -134,3 +142,9 @@ func (ol *OperationLog) IsSuccess() bool {
 func (ll *LoginLog) IsSuccess() bool {
 	return ll.Status == 1
 }
+
+// 日志状态 1-成功 2-失败
+const (
+	LogStatusSuccess = 1
+	LogStatusFailed  = 2
+)
@@ -1,9 +1,15 @@
 package storage
 
 import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"strings"
+	"net/http"
+	"time"
 
 	systemModel "github.com/LiteMove/light-stack/internal/modules/system/model"
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
@@ -79,8 +85,8 @@ func (p *AliyunOSSProvider) GetURL(path string, isPublic bool) string {
 		// 私有文件生成临时访问URL（1小时有效）
 		signedURL, err := p.bucket.SignURL(objectKey, oss.HTTPGet, 3600)
 		if err != nil {
-			// 如果生成签名URL失败，返回需要认证的URL
-			return fmt.Sprintf("/api/v1/file/download/%s", strings.ReplaceAll(path, "/", "%2F"))
+			// 签名失败时不返回直链，私有文件通过 /api/v1/files/:id/private 访问
+			return ""
 		}
 		return signedURL
 	}
@@ -110,6 +116,38 @@ func (p *AliyunOSSProvider) generateObjectKey(path string) string {
 	return path
 }
 
+// Open 打开OSS对象，按读取位置发起范围请求
+func (p *AliyunOSSProvider) Open(path string) (io.ReadSeekCloser, error) {
+	info, err := p.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	objectKey := p.generateObjectKey(path)
+	return newRangeReader(info.Size, func(offset int64) (io.ReadCloser, error) {
+		body, err := p.bucket.GetObject(objectKey, oss.NormalizedRange(fmt.Sprintf("%d-", offset)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get OSS object %s: %w", objectKey, err)
+		}
+		return body, nil
+	}), nil
+}
+
+// Stat 获取OSS对象信息
+func (p *AliyunOSSProvider) Stat(path string) (*ObjectInfo, error) {
+	objectKey := p.generateObjectKey(path)
+
+	header, err := p.bucket.GetObjectDetailedMeta(objectKey)
+	if err != nil {
+		var serviceErr oss.ServiceError
+		if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusNotFound {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get OSS object meta %s: %w", objectKey, err)
+	}
+	return objectInfoFromHeader(header)
+}
+
 // GetSignedURL 生成临时访问URL（用于私有文件下载）
 func (p *AliyunOSSProvider) GetSignedURL(path string, expireSeconds int64) (string, error) {
 	objectKey := p.generateObjectKey(path)
@@ -133,3 +171,101 @@ func (p *AliyunOSSProvider) IsObjectExists(path string) (bool, error) {
 
 	return exists, nil
 }
+
+// InitMultipart 初始化OSS分片上传
+func (p *AliyunOSSProvider) InitMultipart(path string, isPublic bool) (string, error) {
+	acl := oss.ACLPrivate
+	if isPublic {
+		acl = oss.ACLPublicRead
+	}
+
+	imur, err := p.bucket.InitiateMultipartUpload(p.generateObjectKey(path), oss.ACL(acl))
+	if err != nil {
+		return "", fmt.Errorf("failed to initiate OSS multipart upload: %w", err)
+	}
+	return imur.UploadID, nil
+}
+
+// UploadPart 上传OSS分片
+func (p *AliyunOSSProvider) UploadPart(path, uploadID string, partNumber int, data io.Reader, size int64) (string, error) {
+	part, err := p.bucket.UploadPart(p.multipartUpload(path, uploadID), data, size, partNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload OSS part %d: %w", partNumber, err)
+	}
+	return part.ETag, nil
+}
+
+// CompleteMultipart 完成OSS分片上传
+func (p *AliyunOSSProvider) CompleteMultipart(path, uploadID string, parts []UploadedPart, isPublic bool) (string, error) {
+	ossParts := make([]oss.UploadPart, 0, len(parts))
+	for _, part := range parts {
+		ossParts = append(ossParts, oss.UploadPart{PartNumber: part.Number, ETag: part.ETag})
+	}
+
+	if _, err := p.bucket.CompleteMultipartUpload(p.multipartUpload(path, uploadID), ossParts); err != nil {
+		return "", fmt.Errorf("failed to complete OSS multipart upload: %w", err)
+	}
+	return p.GetURL(path, isPublic), nil
+}
+
+// AbortMultipart 取消OSS分片上传
+func (p *AliyunOSSProvider) AbortMultipart(path, uploadID string) error {
+	if err := p.bucket.AbortMultipartUpload(p.multipartUpload(path, uploadID)); err != nil {
+		return fmt.Errorf("failed to abort OSS multipart upload: %w", err)
+	}
+	return nil
+}
+
+// multipartUpload 构造分片上传标识
+func (p *AliyunOSSProvider) multipartUpload(path, uploadID string) oss.InitiateMultipartUploadResult {
+	return oss.InitiateMultipartUploadResult{
+		Bucket:   p.config.OSSBucket,
+		Key:      p.generateObjectKey(path),
+		UploadID: uploadID,
+	}
+}
+
+// PresignUpload 生成OSS PostObject表单上传策略
+func (p *AliyunOSSProvider) PresignUpload(path string, isPublic bool, contentType string, size int64, expires time.Duration) (*PresignedUpload, error) {
+	objectKey := p.generateObjectKey(path)
+	acl := string(oss.ACLPrivate)
+	if isPublic {
+		acl = string(oss.ACLPublicRead)
+	}
+	expiresAt := time.Now().Add(expires)
+
+	policy, err := json.Marshal(map[string]interface{}{
+		"expiration": policyExpiration(expiresAt),
+		"conditions": []interface{}{
+			map[string]string{"bucket": p.config.OSSBucket},
+			[]interface{}{"eq", "$key", objectKey},
+			[]interface{}{"content-length-range", size, size},
+			[]interface{}{"eq", "$Content-Type", contentType},
+			map[string]string{"x-oss-object-acl": acl},
+			map[string]string{"success_action_status": "200"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OSS upload policy: %w", err)
+	}
+	encodedPolicy := base64.StdEncoding.EncodeToString(policy)
+
+	mac := hmac.New(sha1.New, []byte(p.config.OSSSecretKey))
+	mac.Write([]byte(encodedPolicy))
+
+	return &PresignedUpload{
+		Method: http.MethodPost,
+		URL:    fmt.Sprintf("https://%s.%s", p.config.OSSBucket, p.config.OSSEndpoint),
+		Fields: map[string]string{
+			"key":                   objectKey,
+			"policy":                encodedPolicy,
+			"OSSAccessKeyId":        p.config.OSSAccessKey,
+			"Signature":             base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+			"x-oss-object-acl":      acl,
+			"Content-Type":          contentType,
+			"success_action_status": "200",
+		},
+		FileField: "file",
+		ExpiresAt: expiresAt,
+	}, nil
+}
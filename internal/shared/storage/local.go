@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	sysConfig "github.com/LiteMove/light-stack/internal/shared/config"
 	"io"
@@ -128,3 +131,132 @@ func (p *LocalProvider) GetFullPath(path string, isPublic bool) string {
 	// path已经包含了public/private信息，直接拼接基础路径
 	return filepath.Join(basePath, path)
 }
+
+// Open 打开本地文件
+func (p *LocalProvider) Open(path string) (io.ReadSeekCloser, error) {
+	file, err := os.Open(p.GetFullPath(path, false))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to open file %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Stat 获取本地文件信息
+func (p *LocalProvider) Stat(path string) (*ObjectInfo, error) {
+	info, err := os.Stat(p.GetFullPath(path, false))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to stat file %s: %w", path, err)
+	}
+	return &ObjectInfo{Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// InitMultipart 初始化分片上传，分片暂存于本地 multipart/<上传ID> 目录
+func (p *LocalProvider) InitMultipart(path string, isPublic bool) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate upload id: %w", err)
+	}
+	uploadID := hex.EncodeToString(buf)
+
+	if err := os.MkdirAll(p.multipartDir(uploadID), 0755); err != nil {
+		return "", fmt.Errorf("failed to create multipart directory: %w", err)
+	}
+	return uploadID, nil
+}
+
+// UploadPart 保存分片，先写入临时文件再重命名，避免中断的请求留下不完整分片
+func (p *LocalProvider) UploadPart(path, uploadID string, partNumber int, data io.Reader, size int64) (string, error) {
+	dir := p.multipartDir(uploadID)
+	if _, err := os.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrObjectNotFound
+		}
+		return "", fmt.Errorf("failed to stat multipart directory: %w", err)
+	}
+
+	partPath := filepath.Join(dir, fmt.Sprintf("%05d", partNumber))
+	tmp, err := os.CreateTemp(dir, "part-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create part file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := md5.New()
+	written, err := io.Copy(io.MultiWriter(tmp, hash), data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write part %d: %w", partNumber, err)
+	}
+	if written != size {
+		return "", fmt.Errorf("part %d size mismatch: %d != %d", partNumber, written, size)
+	}
+
+	if err := os.Rename(tmp.Name(), partPath); err != nil {
+		return "", fmt.Errorf("failed to save part %d: %w", partNumber, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// CompleteMultipart 按顺序合并分片到目标文件并清理分片目录
+func (p *LocalProvider) CompleteMultipart(path, uploadID string, parts []UploadedPart, isPublic bool) (string, error) {
+	dir := p.multipartDir(uploadID)
+	fullPath := p.GetFullPath(path, isPublic)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %w", filepath.Dir(fullPath), err)
+	}
+
+	dst, err := os.Create(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file %s: %w", fullPath, err)
+	}
+
+	for _, part := range parts {
+		if err = p.appendPart(dst, filepath.Join(dir, fmt.Sprintf("%05d", part.Number))); err != nil {
+			err = fmt.Errorf("failed to merge part %d: %w", part.Number, err)
+			break
+		}
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(fullPath)
+		return "", err
+	}
+
+	os.RemoveAll(dir)
+	return p.GetURL(path, isPublic), nil
+}
+
+// AbortMultipart 删除分片目录
+func (p *LocalProvider) AbortMultipart(path, uploadID string) error {
+	if err := os.RemoveAll(p.multipartDir(uploadID)); err != nil {
+		return fmt.Errorf("failed to remove multipart directory: %w", err)
+	}
+	return nil
+}
+
+// appendPart 将分片内容追加到目标文件
+func (p *LocalProvider) appendPart(dst io.Writer, partPath string) error {
+	src, err := os.Open(partPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+// multipartDir 分片暂存目录，位于静态访问目录之外
+func (p *LocalProvider) multipartDir(uploadID string) string {
+	return p.GetFullPath(filepath.Join("multipart", filepath.Base(uploadID)), false)
+}
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrMultipartNotSupported 存储提供者不支持分片上传
+var ErrMultipartNotSupported = errors.New("multipart upload not supported")
+
+// MultipartProvider 分片上传，本地存储将分片暂存于本地目录，对象存储映射到服务端分片上传
+type MultipartProvider interface {
+	// InitMultipart 初始化分片上传，返回上传ID
+	InitMultipart(path string, isPublic bool) (string, error)
+	// UploadPart 上传分片，分片号从1开始，返回分片ETag
+	UploadPart(path, uploadID string, partNumber int, data io.Reader, size int64) (string, error)
+	// CompleteMultipart 按分片号顺序合并分片，返回访问URL
+	CompleteMultipart(path, uploadID string, parts []UploadedPart, isPublic bool) (string, error)
+	// AbortMultipart 取消分片上传并清理已上传分片
+	AbortMultipart(path, uploadID string) error
+}
+
+// UploadedPart 已上传的分片
+type UploadedPart struct {
+	Number int
+	ETag   string
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrObjectNotFound 存储中不存在该文件
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectInfo 存储对象信息
+type ObjectInfo struct {
+	Size    int64
+	ModTime time.Time
+	ETag    string // 存储服务返回的ETag，本地存储为空
+}
+
+// objectInfoFromHeader 从对象存储的响应头解析对象信息
+func objectInfoFromHeader(header http.Header) (*ObjectInfo, error) {
+	size, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %q", header.Get("Content-Length"))
+	}
+
+	info := &ObjectInfo{
+		Size: size,
+		ETag: strings.Trim(header.Get("ETag"), `"`),
+	}
+	if modTime, err := http.ParseTime(header.Get("Last-Modified")); err == nil {
+		info.ModTime = modTime
+	}
+	return info, nil
+}
+
+// rangeReader 按需发起范围请求的对象读取器，Seek只移动位置，读取时才从当前位置打开对象，
+// 以 io.ReadSeekCloser 的形式提供给 http.ServeContent 支持Range请求而无需整体读入内存
+type rangeReader struct {
+	size   int64
+	offset int64
+	body   io.ReadCloser
+	open   func(offset int64) (io.ReadCloser, error)
+}
+
+// newRangeReader 创建范围读取器，open 返回从指定偏移量到对象末尾的内容
+func newRangeReader(size int64, open func(offset int64) (io.ReadCloser, error)) *rangeReader {
+	return &rangeReader{size: size, open: open}
+}
+
+// Read 从当前位置读取
+func (r *rangeReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		body, err := r.open(r.offset)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+// Seek 移动读取位置，位置变化时关闭当前连接，下次读取时重新打开
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		target = r.offset + offset
+	case io.SeekEnd:
+		target = r.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if target < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if target != r.offset && r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+	r.offset = target
+	return target, nil
+}
+
+// Close 关闭当前连接
+func (r *rangeReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrPresignNotSupported 存储提供者不支持浏览器直传
+var ErrPresignNotSupported = errors.New("presigned upload not supported")
+
+// PresignProvider 生成浏览器直传对象存储的表单上传策略，存储桶需配置允许前端域名的CORS规则
+type PresignProvider interface {
+	// PresignUpload 生成仅允许上传到指定路径、指定大小和内容类型的表单上传策略
+	PresignUpload(path string, isPublic bool, contentType string, size int64, expires time.Duration) (*PresignedUpload, error)
+}
+
+// PresignedUpload 浏览器表单上传参数，Fields 需按原样作为表单字段提交，文件字段放在最后
+type PresignedUpload struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Fields    map[string]string `json:"fields"`
+	FileField string            `json:"fileField"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+// policyExpiration 上传策略的过期时间格式
+func policyExpiration(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
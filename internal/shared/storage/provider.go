@@ -3,6 +3,8 @@ package storage
 import (
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	systemModel "github.com/LiteMove/light-stack/internal/modules/system/model"
 )
@@ -13,6 +15,10 @@ type Provider interface {
 	GetURL(path string, isPublic bool) string
 	Delete(path string) error
 	GetFullPath(path string, isPublic bool) string
+	// Open 打开文件用于流式读取，调用方负责关闭；文件不存在时返回 ErrObjectNotFound
+	Open(path string) (io.ReadSeekCloser, error)
+	// Stat 获取文件信息；文件不存在时返回 ErrObjectNotFound
+	Stat(path string) (*ObjectInfo, error)
 }
 
 // Manager 存储管理器
@@ -34,15 +40,9 @@ func NewManager(config *systemModel.FileStorageConfig) (*Manager, error) {
 		switch config.OSSProvider {
 		case "aliyun":
 			provider, err = NewAliyunOSSProvider(config)
-		case "tencent":
-			// TODO: 实现腾讯云COS Provider
-			return nil, fmt.Errorf("tencent COS provider not implemented yet")
-		case "aws":
-			// TODO: 实现AWS S3 Provider
-			return nil, fmt.Errorf("AWS S3 provider not implemented yet")
-		case "qiniu":
-			// TODO: 实现七牛云Kodo Provider
-			return nil, fmt.Errorf("qiniu Kodo provider not implemented yet")
+		case "aws", "minio", "tencent", "qiniu":
+			// S3协议兼容的对象存储
+			provider, err = NewS3Provider(config)
 		case "upyun":
 			// TODO: 实现又拍云USS Provider
 			return nil, fmt.Errorf("upyun USS provider not implemented yet")
@@ -83,6 +83,79 @@ func (m *Manager) GetFullPath(path string, isPublic bool) string {
 	return m.provider.GetFullPath(path, isPublic)
 }
 
+// Open 打开文件用于流式读取
+func (m *Manager) Open(path string) (io.ReadSeekCloser, error) {
+	return m.provider.Open(path)
+}
+
+// Stat 获取文件信息
+func (m *Manager) Stat(path string) (*ObjectInfo, error) {
+	return m.provider.Stat(path)
+}
+
+// InitMultipart 初始化分片上传
+func (m *Manager) InitMultipart(path string, isPublic bool) (string, error) {
+	provider, ok := m.provider.(MultipartProvider)
+	if !ok {
+		return "", ErrMultipartNotSupported
+	}
+	return provider.InitMultipart(path, isPublic)
+}
+
+// UploadPart 上传分片
+func (m *Manager) UploadPart(path, uploadID string, partNumber int, data io.Reader, size int64) (string, error) {
+	provider, ok := m.provider.(MultipartProvider)
+	if !ok {
+		return "", ErrMultipartNotSupported
+	}
+	return provider.UploadPart(path, uploadID, partNumber, data, size)
+}
+
+// CompleteMultipart 合并分片
+func (m *Manager) CompleteMultipart(path, uploadID string, parts []UploadedPart, isPublic bool) (string, error) {
+	provider, ok := m.provider.(MultipartProvider)
+	if !ok {
+		return "", ErrMultipartNotSupported
+	}
+	return provider.CompleteMultipart(path, uploadID, parts, isPublic)
+}
+
+// AbortMultipart 取消分片上传
+func (m *Manager) AbortMultipart(path, uploadID string) error {
+	provider, ok := m.provider.(MultipartProvider)
+	if !ok {
+		return ErrMultipartNotSupported
+	}
+	return provider.AbortMultipart(path, uploadID)
+}
+
+// PresignUpload 生成浏览器直传策略
+func (m *Manager) PresignUpload(path string, isPublic bool, contentType string, size int64, expires time.Duration) (*PresignedUpload, error) {
+	provider, ok := m.provider.(PresignProvider)
+	if !ok {
+		return nil, ErrPresignNotSupported
+	}
+	return provider.PresignUpload(path, isPublic, contentType, size, expires)
+}
+
+// Move 将文件移动到新路径，通过读取原文件重新上传实现，适用于所有存储提供者
+func (m *Manager) Move(src, dst string, isPublic bool) (string, error) {
+	reader, err := m.provider.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	accessURL, err := m.provider.Upload(reader, dst, isPublic)
+	if err != nil {
+		return "", err
+	}
+	if err := m.provider.Delete(src); err != nil {
+		return "", err
+	}
+	return accessURL, nil
+}
+
 // GenerateStoragePath 生成存储路径
 func GenerateStoragePath(tenantID uint64, dateDir, filename string, isPublic bool) string {
 	accessType := "private"
@@ -92,3 +165,25 @@ func GenerateStoragePath(tenantID uint64, dateDir, filename string, isPublic boo
 	// 使用斜杠作为路径分隔符，确保URL路径正确
 	return fmt.Sprintf("%s/tenant_%d/%s/%s", accessType, tenantID, dateDir, filename)
 }
+
+// quarantineDir 待扫描文件的隔离目录，不提供静态访问
+const quarantineDir = "quarantine"
+
+// GenerateQuarantinePath 生成隔离区存储路径
+func GenerateQuarantinePath(tenantID uint64, dateDir, filename string) string {
+	return fmt.Sprintf("%s/tenant_%d/%s/%s", quarantineDir, tenantID, dateDir, filename)
+}
+
+// IsQuarantinePath 是否为隔离区路径
+func IsQuarantinePath(path string) bool {
+	return strings.HasPrefix(path, quarantineDir+"/")
+}
+
+// ReleasePath 隔离区路径对应的正式存储路径
+func ReleasePath(path string, isPublic bool) string {
+	accessType := "private"
+	if isPublic {
+		accessType = "public"
+	}
+	return accessType + strings.TrimPrefix(path, quarantineDir)
+}
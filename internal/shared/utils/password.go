@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -81,6 +82,43 @@ func GenerateResetToken() (string, error) {
 	return GenerateRandomString(32)
 }
 
+// GenerateTemporaryPassword 生成包含大小写字母、数字和特殊字符的临时密码
+func GenerateTemporaryPassword(length int) (string, error) {
+	charsets := []string{
+		"ABCDEFGHJKLMNPQRSTUVWXYZ",
+		"abcdefghijkmnpqrstuvwxyz",
+		"23456789",
+		"!@#$%^&*-_",
+	}
+	if length < len(charsets) {
+		return "", fmt.Errorf("length must be at least %d", len(charsets))
+	}
+
+	bytes := make([]byte, length*2)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	// 每类字符至少出现一次，其余位置从全部字符中选取
+	all := strings.Join(charsets, "")
+	password := make([]byte, length)
+	for i := range password {
+		charset := all
+		if i < len(charsets) {
+			charset = charsets[i]
+		}
+		password[i] = charset[int(bytes[i])%len(charset)]
+	}
+
+	// 打乱顺序，避免固定位置的字符类型
+	for i := length - 1; i > 0; i-- {
+		j := int(bytes[length+i]) % (i + 1)
+		password[i], password[j] = password[j], password[i]
+	}
+
+	return string(password), nil
+}
+
 // SecureCompare 安全比较两个字符串（防止时序攻击）
 func SecureCompare(a, b string) bool {
 	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
@@ -123,13 +161,33 @@ func ValidatePasswordStrength(password string) error {
 	return nil
 }
 
-// IsPasswordExpired 检查密码是否过期（可根据业务需求实现）
+// IsPasswordExpired 检查密码是否过期
 func IsPasswordExpired(lastChanged int64, expiryDays int) bool {
 	if expiryDays <= 0 {
 		return false // 不设置过期时间
 	}
 
-	// 这里可以根据实际需求实现密码过期逻辑
-	// 例如：检查lastChanged时间戳与当前时间的差值
-	return false
+	expiresAt := time.Unix(lastChanged, 0).AddDate(0, 0, expiryDays)
+	return time.Now().After(expiresAt)
+}
+
+// commonPasswords 内置常见弱密码列表（小写）
+var commonPasswords = map[string]struct{}{
+	"123456": {}, "1234567": {}, "12345678": {}, "123456789": {}, "1234567890": {},
+	"111111": {}, "000000": {}, "666666": {}, "888888": {}, "123123": {},
+	"654321": {}, "112233": {}, "121212": {}, "abc123": {}, "abc123456": {},
+	"a123456": {}, "a12345678": {}, "aa123456": {}, "qq123456": {}, "123456a": {},
+	"123456abc": {}, "password": {}, "password1": {}, "password123": {}, "passw0rd": {},
+	"p@ssw0rd": {}, "admin": {}, "admin123": {}, "admin888": {}, "administrator": {},
+	"root": {}, "root123": {}, "qwerty": {}, "qwerty123": {}, "qwertyuiop": {},
+	"1q2w3e4r": {}, "1qaz2wsx": {}, "qazwsx": {}, "asdfgh": {}, "zxcvbnm": {},
+	"iloveyou": {}, "welcome": {}, "welcome1": {}, "letmein": {}, "monkey": {},
+	"dragon": {}, "football": {}, "baseball": {}, "sunshine": {}, "princess": {},
+	"changeme": {}, "test123": {}, "test1234": {}, "woaini1314": {}, "5201314": {},
+}
+
+// IsCommonPassword 检查是否为常见弱密码（不区分大小写）
+func IsCommonPassword(password string) bool {
+	_, ok := commonPasswords[strings.ToLower(password)]
+	return ok
 }
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// uaRule User-Agent匹配规则
+type uaRule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// browserRules 浏览器匹配规则（顺序敏感：Edge/Opera等基于Chromium的浏览器需在Chrome之前）
+var browserRules = []uaRule{
+	{"WeChat", regexp.MustCompile(`MicroMessenger/([\d.]+)`)},
+	{"DingTalk", regexp.MustCompile(`DingTalk/([\d.]+)`)},
+	{"Edge", regexp.MustCompile(`Edg(?:e|A|iOS)?/([\d.]+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|Opera)/([\d.]+)`)},
+	{"QQBrowser", regexp.MustCompile(`QQBrowser/([\d.]+)`)},
+	{"UCBrowser", regexp.MustCompile(`UCBrowser/([\d.]+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/([\d.]+)`)},
+	{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/([\d.]+)`)},
+	{"Safari", regexp.MustCompile(`Version/([\d.]+).*Safari/`)},
+	{"IE", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)([\d.]+)`)},
+	{"curl", regexp.MustCompile(`curl/([\d.]+)`)},
+	{"PostmanRuntime", regexp.MustCompile(`PostmanRuntime/([\d.]+)`)},
+}
+
+// osRules 操作系统匹配规则（顺序敏感：Android/iOS需在Linux/Mac之前）
+var osRules = []uaRule{
+	{"Windows", regexp.MustCompile(`Windows NT ([\d.]+)`)},
+	{"Android", regexp.MustCompile(`Android ([\d.]+)`)},
+	{"iOS", regexp.MustCompile(`(?:iPhone|iPad|iPod).*? OS ([\d_]+)`)},
+	{"macOS", regexp.MustCompile(`Mac OS X ([\d_.]+)`)},
+	{"HarmonyOS", regexp.MustCompile(`HarmonyOS ?([\d.]*)`)},
+	{"Chrome OS", regexp.MustCompile(`CrOS \S+ ([\d.]+)`)},
+	{"Linux", regexp.MustCompile(`Linux()`)},
+}
+
+// windowsVersions Windows NT版本号映射
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// ParseUserAgent 解析User-Agent，返回浏览器和操作系统
+func ParseUserAgent(userAgent string) (browser, os string) {
+	browser, os = "Unknown", "Unknown"
+	if userAgent == "" {
+		return
+	}
+
+	for _, rule := range browserRules {
+		if matches := rule.pattern.FindStringSubmatch(userAgent); matches != nil {
+			browser = joinNameVersion(rule.name, majorVersion(matches[1]))
+			break
+		}
+	}
+
+	for _, rule := range osRules {
+		if matches := rule.pattern.FindStringSubmatch(userAgent); matches != nil {
+			version := strings.ReplaceAll(matches[1], "_", ".")
+			if rule.name == "Windows" {
+				if name, ok := windowsVersions[version]; ok {
+					version = name
+				}
+			}
+			os = joinNameVersion(rule.name, version)
+			break
+		}
+	}
+
+	return
+}
+
+// GetIPLocation 获取IP归属地（仅区分本机和内网地址，公网地址暂不解析）
+func GetIPLocation(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "未知"
+	}
+	if parsed.IsLoopback() {
+		return "本机地址"
+	}
+	if parsed.IsPrivate() || parsed.IsLinkLocalUnicast() {
+		return "内网IP"
+	}
+	return ""
+}
+
+// majorVersion 截取主版本号
+func majorVersion(version string) string {
+	if idx := strings.Index(version, "."); idx != -1 {
+		return version[:idx]
+	}
+	return version
+}
+
+// joinNameVersion 拼接名称和版本号
+func joinNameVersion(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + " " + version
+}
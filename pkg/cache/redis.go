@@ -48,6 +48,11 @@ func Get(key string) (string, error) {
 	return RDB.Get(ctx, key).Result()
 }
 
+// GetDel 获取并删除缓存（原子操作）
+func GetDel(key string) (string, error) {
+	return RDB.GetDel(ctx, key).Result()
+}
+
 // Del 删除缓存
 func Del(keys ...string) error {
 	return RDB.Del(ctx, keys...).Err()
@@ -84,6 +89,26 @@ func HDel(key string, fields ...string) error {
 	return RDB.HDel(ctx, key, fields...).Err()
 }
 
+// MGet 批量获取缓存，不存在的key对应nil
+func MGet(keys ...string) ([]interface{}, error) {
+	return RDB.MGet(ctx, keys...).Result()
+}
+
+// Incr 自增计数
+func Incr(key string) (int64, error) {
+	return RDB.Incr(ctx, key).Result()
+}
+
+// Publish 发布消息
+func Publish(channel string, message interface{}) error {
+	return RDB.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe 订阅频道
+func Subscribe(channels ...string) *redis.PubSub {
+	return RDB.Subscribe(ctx, channels...)
+}
+
 // Close 关闭Redis连接
 func Close() error {
 	if RDB != nil {
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/LiteMove/light-stack/internal/shared/model"
+
+	"gorm.io/gorm"
+)
+
+// DataScopeColumns 数据权限过滤使用的列
+type DataScopeColumns struct {
+	Tenant string   // 租户列，为空表示数据不区分租户
+	Dept   string   // 数据所属部门的列，为空时按归属用户所在部门判断
+	Owner  []string // 数据归属用户的列（如created_by），满足任一列即视为本人数据
+}
+
+// DataScope 按数据权限过滤列表查询
+//
+//	db.Scopes(database.DataScope(filter, database.DataScopeColumns{Tenant: "tenant_id", Dept: "dept_id", Owner: []string{"created_by"}}))
+func DataScope(filter model.DataFilter, columns DataScopeColumns) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch filter.Scope {
+		case model.DataScopeNone:
+			return db.Where("1 = 0")
+		case model.DataScopeAll:
+			return db
+		case model.DataScopeTenant:
+			return tenantDataScope(db, filter, columns)
+		case model.DataScopeDept:
+			return deptDataScope(tenantDataScope(db, filter, columns), filter, columns)
+		default:
+			// 未知的数据范围按最小范围处理
+			return ownerDataScope(tenantDataScope(db, filter, columns), filter, columns)
+		}
+	}
+}
+
+// tenantDataScope 限定本租户数据
+func tenantDataScope(db *gorm.DB, filter model.DataFilter, columns DataScopeColumns) *gorm.DB {
+	if columns.Tenant == "" {
+		return db
+	}
+	return db.Where(columns.Tenant+" = ?", filter.TenantID)
+}
+
+// ownerDataScope 限定本人数据，没有归属列时不返回任何数据
+func ownerDataScope(db *gorm.DB, filter model.DataFilter, columns DataScopeColumns) *gorm.DB {
+	conditions, args := ownerConditions(filter, columns)
+	if len(conditions) == 0 {
+		return db.Where("1 = 0")
+	}
+	return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// deptDataScope 限定本部门及下级部门的数据，同时包含本人数据
+// 调用者未分配部门时按本人数据处理
+func deptDataScope(db *gorm.DB, filter model.DataFilter, columns DataScopeColumns) *gorm.DB {
+	conditions, args := ownerConditions(filter, columns)
+	if len(filter.DeptIDs) > 0 {
+		if columns.Dept != "" {
+			conditions = append(conditions, columns.Dept+" IN ?")
+			args = append(args, filter.DeptIDs)
+		} else {
+			for _, column := range columns.Owner {
+				conditions = append(conditions, column+" IN (SELECT id FROM users WHERE dept_id IN ?)")
+				args = append(args, filter.DeptIDs)
+			}
+		}
+	}
+	if len(conditions) == 0 {
+		return db.Where("1 = 0")
+	}
+	return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// ownerConditions 本人数据的过滤条件
+func ownerConditions(filter model.DataFilter, columns DataScopeColumns) ([]string, []interface{}) {
+	if filter.UserID == 0 {
+		return nil, nil
+	}
+
+	conditions := make([]string, len(columns.Owner))
+	args := make([]interface{}, len(columns.Owner))
+	for i, column := range columns.Owner {
+		conditions[i] = column + " = ?"
+		args[i] = filter.UserID
+	}
+	return conditions, args
+}
@@ -33,6 +33,11 @@ func Init() error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// 注册租户隔离插件
+	if err := DB.Use(TenantPlugin{}); err != nil {
+		return fmt.Errorf("failed to register tenant plugin: %w", err)
+	}
+
 	// 获取底层sql.DB对象进行连接池配置
 	sqlDB, err := DB.DB()
 	if err != nil {
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/LiteMove/light-stack/internal/shared/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrTenantMismatch 写入数据的租户与当前请求租户不一致
+var ErrTenantMismatch = errors.New("禁止跨租户写入数据")
+
+// tenantContextKey 请求上下文中租户ID的键
+type tenantContextKey struct{}
+
+// skipTenantSetting 跳过租户过滤的语句设置键
+const skipTenantSetting = "tenant:skip"
+
+// WithTenant 将租户ID写入请求上下文，携带该上下文的数据库操作自动按租户隔离
+func WithTenant(ctx context.Context, tenantID uint64) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext 从上下文获取租户ID
+func TenantFromContext(ctx context.Context) (uint64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	tenantID, ok := ctx.Value(tenantContextKey{}).(uint64)
+	return tenantID, ok && tenantID != 0
+}
+
+// WithoutTenant 跳过租户过滤的查询范围，仅供超级管理员跨租户操作显式使用
+//
+//	db.WithContext(ctx).Scopes(database.WithoutTenant).Find(&users)
+func WithoutTenant(db *gorm.DB) *gorm.DB {
+	return db.Set(skipTenantSetting, true)
+}
+
+// TenantPlugin 租户隔离插件
+// 对嵌入TenantBaseModel的模型：查询、更新、删除时追加 tenant_id 条件，创建时填充 tenant_id。
+// 上下文中没有租户ID时（如后台任务、数据迁移）不做处理。
+type TenantPlugin struct{}
+
+// Name 插件名称
+func (TenantPlugin) Name() string {
+	return "tenant"
+}
+
+// Initialize 注册回调
+func (TenantPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("tenant:create", fillTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scopeTenant); err != nil {
+		return err
+	}
+	return callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scopeTenant)
+}
+
+// currentTenant 获取当前语句需要隔离的租户，返回模型是否为共享数据
+func currentTenant(db *gorm.DB) (tenantID uint64, shared bool, ok bool) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil {
+		return 0, false, false
+	}
+	if skip, _ := db.Get(skipTenantSetting); skip == true {
+		return 0, false, false
+	}
+
+	tenantID, ok = TenantFromContext(stmt.Context)
+	if !ok {
+		return 0, false, false
+	}
+
+	switch reflect.New(stmt.Schema.ModelType).Interface().(type) {
+	case model.TenantShared:
+		return tenantID, true, true
+	case model.TenantScoped:
+		return tenantID, false, true
+	default:
+		return 0, false, false
+	}
+}
+
+// scopeTenant 追加租户条件
+func scopeTenant(db *gorm.DB) {
+	tenantID, shared, ok := currentTenant(db)
+	if !ok {
+		return
+	}
+
+	column := clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}
+	var condition clause.Expression = clause.Eq{Column: column, Value: tenantID}
+	if shared {
+		condition = clause.Expr{SQL: "(? = 0 OR ? = ?)", Vars: []interface{}{column, column, tenantID}}
+	}
+
+	// 已有多个条件时先整体加括号，避免 OR 条件与租户条件优先级混淆
+	stmt := db.Statement
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 1 {
+			where.Exprs = []clause.Expression{clause.And(where.Exprs...)}
+			c.Expression = where
+			stmt.Clauses["WHERE"] = c
+		}
+	}
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{condition}})
+}
+
+// fillTenant 创建时填充租户ID，拒绝写入其他租户的数据
+func fillTenant(db *gorm.DB) {
+	tenantID, shared, ok := currentTenant(db)
+	if !ok {
+		return
+	}
+
+	field := db.Statement.Schema.LookUpField("tenant_id")
+	if field == nil {
+		return
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := setTenant(db, field, reflect.Indirect(rv.Index(i)), tenantID, shared); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := setTenant(db, field, rv, tenantID, shared); err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+// setTenant 为单条记录填充租户ID
+// 共享数据的 tenant_id 为0表示系统数据，保持原值
+func setTenant(db *gorm.DB, field *schema.Field, rv reflect.Value, tenantID uint64, shared bool) error {
+	value, isZero := field.ValueOf(db.Statement.Context, rv)
+	if isZero {
+		if shared {
+			return nil
+		}
+		return field.Set(db.Statement.Context, rv, tenantID)
+	}
+	if current, ok := value.(uint64); ok && current != tenantID {
+		return ErrTenantMismatch
+	}
+	return nil
+}
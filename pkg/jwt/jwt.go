@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"github.com/LiteMove/light-stack/internal/shared/config"
 	"time"
@@ -8,32 +10,87 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+func init() {
+	// 签发时间保留到毫秒，用于与用户级吊销时间点比较
+	jwt.TimePrecision = time.Millisecond
+}
+
 // Claims JWT声明结构
 type Claims struct {
-	UserID   uint64   `json:"userId"`
-	Username string   `json:"username"`
-	Roles    []string `json:"roles"`
+	UserID       uint64        `json:"userId"`
+	TenantID     uint64        `json:"tenantId"` // 签发时用户所属租户，请求时与解析出的租户比对
+	Username     string        `json:"username"`
+	Roles        []string      `json:"roles"`
+	FamilyID     string        `json:"fid,omitempty"` // 所属刷新token族
+	Impersonator *Impersonator `json:"imp,omitempty"` // 模拟登录时的实际操作人
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成JWT token
-func GenerateToken(userID uint64, username string, roles []string) (string, error) {
+// Impersonator 模拟登录的实际操作人（超级管理员）
+type Impersonator struct {
+	UserID   uint64 `json:"userId"`
+	Username string `json:"username"`
+	TenantID uint64 `json:"tenantId"`
+}
+
+// IsImpersonated 是否为模拟登录签发的token
+func (c *Claims) IsImpersonated() bool {
+	return c.Impersonator != nil
+}
+
+// GenerateToken 生成JWT访问token，familyID为所属刷新token族
+func GenerateToken(userID, tenantID uint64, username string, roles []string, familyID string) (string, error) {
 	cfg := config.Get()
 	if cfg == nil {
 		return "", errors.New("config not initialized")
 	}
 
-	// 创建声明
 	claims := Claims{
 		UserID:   userID,
+		TenantID: tenantID,
 		Username: username,
 		Roles:    roles,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(cfg.JWT.ExpiresIn) * time.Second)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    cfg.App.Name,
-		},
+		FamilyID: familyID,
+	}
+	return signToken(&claims, time.Duration(cfg.JWT.ExpiresIn)*time.Second)
+}
+
+// GenerateImpersonationToken 生成模拟登录访问token，不属于任何刷新token族，到期后需重新发起模拟
+func GenerateImpersonationToken(userID, tenantID uint64, username string, roles []string, impersonator *Impersonator) (*Claims, string, error) {
+	claims := &Claims{
+		UserID:       userID,
+		TenantID:     tenantID,
+		Username:     username,
+		Roles:        roles,
+		Impersonator: impersonator,
+	}
+	token, err := signToken(claims, ImpersonationExpiresIn())
+	if err != nil {
+		return nil, "", err
+	}
+	return claims, token, nil
+}
+
+// signToken 补全标准声明并签名
+func signToken(claims *Claims, expiresIn time.Duration) (string, error) {
+	cfg := config.Get()
+	if cfg == nil {
+		return "", errors.New("config not initialized")
+	}
+
+	// 生成token唯一标识，用于吊销
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        tokenID,
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Issuer:    cfg.App.Name,
 	}
 
 	// 创建token
@@ -76,24 +133,35 @@ func ParseToken(tokenString string) (*Claims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// ExpiresIn 访问token有效期（秒）
+func ExpiresIn() int {
+	cfg := config.Get()
+	if cfg == nil {
+		return 0
+	}
+	return cfg.JWT.ExpiresIn
+}
+
+// ImpersonationExpiresIn 模拟登录token有效期
+func ImpersonationExpiresIn() time.Duration {
+	cfg := config.Get()
+	if cfg == nil || cfg.JWT.ImpersonationExpiresIn <= 0 {
+		return 30 * time.Minute
+	}
+	return time.Duration(cfg.JWT.ImpersonationExpiresIn) * time.Second
+}
+
 // ValidateToken 验证token有效性
 func ValidateToken(tokenString string) bool {
 	_, err := ParseToken(tokenString)
 	return err == nil
 }
 
-// RefreshToken 刷新token
-func RefreshToken(tokenString string) (string, error) {
-	claims, err := ParseToken(tokenString)
-	if err != nil {
+// newTokenID 生成随机的token唯一标识（jti）
+func newTokenID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
 		return "", err
 	}
-
-	// 检查token是否即将过期（在过期前30分钟可以刷新）
-	if time.Until(claims.ExpiresAt.Time) > 30*time.Minute {
-		return "", errors.New("token does not need refresh yet")
-	}
-
-	// 生成新token
-	return GenerateToken(claims.UserID, claims.Username, claims.Roles)
+	return hex.EncodeToString(bytes), nil
 }
@@ -0,0 +1,183 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/config"
+	"github.com/LiteMove/light-stack/pkg/cache"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	refreshTokenKeyPrefix  = "jwt:refresh:"        // 刷新token记录，key为token的sha256
+	refreshUsedKeyPrefix   = "jwt:refresh_used:"   // 已使用的刷新token标记
+	refreshFamilyKeyPrefix = "jwt:refresh_family:" // 刷新token族，存在即有效
+)
+
+var (
+	// ErrRefreshTokenInvalid 刷新token无效或已过期
+	ErrRefreshTokenInvalid = errors.New("refresh token invalid")
+	// ErrRefreshTokenReused 刷新token被重复使用（疑似泄露）
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
+// RefreshSession 刷新token对应的会话信息
+type RefreshSession struct {
+	UserID   uint64 `json:"userId"`
+	FamilyID string `json:"familyId"`
+	IssuedAt int64  `json:"issuedAt"` // 签发时间（毫秒）
+}
+
+// RefreshExpiresIn 刷新token有效期
+func RefreshExpiresIn() time.Duration {
+	cfg := config.Get()
+	if cfg == nil || cfg.JWT.RefreshExpiresIn <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(cfg.JWT.RefreshExpiresIn) * time.Second
+}
+
+// NewTokenFamily 创建新的刷新token族，每次登录对应一个族
+func NewTokenFamily() (string, error) {
+	familyID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	if err := cache.Set(refreshFamilyKeyPrefix+familyID, 1, RefreshExpiresIn()); err != nil {
+		return "", fmt.Errorf("创建token族失败: %w", err)
+	}
+	return familyID, nil
+}
+
+// GenerateRefreshToken 在指定token族下签发不透明的刷新token
+func GenerateRefreshToken(userID uint64, familyID string) (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(bytes)
+
+	session := RefreshSession{
+		UserID:   userID,
+		FamilyID: familyID,
+		IssuedAt: time.Now().UnixMilli(),
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	ttl := RefreshExpiresIn()
+	if err := cache.Set(refreshTokenKeyPrefix+hashToken(token), data, ttl); err != nil {
+		return "", fmt.Errorf("保存刷新token失败: %w", err)
+	}
+	// 滑动续期token族
+	if err := cache.Expire(refreshFamilyKeyPrefix+familyID, ttl); err != nil {
+		return "", fmt.Errorf("续期token族失败: %w", err)
+	}
+
+	return token, nil
+}
+
+// UseRefreshToken 消费刷新token，每个刷新token只能使用一次
+// 重复使用已消费的token会吊销整个token族
+func UseRefreshToken(token string) (*RefreshSession, error) {
+	tokenHash := hashToken(token)
+
+	data, err := cache.Get(refreshTokenKeyPrefix + tokenHash)
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询刷新token失败: %w", err)
+	}
+
+	var session RefreshSession
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// token族已被吊销（登出或检测到重用）
+	active, err := IsFamilyActive(session.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// 用户已登出所有会话
+	revokedBefore, err := userRevokedBefore(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if session.IssuedAt < revokedBefore {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// 原子标记为已使用，标记失败说明该token已被使用过
+	ok, err := cache.SetNX(refreshUsedKeyPrefix+tokenHash, 1, RefreshExpiresIn())
+	if err != nil {
+		return nil, fmt.Errorf("标记刷新token失败: %w", err)
+	}
+	if !ok {
+		if err := RevokeTokenFamily(session.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	return &session, nil
+}
+
+// RevokeTokenFamily 吊销token族，族内的刷新token和访问token全部失效
+func RevokeTokenFamily(familyID string) error {
+	if familyID == "" {
+		return nil
+	}
+	if err := cache.Del(refreshFamilyKeyPrefix + familyID); err != nil {
+		return fmt.Errorf("吊销token族失败: %w", err)
+	}
+	return nil
+}
+
+// IsFamilyActive 检查token族是否有效
+func IsFamilyActive(familyID string) (bool, error) {
+	exists, err := cache.Exists(refreshFamilyKeyPrefix + familyID)
+	if err != nil {
+		return false, fmt.Errorf("查询token族状态失败: %w", err)
+	}
+	return exists, nil
+}
+
+// userRevokedBefore 获取用户级吊销时间点（毫秒），未吊销返回0
+func userRevokedBefore(userID uint64) (int64, error) {
+	value, err := cache.Get(revokedUserKeyPrefix + strconv.FormatUint(userID, 10))
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("查询用户吊销状态失败: %w", err)
+	}
+
+	revokedBefore, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		// 无法解析时按当前时间处理，视为全部吊销
+		return time.Now().UnixMilli(), nil
+	}
+	return revokedBefore, nil
+}
+
+// hashToken 计算刷新token的哈希，Redis中不保存明文
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/config"
+	"github.com/LiteMove/light-stack/pkg/cache"
+)
+
+const (
+	revokedTokenKeyPrefix = "jwt:revoked:"        // 单个token吊销记录，key为jti
+	revokedUserKeyPrefix  = "jwt:revoked_before:" // 用户级吊销时间点（毫秒），早于该时间签发的token均失效
+)
+
+// RevokeToken 吊销单个token，记录保留到token自然过期
+func RevokeToken(claims *Claims) error {
+	if claims == nil || claims.ID == "" {
+		return errors.New("token缺少jti，无法吊销")
+	}
+
+	ttl := time.Minute
+	if claims.ExpiresAt != nil {
+		ttl = time.Until(claims.ExpiresAt.Time)
+	}
+	if ttl <= 0 {
+		// 已过期的token无需吊销
+		return nil
+	}
+
+	return cache.Set(revokedTokenKeyPrefix+claims.ID, 1, ttl)
+}
+
+// RevokeUserTokens 吊销用户所有已签发的token（登出所有会话）
+func RevokeUserTokens(userID uint64) error {
+	cfg := config.Get()
+	if cfg == nil {
+		return errors.New("config not initialized")
+	}
+
+	// 保留时长覆盖访问token和刷新token的有效期，之后签发时间早于该点的token都已自然过期
+	ttl := time.Duration(cfg.JWT.ExpiresIn) * time.Second
+	if refreshTTL := RefreshExpiresIn(); refreshTTL > ttl {
+		ttl = refreshTTL
+	}
+	return cache.Set(revokedUserKeyPrefix+strconv.FormatUint(userID, 10), time.Now().UnixMilli(), ttl)
+}
+
+// IsRevoked 检查token是否已被吊销
+func IsRevoked(claims *Claims) (bool, error) {
+	if claims.ID != "" {
+		exists, err := cache.Exists(revokedTokenKeyPrefix + claims.ID)
+		if err != nil {
+			return false, fmt.Errorf("查询token吊销状态失败: %w", err)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+
+	// 所属token族已被吊销
+	if claims.FamilyID != "" {
+		active, err := IsFamilyActive(claims.FamilyID)
+		if err != nil {
+			return false, err
+		}
+		if !active {
+			return true, nil
+		}
+	}
+
+	revoked, err := isUserRevoked(claims.UserID, claims)
+	if err != nil || revoked {
+		return revoked, err
+	}
+
+	// 模拟登录的token随操作人的会话一同失效
+	if claims.Impersonator != nil {
+		return isUserRevoked(claims.Impersonator.UserID, claims)
+	}
+	return false, nil
+}
+
+// isUserRevoked 检查token是否签发于用户级吊销时间点之前
+func isUserRevoked(userID uint64, claims *Claims) (bool, error) {
+	revokedBefore, err := userRevokedBefore(userID)
+	if err != nil {
+		return false, err
+	}
+	if revokedBefore == 0 {
+		return false, nil
+	}
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+
+	return claims.IssuedAt.UnixMilli() < revokedBefore, nil
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/LiteMove/light-stack/pkg/logger"
+)
+
+// fileMailer 本地开发和测试使用的邮件发送实现，将邮件写入目录或日志，不实际发送
+type fileMailer struct {
+	dir  string
+	from Sender
+}
+
+// NewFileMailer 创建文件邮件发送器，dir为空时仅输出到日志
+func NewFileMailer(dir string, from Sender) Mailer {
+	return &fileMailer{dir: dir, from: from}
+}
+
+// Send 保存邮件为 .eml 文件并记录日志
+func (m *fileMailer) Send(msg *Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"to":      strings.Join(msg.To, ","),
+		"subject": msg.Subject,
+	}
+
+	if m.dir == "" {
+		fields["body"] = msg.Body
+		logger.WithFields(fields).Info("Mail captured (not sent)")
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create mail dir: %w", err)
+	}
+
+	filename := filepath.Join(m.dir, fmt.Sprintf("%d.eml", time.Now().UnixNano()))
+	if err := os.WriteFile(filename, msg.build(m.from), 0644); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	fields["file"] = filename
+	logger.WithFields(fields).Info("Mail captured (not sent)")
+	return nil
+}
@@ -0,0 +1,91 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/config"
+)
+
+// Message 邮件消息
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+	HTML    bool // 正文是否为HTML
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	// Send 发送邮件
+	Send(msg *Message) error
+}
+
+// Sender 发件人信息
+type Sender struct {
+	Address string // 发件地址
+	Name    string // 发件人名称
+}
+
+// String 格式化发件人，如 "Light Stack" <noreply@example.com>
+func (s Sender) String() string {
+	if s.Name == "" {
+		return s.Address
+	}
+	return fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("utf-8", s.Name), s.Address)
+}
+
+// validate 校验邮件消息
+func (m *Message) validate() error {
+	if len(m.To) == 0 {
+		return errors.New("mail recipient is required")
+	}
+	for _, to := range m.To {
+		if strings.ContainsAny(to, "\r\n") {
+			return errors.New("invalid mail recipient")
+		}
+	}
+	if strings.ContainsAny(m.Subject, "\r\n") {
+		return errors.New("invalid mail subject")
+	}
+	return nil
+}
+
+// build 构建RFC 5322格式的邮件内容
+func (m *Message) build(from Sender) []byte {
+	contentType := "text/plain"
+	if m.HTML {
+		contentType = "text/html"
+	}
+
+	var builder strings.Builder
+	builder.WriteString("From: " + from.String() + "\r\n")
+	builder.WriteString("To: " + strings.Join(m.To, ", ") + "\r\n")
+	builder.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", m.Subject) + "\r\n")
+	builder.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: " + contentType + "; charset=UTF-8\r\n")
+	builder.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return []byte(builder.String())
+}
+
+// NewFromConfig 根据全局邮件配置创建邮件发送器
+func NewFromConfig(cfg config.MailConfig) Mailer {
+	from := Sender{Address: cfg.From, Name: cfg.FromName}
+	if cfg.Driver == "smtp" {
+		return NewSMTPMailer(SMTPConfig{
+			Host:     cfg.Host,
+			Port:     cfg.Port,
+			Username: cfg.Username,
+			Password: cfg.Password,
+			From:     from,
+			UseTLS:   cfg.UseTLS,
+		})
+	}
+	return NewFileMailer(cfg.FileDir, from)
+}
@@ -0,0 +1,110 @@
+package mailer
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig SMTP发送配置
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     Sender
+	UseTLS   bool // 是否使用隐式TLS（通常为465端口），否则在服务器支持时使用STARTTLS
+}
+
+// smtpMailer SMTP邮件发送实现
+type smtpMailer struct {
+	config SMTPConfig
+}
+
+// NewSMTPMailer 创建SMTP邮件发送器
+func NewSMTPMailer(config SMTPConfig) Mailer {
+	if config.Port == 0 {
+		config.Port = 25
+	}
+	if config.From.Address == "" {
+		config.From.Address = config.Username
+	}
+	return &smtpMailer{config: config}
+}
+
+// Send 发送邮件
+func (m *smtpMailer) Send(msg *Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	client, err := m.dial()
+	if err != nil {
+		return fmt.Errorf("failed to connect smtp server: %w", err)
+	}
+	defer client.Close()
+
+	// 非隐式TLS连接时，服务器支持则升级为STARTTLS
+	if !m.config.UseTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: m.config.Host}); err != nil {
+				return fmt.Errorf("failed to start tls: %w", err)
+			}
+		}
+	}
+
+	if m.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+			if err := client.Auth(auth); err != nil {
+				return fmt.Errorf("smtp auth failed: %w", err)
+			}
+		}
+	}
+
+	if err := client.Mail(m.config.From.Address); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(msg.build(m.config.From)); err != nil {
+		writer.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// dial 建立SMTP连接
+func (m *smtpMailer) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	if m.config.UseTLS {
+		conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: m.config.Host})
+		if err != nil {
+			return nil, err
+		}
+		return smtp.NewClient(conn, m.config.Host)
+	}
+
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return smtp.NewClient(conn, m.config.Host)
+}
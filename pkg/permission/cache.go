@@ -2,88 +2,160 @@ package permission
 
 import (
 	"sync"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/model"
 )
 
+// localTTL 进程内缓存有效期，用于兜底丢失的失效通知
+const localTTL = 5 * time.Minute
+
+// PermissionCache 用户权限缓存
+// 进程内缓存未命中时从Redis共享缓存读取，Redis未命中或版本过期时从数据库加载
 type PermissionCache struct {
 	sync.RWMutex
-	userPermissions map[uint64]map[string]bool
-	userRoles       map[uint64]map[string]bool
+	entries    map[uint64]*cacheEntry
+	generation uint64 // 每次清除时递增，避免加载期间发生的失效被旧数据覆盖
+}
+
+// cacheEntry 进程内缓存的用户权限和角色
+type cacheEntry struct {
+	permissions map[string]bool
+	roles       map[string]bool
+	dataScope   model.DataScope // 用户所有角色中最大的数据权限范围
+	deptIDs     []uint64        // 用户所在部门及其下级部门，仅"本部门"范围时加载
+	loadedAt    time.Time
 }
 
 var Cache = &PermissionCache{
-	userPermissions: make(map[uint64]map[string]bool),
-	userRoles:       make(map[uint64]map[string]bool),
+	entries: make(map[uint64]*cacheEntry),
+}
+
+// newCacheEntry 创建缓存项
+func newCacheEntry(permissions, roles []string, dataScope model.DataScope, deptIDs []uint64) *cacheEntry {
+	entry := &cacheEntry{
+		permissions: make(map[string]bool, len(permissions)),
+		roles:       make(map[string]bool, len(roles)),
+		dataScope:   dataScope,
+		deptIDs:     deptIDs,
+		loadedAt:    time.Now(),
+	}
+	for _, perm := range permissions {
+		entry.permissions[perm] = true
+	}
+	for _, role := range roles {
+		entry.roles[role] = true
+	}
+	return entry
 }
 
-func (p *PermissionCache) LoadUserPermissions(userID uint64, permissions []string) {
+// set 写入进程内缓存
+func (p *PermissionCache) set(userID uint64, entry *cacheEntry) {
 	p.Lock()
 	defer p.Unlock()
 
-	userPerms := make(map[string]bool, len(permissions))
-	for _, perm := range permissions {
-		userPerms[perm] = true
+	p.entries[userID] = entry
+}
+
+// get 获取用户缓存项，未命中或已过期时懒加载，加载失败返回nil
+func (p *PermissionCache) get(userID uint64) *cacheEntry {
+	p.RLock()
+	entry, exists := p.entries[userID]
+	generation := p.generation
+	p.RUnlock()
+
+	if exists && time.Since(entry.loadedAt) < localTTL {
+		return entry
+	}
+
+	entry, err := loadUser(userID)
+	if err != nil {
+		return nil
 	}
-	p.userPermissions[userID] = userPerms
+
+	p.Lock()
+	if p.generation == generation {
+		p.entries[userID] = entry
+	}
+	p.Unlock()
+	return entry
 }
 
-func (p *PermissionCache) LoadUserRoles(userID uint64, roles []string) {
+// clear 清除进程内缓存
+func (p *PermissionCache) clear(userIDs ...uint64) {
 	p.Lock()
 	defer p.Unlock()
 
-	userRoles := make(map[string]bool, len(roles))
-	for _, role := range roles {
-		userRoles[role] = true
+	for _, userID := range userIDs {
+		delete(p.entries, userID)
 	}
-	p.userRoles[userID] = userRoles
+	p.generation++
 }
 
-func (p *PermissionCache) GetUserPermissions(userID uint64) (map[string]bool, bool) {
-	p.RLock()
-	defer p.RUnlock()
+// clearAll 清除全部进程内缓存
+func (p *PermissionCache) clearAll() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.entries = make(map[uint64]*cacheEntry)
+	p.generation++
+}
 
-	perms, exists := p.userPermissions[userID]
-	return perms, exists
+func (p *PermissionCache) GetUserPermissions(userID uint64) (map[string]bool, bool) {
+	entry := p.get(userID)
+	if entry == nil {
+		return nil, false
+	}
+	return entry.permissions, true
 }
 
 func (p *PermissionCache) GetUserRoles(userID uint64) (map[string]bool, bool) {
-	p.RLock()
-	defer p.RUnlock()
+	entry := p.get(userID)
+	if entry == nil {
+		return nil, false
+	}
+	return entry.roles, true
+}
 
-	roles, exists := p.userRoles[userID]
-	return roles, exists
+// GetUserDataScope 获取用户的数据权限范围，加载失败或没有角色时为仅本人数据
+func (p *PermissionCache) GetUserDataScope(userID uint64) model.DataScope {
+	entry := p.get(userID)
+	if entry == nil || !entry.dataScope.Valid() {
+		return model.DataScopeSelf
+	}
+	return entry.dataScope
 }
 
-func (p *PermissionCache) ClearUserPermissions(userID uint64) {
-	p.Lock()
-	defer p.Unlock()
+// GetUserDeptIDs 获取用户所在部门及其下级部门的ID
+func (p *PermissionCache) GetUserDeptIDs(userID uint64) []uint64 {
+	entry := p.get(userID)
+	if entry == nil {
+		return nil
+	}
+	return entry.deptIDs
+}
 
-	delete(p.userPermissions, userID)
-	delete(p.userRoles, userID)
+func (p *PermissionCache) ClearUserPermissions(userID uint64) {
+	p.clear(userID)
 }
 
 func (p *PermissionCache) HasPermission(userID uint64, code string) bool {
-	p.RLock()
-	defer p.RUnlock()
-
-	perms, exists := p.userPermissions[userID]
-	if !exists {
+	entry := p.get(userID)
+	if entry == nil {
 		return false
 	}
 
-	return perms[code]
+	return entry.permissions[code]
 }
 
 func (p *PermissionCache) HasAnyPermission(userID uint64, codes ...string) bool {
-	p.RLock()
-	defer p.RUnlock()
-
-	perms, exists := p.userPermissions[userID]
-	if !exists {
+	entry := p.get(userID)
+	if entry == nil {
 		return false
 	}
 
 	for _, code := range codes {
-		if perms[code] {
+		if entry.permissions[code] {
 			return true
 		}
 	}
@@ -92,28 +164,22 @@ func (p *PermissionCache) HasAnyPermission(userID uint64, codes ...string) bool
 }
 
 func (p *PermissionCache) HasRole(userID uint64, roleCode string) bool {
-	p.RLock()
-	defer p.RUnlock()
-
-	roles, exists := p.userRoles[userID]
-	if !exists {
+	entry := p.get(userID)
+	if entry == nil {
 		return false
 	}
 
-	return roles[roleCode]
+	return entry.roles[roleCode]
 }
 
 func (p *PermissionCache) HasAnyRole(userID uint64, roleCodes ...string) bool {
-	p.RLock()
-	defer p.RUnlock()
-
-	roles, exists := p.userRoles[userID]
-	if !exists {
+	entry := p.get(userID)
+	if entry == nil {
 		return false
 	}
 
 	for _, code := range roleCodes {
-		if roles[code] {
+		if entry.roles[code] {
 			return true
 		}
 	}
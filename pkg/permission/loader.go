@@ -1,54 +1,251 @@
 package permission
 
 import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
 	repository2 "github.com/LiteMove/light-stack/internal/modules/system/repository"
+	"github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/pkg/cache"
 	"github.com/LiteMove/light-stack/pkg/logger"
 )
 
-func LoadUserPermissions(userID uint64, menuRepo repository2.MenuRepository) error {
-	permissions, err := menuRepo.GetUserPermissions(userID)
+const (
+	dataKeyPrefix        = "perm:data:"      // 用户权限数据
+	userVersionKeyPrefix = "perm:ver:user:"  // 用户级版本号，用户角色变化时递增
+	globalVersionKey     = "perm:ver:global" // 全局版本号，菜单权限码变化时递增
+	invalidateChannel    = "perm:invalidate" // 失效通知频道，消息为逗号分隔的用户ID或"*"
+
+	dataTTL = 24 * time.Hour
+)
+
+var (
+	menuRepo repository2.MenuRepository
+	roleRepo repository2.RoleRepository
+	deptRepo repository2.DeptRepository
+)
+
+// storedPermissions Redis中保存的用户权限数据，版本号与当前版本不一致时视为过期
+type storedPermissions struct {
+	GlobalVersion int64           `json:"globalVersion"`
+	UserVersion   int64           `json:"userVersion"`
+	Permissions   []string        `json:"permissions"`
+	Roles         []string        `json:"roles"`
+	DataScope     model.DataScope `json:"dataScope"`
+	DeptIDs       []uint64        `json:"deptIds,omitempty"`
+}
+
+// Init 设置权限数据来源，并订阅其他实例发出的失效通知
+func Init(menuRepository repository2.MenuRepository, roleRepository repository2.RoleRepository, deptRepository repository2.DeptRepository) {
+	menuRepo = menuRepository
+	roleRepo = roleRepository
+	deptRepo = deptRepository
+
+	if cache.RDB != nil {
+		go subscribeInvalidation()
+	}
+}
+
+func LoadUserData(userID uint64, menuRepo repository2.MenuRepository, roleRepo repository2.RoleRepository) error {
+	globalVersion, userVersion := currentVersions(userID)
+
+	entry, err := loadFromDB(userID, menuRepo, roleRepo, globalVersion, userVersion)
 	if err != nil {
-		logger.WithField("userId", userID).Error("Failed to load user permissions:", err)
 		return err
 	}
 
-	Cache.LoadUserPermissions(userID, permissions)
+	Cache.set(userID, entry)
 	logger.WithField("userId", userID).Debug("User permissions loaded successfully")
 	return nil
 }
 
-func LoadUserRoles(userID uint64, roleRepo repository2.RoleRepository) error {
+func ClearUserPermissions(userID uint64) {
+	InvalidateUsers(userID)
+	logger.WithField("userId", userID).Debug("User permissions cleared")
+}
+
+// InvalidateUsers 使指定用户的权限缓存失效，并通知其他实例
+func InvalidateUsers(userIDs ...uint64) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	Cache.clear(userIDs...)
+	if cache.RDB == nil {
+		return
+	}
+
+	ids := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		uid := strconv.FormatUint(userID, 10)
+		if _, err := cache.Incr(userVersionKeyPrefix + uid); err != nil {
+			logger.WithField("userId", userID).Error("Failed to bump permission version:", err)
+		}
+		ids = append(ids, uid)
+	}
+
+	if err := cache.Publish(invalidateChannel, strings.Join(ids, ",")); err != nil {
+		logger.Error("Failed to publish permission invalidation:", err)
+	}
+}
+
+// InvalidateRoleUsers 使拥有该角色的用户权限缓存失效（角色菜单或状态变化时调用）
+func InvalidateRoleUsers(roleID uint64) {
+	if roleRepo == nil {
+		InvalidateAll()
+		return
+	}
+
+	userIDs, err := roleRepo.GetRoleUserIDs(roleID)
+	if err != nil {
+		logger.WithField("roleId", roleID).Error("Failed to get role users, invalidating all permissions:", err)
+		InvalidateAll()
+		return
+	}
+	InvalidateUsers(userIDs...)
+}
+
+// InvalidateAll 使所有用户的权限缓存失效（菜单权限码变化等影响范围不确定时调用）
+func InvalidateAll() {
+	Cache.clearAll()
+	if cache.RDB == nil {
+		return
+	}
+
+	if _, err := cache.Incr(globalVersionKey); err != nil {
+		logger.Error("Failed to bump global permission version:", err)
+	}
+	if err := cache.Publish(invalidateChannel, "*"); err != nil {
+		logger.Error("Failed to publish permission invalidation:", err)
+	}
+}
+
+// loadUser 从Redis加载用户权限，未命中或版本过期时从数据库加载并回写Redis
+func loadUser(userID uint64) (*cacheEntry, error) {
+	if menuRepo == nil || roleRepo == nil {
+		return nil, errors.New("permission cache not initialized")
+	}
+	if cache.RDB == nil {
+		return loadFromDB(userID, menuRepo, roleRepo, 0, 0)
+	}
+
+	uid := strconv.FormatUint(userID, 10)
+	values, err := cache.MGet(globalVersionKey, userVersionKeyPrefix+uid, dataKeyPrefix+uid)
+	if err != nil {
+		logger.WithField("userId", userID).Warn("Failed to read permission cache, loading from database:", err)
+		return loadFromDB(userID, menuRepo, roleRepo, 0, 0)
+	}
+
+	globalVersion := parseVersion(values[0])
+	userVersion := parseVersion(values[1])
+	if data, ok := values[2].(string); ok {
+		var stored storedPermissions
+		if err := json.Unmarshal([]byte(data), &stored); err == nil &&
+			stored.GlobalVersion == globalVersion && stored.UserVersion == userVersion &&
+			(stored.DataScope.Valid() || len(stored.Roles) == 0) {
+			return newCacheEntry(stored.Permissions, stored.Roles, stored.DataScope, stored.DeptIDs), nil
+		}
+	}
+
+	return loadFromDB(userID, menuRepo, roleRepo, globalVersion, userVersion)
+}
+
+// loadFromDB 从数据库加载用户权限和角色，并以加载前读取的版本号写入Redis
+// 加载期间发生的失效会递增版本号，写入的数据随即被视为过期
+func loadFromDB(userID uint64, menuRepo repository2.MenuRepository, roleRepo repository2.RoleRepository, globalVersion, userVersion int64) (*cacheEntry, error) {
+	permissions, err := menuRepo.GetUserPermissions(userID)
+	if err != nil {
+		logger.WithField("userId", userID).Error("Failed to load user permissions:", err)
+		return nil, err
+	}
+
 	roles, err := roleRepo.GetUserRoles(userID)
 	if err != nil {
 		logger.WithField("userId", userID).Error("Failed to load user roles:", err)
-		return err
+		return nil, err
 	}
-
 	roleCodes := make([]string, len(roles))
+	var dataScope model.DataScope
 	for i, role := range roles {
 		roleCodes[i] = role.Code
+		dataScope = dataScope.Wider(role.DataScope)
 	}
 
-	Cache.LoadUserRoles(userID, roleCodes)
-	logger.WithField("userId", userID).Debug("User roles loaded successfully")
-	return nil
+	// "本部门"范围需要用户所在部门及其下级部门
+	var deptIDs []uint64
+	if dataScope == model.DataScopeDept && deptRepo != nil {
+		deptIDs, err = deptRepo.GetUserDeptSubtreeIDs(userID)
+		if err != nil {
+			logger.WithField("userId", userID).Error("Failed to load user departments:", err)
+			return nil, err
+		}
+	}
+
+	if cache.RDB != nil {
+		data, err := json.Marshal(storedPermissions{
+			GlobalVersion: globalVersion,
+			UserVersion:   userVersion,
+			Permissions:   permissions,
+			Roles:         roleCodes,
+			DataScope:     dataScope,
+			DeptIDs:       deptIDs,
+		})
+		if err == nil {
+			err = cache.Set(dataKeyPrefix+strconv.FormatUint(userID, 10), data, dataTTL)
+		}
+		if err != nil {
+			logger.WithField("userId", userID).Warn("Failed to save permission cache:", err)
+		}
+	}
+
+	return newCacheEntry(permissions, roleCodes, dataScope, deptIDs), nil
 }
 
-func LoadUserData(userID uint64, menuRepo repository2.MenuRepository, roleRepo repository2.RoleRepository) error {
-	// 加载权限
-	if err := LoadUserPermissions(userID, menuRepo); err != nil {
-		return err
+// currentVersions 读取当前的全局版本号和用户版本号
+func currentVersions(userID uint64) (int64, int64) {
+	if cache.RDB == nil {
+		return 0, 0
 	}
 
-	// 加载角色
-	if err := LoadUserRoles(userID, roleRepo); err != nil {
-		return err
+	values, err := cache.MGet(globalVersionKey, userVersionKeyPrefix+strconv.FormatUint(userID, 10))
+	if err != nil {
+		logger.WithField("userId", userID).Warn("Failed to read permission version:", err)
+		return 0, 0
 	}
+	return parseVersion(values[0]), parseVersion(values[1])
+}
 
-	return nil
+// parseVersion 解析版本号，不存在时为0
+func parseVersion(value interface{}) int64 {
+	str, ok := value.(string)
+	if !ok {
+		return 0
+	}
+	version, _ := strconv.ParseInt(str, 10, 64)
+	return version
 }
 
-func ClearUserPermissions(userID uint64) {
-	Cache.ClearUserPermissions(userID)
-	logger.WithField("userId", userID).Debug("User permissions cleared")
+// subscribeInvalidation 订阅失效通知，清除本实例的进程内缓存
+// 断线重连期间丢失的通知由进程内缓存的有效期兜底
+func subscribeInvalidation() {
+	pubsub := cache.Subscribe(invalidateChannel)
+	defer pubsub.Close()
+
+	for msg := range pubsub.Channel() {
+		if msg.Payload == "*" {
+			Cache.clearAll()
+			continue
+		}
+
+		var userIDs []uint64
+		for _, value := range strings.Split(msg.Payload, ",") {
+			if userID, err := strconv.ParseUint(value, 10, 64); err == nil {
+				userIDs = append(userIDs, userID)
+			}
+		}
+		Cache.clear(userIDs...)
+	}
 }
@@ -0,0 +1,59 @@
+package permission
+
+import (
+	"sort"
+	"sync"
+)
+
+// RouteInfo 受保护路由的权限声明
+type RouteInfo struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Permissions []string `json:"permissions"` // 满足任一权限码即可访问
+	SuperAdmin  bool     `json:"superAdmin"`  // 仅超级管理员可访问
+}
+
+var (
+	routesMu sync.RWMutex
+	routes   []RouteInfo
+)
+
+// RegisterRoute 登记路由的权限声明，由路由注册时调用
+func RegisterRoute(info RouteInfo) {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+
+	routes = append(routes, info)
+}
+
+// Routes 获取已登记的路由权限清单，按路径和方法排序
+func Routes() []RouteInfo {
+	routesMu.RLock()
+	result := make([]RouteInfo, len(routes))
+	copy(result, routes)
+	routesMu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].Method < result[j].Method
+	})
+	return result
+}
+
+// RouteCodes 获取清单中声明的全部权限码（去重）
+func RouteCodes() []string {
+	seen := make(map[string]bool)
+	var codes []string
+	for _, route := range Routes() {
+		for _, code := range route.Permissions {
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -15,6 +15,16 @@ type Response struct {
 	Timestamp int64       `json:"timestamp"`
 }
 
+// 租户相关的业务错误码，随403响应返回，便于前端区分处理
+const (
+	CodeTenantDisabled = 40301 // 租户已被禁用
+	CodeTenantExpired  = 40302 // 租户已过期
+	CodeTenantMismatch = 40303 // 登录凭证与当前租户不匹配
+)
+
+// CodeImpersonationForbidden 模拟登录会话禁止执行该操作，随403响应返回
+const CodeImpersonationForbidden = 40310
+
 // Success 成功响应
 func Success(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, Response{
@@ -65,6 +75,16 @@ func Forbidden(c *gin.Context, message string) {
 	})
 }
 
+// ForbiddenWithCode 403错误，携带具体业务错误码
+func ForbiddenWithCode(c *gin.Context, code int, message string) {
+	c.JSON(http.StatusForbidden, Response{
+		Code:      code,
+		Message:   message,
+		Data:      nil,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
 // NotFound 404错误
 func NotFound(c *gin.Context, message string) {
 	c.JSON(http.StatusNotFound, Response{
@@ -0,0 +1,122 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamavChunkSize INSTREAM 每次发送的数据块大小
+const clamavChunkSize = 64 << 10
+
+// clamavScanner 通过 clamd 的 INSTREAM 命令扫描文件
+type clamavScanner struct {
+	network string
+	address string
+	timeout time.Duration // 连接、每个数据块发送和等待结果的超时
+	maxSize int64         // 允许发送的最大字节数，应与 clamd 的 StreamMaxLength 一致，0 表示不限制
+}
+
+// NewClamAVScanner 创建ClamAV扫描器，address 为 unix:/path/to/clamd.sock 或 host:port
+func NewClamAVScanner(address string, timeout time.Duration, maxSize int64) Scanner {
+	network := "tcp"
+	if strings.HasPrefix(address, "unix:") {
+		network = "unix"
+		address = strings.TrimPrefix(address, "unix:")
+	} else {
+		address = strings.TrimPrefix(address, "tcp:")
+	}
+	return &clamavScanner{network: network, address: address, timeout: timeout, maxSize: maxSize}
+}
+
+// Scan 以数据块形式发送文件内容，按 clamd 的响应判断是否检出威胁
+// 超时按每次网络操作计算，大文件的总耗时不受限制；超出大小限制时返回 ErrTooLarge
+func (s *clamavScanner) Scan(r io.Reader) (*Result, error) {
+	conn, err := net.DialTimeout(s.network, s.address, s.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	if err := s.write(conn, []byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("failed to send clamd command: %w", err)
+	}
+
+	buf := make([]byte, clamavChunkSize)
+	size := make([]byte, 4)
+	var sent int64
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			if sent += int64(n); s.maxSize > 0 && sent > s.maxSize {
+				return nil, ErrTooLarge
+			}
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if err := s.write(conn, append(size, buf[:n]...)); err != nil {
+				// clamd 超出 StreamMaxLength 时会回复错误并关闭连接
+				if reply, replyErr := s.readReply(conn); replyErr == nil {
+					if _, err := parseClamAVReply(reply); errors.Is(err, ErrTooLarge) {
+						return nil, err
+					}
+				}
+				return nil, fmt.Errorf("failed to send data to clamd: %w", err)
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read file: %w", readErr)
+		}
+	}
+
+	// 长度为0的数据块表示结束
+	binary.BigEndian.PutUint32(size, 0)
+	if err := s.write(conn, size); err != nil {
+		return nil, fmt.Errorf("failed to send data to clamd: %w", err)
+	}
+
+	reply, err := s.readReply(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseClamAVReply(reply)
+}
+
+// write 发送数据，每次发送单独计算超时
+func (s *clamavScanner) write(conn net.Conn, data []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	_, err := conn.Write(data)
+	return err
+}
+
+// readReply 读取 clamd 以 \x00 结尾的响应
+func (s *clamavScanner) readReply(conn net.Conn) (string, error) {
+	conn.SetReadDeadline(time.Now().Add(s.timeout))
+	reply, err := bufio.NewReader(conn).ReadString('\x00')
+	if err != nil && (!errors.Is(err, io.EOF) || reply == "") {
+		return "", err
+	}
+	return strings.TrimRight(reply, "\x00\n"), nil
+}
+
+// parseClamAVReply 解析 clamd 响应，如 "stream: OK"、"stream: Eicar-Signature FOUND"、
+// "INSTREAM size limit exceeded. ERROR"
+func parseClamAVReply(reply string) (*Result, error) {
+	status := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case strings.Contains(status, "size limit exceeded"):
+		return nil, ErrTooLarge
+	case status == "OK":
+		return &Result{Clean: true}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return &Result{Threat: strings.TrimSuffix(status, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd error: %s", reply)
+	}
+}
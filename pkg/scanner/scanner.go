@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/LiteMove/light-stack/internal/shared/config"
+)
+
+// ErrTooLarge 文件超出扫描器允许的大小，无法扫描，重试也不会成功
+var ErrTooLarge = errors.New("file exceeds scanner size limit")
+
+// Result 扫描结果
+type Result struct {
+	Clean  bool   // 未检出威胁
+	Threat string // 检出的威胁名称
+}
+
+// Scanner 文件病毒扫描接口
+type Scanner interface {
+	// Scan 扫描文件内容，扫描器不可用或扫描出错时返回错误
+	Scan(r io.Reader) (*Result, error)
+}
+
+// noopScanner 不做扫描的默认实现，所有文件视为安全
+type noopScanner struct{}
+
+// NewNoopScanner 创建不做扫描的扫描器
+func NewNoopScanner() Scanner {
+	return noopScanner{}
+}
+
+// Scan 直接返回安全
+func (noopScanner) Scan(r io.Reader) (*Result, error) {
+	return &Result{Clean: true}, nil
+}
+
+// Enabled 是否启用了实际的扫描器，未启用时上传文件无需隔离
+func Enabled(s Scanner) bool {
+	if s == nil {
+		return false
+	}
+	_, noop := s.(noopScanner)
+	return !noop
+}
+
+// NewFromConfig 根据全局扫描配置创建扫描器
+func NewFromConfig(cfg config.ScannerConfig) Scanner {
+	if cfg.Driver == "clamav" {
+		timeout := time.Duration(cfg.Timeout) * time.Second
+		if timeout <= 0 {
+			timeout = time.Minute
+		}
+		return NewClamAVScanner(cfg.Address, timeout, cfg.MaxSize)
+	}
+	return NewNoopScanner()
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/LiteMove/light-stack/internal/model"
 	"github.com/LiteMove/light-stack/internal/service"
+	"github.com/LiteMove/light-stack/internal/shared/middleware"
 	"github.com/LiteMove/light-stack/pkg/response"
 )
 
@@ -161,7 +162,7 @@ func (c *{{.ClassName}}Controller) GetList(ctx *gin.Context) {
 		query.PageSize = 10
 	}
 
-	{{pluralize (uncapitalize .BusinessName)}}, total, err := c.service.GetList(&query)
+	{{pluralize (uncapitalize .BusinessName)}}, total, err := c.service.GetList(middleware.GetDataFilterFromContext(ctx), &query)
 {{- else }}
 	page := 1
 	pageSize := 10
@@ -178,7 +179,7 @@ func (c *{{.ClassName}}Controller) GetList(ctx *gin.Context) {
 		}
 	}
 
-	{{pluralize (uncapitalize .BusinessName)}}, total, err := c.service.GetList(page, pageSize)
+	{{pluralize (uncapitalize .BusinessName)}}, total, err := c.service.GetList(middleware.GetDataFilterFromContext(ctx), page, pageSize)
 {{- end }}
 	if err != nil {
 		response.Error(ctx, response.CodeServerError, "查询{{.FunctionName}}列表失败: "+err.Error())
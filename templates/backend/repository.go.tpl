@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/LiteMove/light-stack/internal/model"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
+	"github.com/LiteMove/light-stack/pkg/database"
 	"gorm.io/gorm"
 )
 
@@ -12,6 +14,21 @@ type {{.ClassName}}Repository struct {
 	db *gorm.DB
 }
 
+// {{uncapitalize .ClassName}}DataScopeColumns {{.FunctionName}}数据权限过滤使用的列
+var {{uncapitalize .ClassName}}DataScopeColumns = database.DataScopeColumns{
+{{- if hasColumn .Fields "tenant_id" }}
+	Tenant: "tenant_id",
+{{- end }}
+{{- if hasColumn .Fields "dept_id" }}
+	Dept: "dept_id",
+{{- end }}
+{{- if hasColumn .Fields "created_by" }}
+	Owner: []string{"created_by"},
+{{- else if hasColumn .Fields "create_by" }}
+	Owner: []string{"create_by"},
+{{- end }}
+}
+
 // New{{.ClassName}}Repository 创建{{.FunctionName}}仓储
 func New{{.ClassName}}Repository(db *gorm.DB) *{{.ClassName}}Repository {
 	return &{{.ClassName}}Repository{
@@ -58,12 +75,12 @@ func (r *{{.ClassName}}Repository) Delete(id uint64) error {
 	return nil
 }
 
-// GetList 获取{{.FunctionName}}列表
-func (r *{{.ClassName}}Repository) GetList({{- if .HasQuery }}query *model.{{.ClassName}}Query{{- else }}page, pageSize int{{- end }}) ([]*model.{{.ClassName}}, int64, error) {
+// GetList 获取{{.FunctionName}}列表（按数据权限过滤）
+func (r *{{.ClassName}}Repository) GetList(filter sharedModel.DataFilter, {{ if .HasQuery }}query *model.{{.ClassName}}Query{{- else }}page, pageSize int{{- end }}) ([]*model.{{.ClassName}}, int64, error) {
 	var {{pluralize (uncapitalize .BusinessName)}} []*model.{{.ClassName}}
 	var total int64
 
-	db := r.db.Model(&model.{{.ClassName}}{})
+	db := r.db.Model(&model.{{.ClassName}}{}).Scopes(database.DataScope(filter, {{uncapitalize .ClassName}}DataScopeColumns))
 
 {{- if .HasQuery }}
 	// 应用查询条件
@@ -5,6 +5,7 @@ import (
 
 	"github.com/LiteMove/light-stack/internal/model"
 	"github.com/LiteMove/light-stack/internal/repository"
+	sharedModel "github.com/LiteMove/light-stack/internal/shared/model"
 )
 
 // {{.ClassName}}Service {{.FunctionName}}服务
@@ -70,8 +71,8 @@ func (s *{{.ClassName}}Service) Delete(id uint64) error {
 }
 
 // GetList 获取{{.FunctionName}}列表
-func (s *{{.ClassName}}Service) GetList({{- if .HasQuery }}query *model.{{.ClassName}}Query{{- else }}page, pageSize int{{- end }}) ([]*model.{{.ClassName}}, int64, error) {
-	return s.repo.GetList({{- if .HasQuery }}query{{- else }}page, pageSize{{- end }})
+func (s *{{.ClassName}}Service) GetList(filter sharedModel.DataFilter, {{ if .HasQuery }}query *model.{{.ClassName}}Query{{- else }}page, pageSize int{{- end }}) ([]*model.{{.ClassName}}, int64, error) {
+	return s.repo.GetList(filter, {{ if .HasQuery }}query{{- else }}page, pageSize{{- end }})
 }
 
 // validate{{.ClassName}} 验证{{.FunctionName}}数据